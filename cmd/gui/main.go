@@ -1,10 +1,39 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"os"
+
+	"devpathpro/pkg/config"
+	"devpathpro/pkg/ui"
 	"devpathpro/pkg/ui/gui"
 )
 
 func main() {
-	app := gui.NewDevPathProGUI()
+	storeFlag := flag.String("store", "registry", "Where variables are read and written: registry, memory or file:PATH")
+	flag.Parse()
+
+	settingsPath := config.DefaultSettingsPath()
+	settings, err := config.LoadSettings(settingsPath)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	ui.ApplySettings(settings)
+
+	cfg, err := config.NewConfiguration(settings, settingsPath)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	store, err := config.OpenStore(*storeFlag, cfg.Programs)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	app := gui.NewDevPathProGUI(cfg, store)
 	app.Run()
-} 
\ No newline at end of file
+}
@@ -5,11 +5,14 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 
 	"devpathpro/pkg/config"
 	"devpathpro/pkg/registry"
+	"devpathpro/pkg/ui"
 	"devpathpro/pkg/ui/cli"
 	"devpathpro/pkg/ui/gui"
+	"devpathpro/pkg/utils"
 )
 
 // Initialize logging
@@ -25,29 +28,138 @@ func init() {
 func main() {
 	// Parse command line flags
 	cliMode := flag.Bool("cli", false, "Run in CLI mode instead of GUI")
+	settingsPath := flag.String("settings", config.DefaultSettingsPath(), "Settings file")
+	scopeFlag := flag.String("scope", "", "Where to write variables: user, machine or both (default: from settings, else per tool)")
+	backupDir := flag.String("backup-dir", "", "Backup directory (default: from settings, else per-user DevPathPro data directory)")
+	backupKeep := flag.Int("backup-keep", 0, "Number of backups to keep (0: keep all)")
+	backupMaxAge := flag.Duration("backup-max-age", 0, "Delete backups older than this, e.g. 720h (0: keep forever)")
+	backupZip := flag.Bool("backup-zip", false, "Bundle each backup into a single zip archive")
+	var selectFlags selectFlag
+	flag.Var(&selectFlags, "select", "Version constraint for a tool, e.g. \"Java=>=17 <22\" (repeatable)")
+	preferFlag := flag.String("prefer", "", "Installation preferences for all tools in priority order: newest, lts, 64bit")
+	preferRoot := flag.String("prefer-root", "", "Prefer installations under this directory")
+	storeFlag := flag.String("store", "registry", "Where variables are read and written: registry, memory or file:PATH")
 	flag.Parse()
 
-	// Check for administrator privileges
-	if !registry.IsAdmin() {
-		fmt.Println("Administrator privileges required")
-		fmt.Println("Please restart the program with administrator privileges")
+	settings, err := config.LoadSettings(*settingsPath)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	// Command line flags override the settings file for this run only,
+	// so they are not written back when the settings are saved
+	effective := *settings
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "scope":
+			effective.DefaultScope = registry.Scope(*scopeFlag)
+		case "backup-dir":
+			effective.BackupDir = *backupDir
+		case "backup-keep":
+			effective.BackupKeep = *backupKeep
+		case "backup-max-age":
+			effective.BackupMaxAge = backupMaxAge.String()
+		case "backup-zip":
+			effective.BackupCompress = *backupZip
+		}
+	})
+	if err := effective.Validate(); err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+	ui.ApplySettings(&effective)
+
+	var scope registry.Scope
+	if effective.DefaultScope != "" {
+		scope, _ = registry.ParseScope(string(effective.DefaultScope))
+	}
+
+	// Administrator privileges are only needed for machine scope writes
+	if scope.RequiresAdmin() && !registry.IsAdmin() {
+		fmt.Printf("Administrator privileges required for the %s scope\n", scope)
+		fmt.Println("Please restart the program with administrator privileges or use -scope user")
 		os.Exit(1)
 	}
 
 	// Initialize configuration
-	cfg := &config.Configuration{
-		LogFile:  "devpathpro.log",
-		Programs: config.GetDefaultPrograms(),
+	cfg, err := config.NewConfiguration(settings, *settingsPath)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	// Selection flags apply on top of the settings and catalog policies
+	policies := selectFlags.policies()
+	if *preferFlag != "" || *preferRoot != "" {
+		all := &config.SelectionPolicy{Root: *preferRoot}
+		for _, prefer := range strings.Split(*preferFlag, ",") {
+			if prefer = strings.TrimSpace(prefer); prefer != "" {
+				all.Prefer = append(all.Prefer, prefer)
+			}
+		}
+		policies["*"] = all
+	}
+	for name, policy := range policies {
+		if err := policy.Validate(); err != nil {
+			fmt.Printf("-select %s: %v\n", name, err)
+			os.Exit(2)
+		}
+	}
+	config.ApplySelection(cfg.Programs, policies)
+
+	// Environment changes are persisted in the Windows registry and
+	// copied to the configured shell startup files, unless another
+	// backend is chosen to try changes out
+	store, err := config.OpenStore(*storeFlag, cfg.Programs)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(2)
+	}
+
+	// Subcommands such as "plan" and "apply" run without a menu
+	if flag.NArg() > 0 {
+		os.Exit(cli.RunCommand(cfg, store, scope, flag.Args()))
 	}
 
 	// Run in CLI or GUI mode based on flag
 	if *cliMode {
+		// The menu reads its answers from the terminal; scripts use subcommands
+		if !utils.IsTerminal(os.Stdin) {
+			fmt.Println("The interactive CLI needs a terminal; run \"devpathpro help\" for scriptable commands")
+			os.Exit(2)
+		}
 		// CLI mode
-		cli := cli.NewCLI(cfg)
+		cli := cli.NewCLI(cfg, store, scope)
 		cli.Run()
 	} else {
 		// GUI mode (default)
-		gui := gui.NewGUI()
+		gui := gui.NewGUI(cfg, store, scope)
 		gui.Run()
 	}
 }
+
+// selectFlag collects repeated -select Tool=constraint flags
+type selectFlag []string
+
+func (f *selectFlag) String() string {
+	return strings.Join(*f, ", ")
+}
+
+func (f *selectFlag) Set(value string) error {
+	if name, constraint, ok := strings.Cut(value, "="); !ok || strings.TrimSpace(name) == "" || strings.TrimSpace(constraint) == "" {
+		return fmt.Errorf("expected Tool=constraint, e.g. \"Java=>=17 <22\"")
+	}
+	*f = append(*f, value)
+	return nil
+}
+
+// policies returns the constraints by tool name
+func (f selectFlag) policies() map[string]*config.SelectionPolicy {
+	policies := make(map[string]*config.SelectionPolicy)
+	for _, value := range f {
+		name, constraint, _ := strings.Cut(value, "=")
+		policies[strings.TrimSpace(name)] = &config.SelectionPolicy{Constraint: strings.TrimSpace(constraint)}
+	}
+	return policies
+}
@@ -5,113 +5,296 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"os/user"
 	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
 	"time"
+
+	"devpathpro/pkg/registry"
+	"devpathpro/pkg/utils"
 )
 
+// backupScopes are the persistent scopes captured by a backup
+var backupScopes = []registry.Scope{registry.ScopeMachine, registry.ScopeUser}
+
+// Metadata describes why and where a backup was created
+type Metadata struct {
+	// Action is what created the backup, e.g. "configure", "apply" or "manual"
+	Action string `json:"action,omitempty" yaml:"action,omitempty"`
+	// Tools are the tools being configured when the backup was taken
+	Tools []string       `json:"tools,omitempty" yaml:"tools,omitempty"`
+	Host  string         `json:"host,omitempty" yaml:"host,omitempty"`
+	User  string         `json:"user,omitempty" yaml:"user,omitempty"`
+	Scope registry.Scope `json:"scope,omitempty" yaml:"scope,omitempty"`
+}
+
+// Info identifies a backup in the catalog
+type Info struct {
+	ID        string    `json:"id" yaml:"id"`
+	Timestamp time.Time `json:"timestamp" yaml:"timestamp"`
+	// Compressed is set for backups bundled into a zip archive
+	Compressed bool `json:"compressed,omitempty" yaml:"compressed,omitempty"`
+	Metadata   `yaml:",inline"`
+}
+
+// String describes the backup in one line
+func (i Info) String() string {
+	desc := i.ID
+	if i.Action != "" {
+		desc += "  " + i.Action
+	}
+	if len(i.Tools) > 0 {
+		desc += " " + strings.Join(i.Tools, ", ")
+	}
+	if i.Scope != "" {
+		desc += fmt.Sprintf(" (%s)", i.Scope)
+	}
+	if i.User != "" || i.Host != "" {
+		desc += fmt.Sprintf("  %s@%s", i.User, i.Host)
+	}
+	return desc
+}
+
+// EnvironmentBackup is a snapshot of the persistent machine and user environment
 type EnvironmentBackup struct {
-	Timestamp time.Time          `json:"timestamp"`
-	Variables map[string]string `json:"variables"`
+	Timestamp time.Time         `json:"timestamp"`
+	Meta      Metadata          `json:"meta"`
+	Machine   map[string]string `json:"machine"`
+	User      map[string]string `json:"user"`
+	// Variables holds the process environment captured by older versions.
+	// It is kept for reading old files and is never restored.
+	Variables map[string]string `json:"variables,omitempty"`
 }
 
-// CreateBackup creates a backup of both registry and environment variables
-func CreateBackup() error {
-	backupDir := "backups"
-	if err := os.MkdirAll(backupDir, 0755); err != nil {
-		return fmt.Errorf("failed to create backup directory: %v", err)
+// vars returns the captured variables of a scope
+func (b *EnvironmentBackup) vars(scope registry.Scope) map[string]string {
+	switch scope {
+	case registry.ScopeMachine:
+		return b.Machine
+	case registry.ScopeUser:
+		return b.User
 	}
+	return nil
+}
 
-	timestamp := time.Now().Format("2006-01-02_15-04-05")
-	
-	// Backup registry
-	regFile := filepath.Join(backupDir, fmt.Sprintf("registry_%s.reg", timestamp))
-	cmd := exec.Command("reg", "export", "HKLM\\SYSTEM\\CurrentControlSet\\Control\\Session Manager\\Environment", regFile)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to backup registry: %v", err)
+// RestoreOptions limits what RestoreBackup writes back.
+// The zero value restores every variable of both scopes.
+type RestoreOptions struct {
+	// Scope restricts the restore to the machine or the user scope
+	Scope registry.Scope
+	// Variable restricts the restore to a single variable
+	Variable string
+	// PathOnly restricts the restore to the PATH variable
+	PathOnly bool
+}
+
+// CreateBackup saves the machine and user environment from the store together
+// with meta. Host and user are filled in when empty. On Windows the machine key
+// is also exported as a .reg file for manual recovery. Old backups are pruned
+// according to the configured retention.
+func CreateBackup(store registry.EnvStore, meta Metadata) (Info, error) {
+	if err := os.MkdirAll(backupDir(), 0755); err != nil {
+		return Info{}, fmt.Errorf("failed to create backup directory: %v", err)
 	}
 
-	// Backup environment variables
-	envVars := make(map[string]string)
-	for _, env := range os.Environ() {
-		for i := 0; i < len(env); i++ {
-			if env[i] == '=' {
-				envVars[env[:i]] = env[i+1:]
-				break
-			}
+	now := time.Now()
+	timestamp := now.Format("2006-01-02_15-04-05")
+	// Keep backups taken within the same second apart
+	for n := 2; backupExists(timestamp); n++ {
+		timestamp = fmt.Sprintf("%s_%d", now.Format("2006-01-02_15-04-05"), n)
+	}
+
+	if meta.Host == "" {
+		meta.Host, _ = os.Hostname()
+	}
+	if meta.User == "" {
+		if u, err := user.Current(); err == nil {
+			meta.User = u.Username
 		}
 	}
 
 	backup := EnvironmentBackup{
-		Timestamp: time.Now(),
-		Variables: envVars,
+		Timestamp: now,
+		Meta:      meta,
+	}
+	var err error
+	if backup.Machine, err = store.List(registry.ScopeMachine); err != nil {
+		return Info{}, fmt.Errorf("failed to read machine environment: %v", err)
+	}
+	if backup.User, err = store.List(registry.ScopeUser); err != nil {
+		return Info{}, fmt.Errorf("failed to read user environment: %v", err)
 	}
 
-	envFile := filepath.Join(backupDir, fmt.Sprintf("env_%s.json", timestamp))
-	f, err := os.Create(envFile)
+	envData, err := json.MarshalIndent(backup, "", "  ")
 	if err != nil {
-		return fmt.Errorf("failed to create environment backup file: %v", err)
+		return Info{}, fmt.Errorf("failed to write environment backup: %v", err)
+	}
+	files := []backupFile{{name: envName(timestamp), data: envData}}
+
+	// The structured backup is what gets restored; the .reg export is only a convenience
+	if runtime.GOOS == "windows" {
+		if regData, err := exportRegistry(); err != nil {
+			utils.Warnf("registry export for backup %s failed: %v", timestamp, err)
+		} else {
+			files = append(files, backupFile{name: regName(timestamp), data: regData})
+		}
 	}
-	defer f.Close()
 
-	encoder := json.NewEncoder(f)
-	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(backup); err != nil {
-		return fmt.Errorf("failed to write environment backup: %v", err)
+	if err := writeBackup(timestamp, now, files, options.Compress); err != nil {
+		return Info{}, err
 	}
 
-	return nil
+	if _, err := Prune(); err != nil {
+		utils.Warnf("pruning old backups failed: %v", err)
+	}
+
+	return Info{ID: timestamp, Timestamp: now, Compressed: options.Compress, Metadata: meta}, nil
 }
 
-// RestoreBackup restores environment from a specific backup
-func RestoreBackup(timestamp string) error {
-	backupDir := "backups"
-	
-	// Restore registry
-	regFile := filepath.Join(backupDir, fmt.Sprintf("registry_%s.reg", timestamp))
-	cmd := exec.Command("reg", "import", regFile)
+// exportRegistry returns the machine environment key in .reg format
+func exportRegistry() ([]byte, error) {
+	tmp, err := os.CreateTemp("", "devpathpro-*.reg")
+	if err != nil {
+		return nil, err
+	}
+	tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	cmd := exec.Command("reg", "export", "HKLM\\SYSTEM\\CurrentControlSet\\Control\\Session Manager\\Environment", tmp.Name(), "/y")
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to restore registry: %v", err)
+		return nil, err
 	}
+	return os.ReadFile(tmp.Name())
+}
 
-	// Restore environment variables
-	envFile := filepath.Join(backupDir, fmt.Sprintf("env_%s.json", timestamp))
-	f, err := os.Open(envFile)
+// LoadBackup reads the backup created at timestamp after verifying its checksums
+func LoadBackup(timestamp string) (*EnvironmentBackup, error) {
+	files, err := readBackupFiles(timestamp, true)
+	if err != nil {
+		return nil, err
+	}
+	backup, err := parseBackup(files[envName(timestamp)])
 	if err != nil {
-		return fmt.Errorf("failed to open environment backup file: %v", err)
+		return nil, err
+	}
+	if backup.Machine == nil && backup.User == nil {
+		return nil, fmt.Errorf("backup %s was created by an older version and has no machine or user variables", timestamp)
 	}
-	defer f.Close()
+	return backup, nil
+}
 
+// parseBackup decodes the environment file of a backup
+func parseBackup(data []byte) (*EnvironmentBackup, error) {
+	if data == nil {
+		return nil, fmt.Errorf("backup has no environment file")
+	}
 	var backup EnvironmentBackup
-	if err := json.NewDecoder(f).Decode(&backup); err != nil {
-		return fmt.Errorf("failed to read environment backup: %v", err)
+	if err := json.Unmarshal(data, &backup); err != nil {
+		return nil, fmt.Errorf("failed to read environment backup: %v", err)
+	}
+	return &backup, nil
+}
+
+// PreviewRestore returns the changes restoring a backup would make to the store
+func PreviewRestore(store registry.EnvStore, timestamp string, opts RestoreOptions) ([]Change, error) {
+	backup, err := LoadBackup(timestamp)
+	if err != nil {
+		return nil, err
+	}
+	return restoreChanges(store, backup, opts)
+}
+
+// RestoreBackup writes the variables of a backup back to the store. Variables
+// created after the backup are deleted. All writes happen in one transaction,
+// so a failure leaves the environment unchanged.
+func RestoreBackup(store registry.EnvStore, timestamp string, opts RestoreOptions) error {
+	changes, err := PreviewRestore(store, timestamp, opts)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		return nil
 	}
 
-	// Apply environment variables
-	for key, value := range backup.Variables {
-		if err := os.Setenv(key, value); err != nil {
-			return fmt.Errorf("failed to restore environment variable %s: %v", key, err)
+	tx, err := Begin(store)
+	if err != nil {
+		return err
+	}
+	for _, c := range changes {
+		if c.NewExists {
+			err = tx.Set(c.Scope, c.Name, c.New)
+		} else {
+			err = tx.Delete(c.Scope, c.Name)
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to restore %s %s: %v", c.Scope, c.Name, err)
+			if rbErr := tx.Rollback(); rbErr != nil {
+				return fmt.Errorf("%v; %v", err, rbErr)
+			}
+			return err
 		}
 	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
 
+	registry.NotifyEnvironmentChange()
 	return nil
 }
 
-// ListBackups returns a list of available backups
-func ListBackups() ([]string, error) {
-	backupDir := "backups"
-	entries, err := os.ReadDir(backupDir)
+// restoreChanges compares a backup with the store, limited by opts
+func restoreChanges(store registry.EnvStore, backup *EnvironmentBackup, opts RestoreOptions) ([]Change, error) {
+	scopes := backupScopes
+	if opts.Scope != "" {
+		if opts.Scope != registry.ScopeMachine && opts.Scope != registry.ScopeUser {
+			return nil, fmt.Errorf("can only restore the machine or user scope, not %q", opts.Scope)
+		}
+		scopes = []registry.Scope{opts.Scope}
+	}
+
+	variable := opts.Variable
+	if opts.PathOnly {
+		variable = registry.PathVariable
+	}
+
+	var changes []Change
+	for _, scope := range scopes {
+		current, err := store.List(scope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s environment: %v", scope, err)
+		}
+		changes = append(changes, diffVars(scope, current, backup.vars(scope), variable)...)
+	}
+	return changes, nil
+}
+
+// ListBackups returns the available backups, oldest first
+func ListBackups() ([]Info, error) {
+	ids, err := listBackupIDs()
 	if err != nil {
-		return nil, fmt.Errorf("failed to read backup directory: %v", err)
+		return nil, err
 	}
 
-	var timestamps []string
-	for _, entry := range entries {
-		if !entry.IsDir() && filepath.Ext(entry.Name()) == ".reg" {
-			// Extract timestamp from filename (registry_2006-01-02_15-04-05.reg)
-			timestamp := entry.Name()[9 : len(entry.Name())-4]
-			timestamps = append(timestamps, timestamp)
+	var backups []Info
+	for _, id := range ids {
+		info := Info{ID: id}
+		if _, err := os.Stat(filepath.Join(backupDir(), archiveName(id))); err == nil {
+			info.Compressed = true
+		}
+		// Metadata is best effort; corrupted backups are still listed
+		if files, err := readBackupFiles(id, false); err == nil {
+			if backup, err := parseBackup(files[envName(id)]); err == nil {
+				info.Timestamp = backup.Timestamp
+				info.Metadata = backup.Meta
+			}
 		}
+		backups = append(backups, info)
 	}
 
-	return timestamps, nil
-} 
\ No newline at end of file
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Timestamp.Before(backups[j].Timestamp)
+	})
+	return backups, nil
+}
@@ -0,0 +1,195 @@
+package backup
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"devpathpro/pkg/registry"
+)
+
+// Current names the live environment when comparing backups
+const Current = "current"
+
+// Change is the difference of one variable between two environments.
+// For restores Old is the current value and New the value in the backup;
+// for comparisons Old comes from the first environment and New from the second.
+type Change struct {
+	Scope                registry.Scope
+	Name                 string
+	Old, New             string
+	OldExists, NewExists bool
+}
+
+// PathEdit is one line of an ordered PATH comparison
+type PathEdit struct {
+	// Op is "+" for an added entry, "-" for a removed one and " " for an unchanged one
+	Op    string
+	Entry string
+}
+
+// Compare returns the variables that differ between two backups. Either ID may
+// be Current to compare against the environment in the store.
+func Compare(store registry.EnvStore, from, to string) ([]Change, error) {
+	fromEnv, err := loadEnvironment(store, from)
+	if err != nil {
+		return nil, err
+	}
+	toEnv, err := loadEnvironment(store, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []Change
+	for _, scope := range backupScopes {
+		changes = append(changes, diffVars(scope, fromEnv[scope], toEnv[scope], "")...)
+	}
+	return changes, nil
+}
+
+// loadEnvironment returns the variables of a backup, or of the store for Current
+func loadEnvironment(store registry.EnvStore, id string) (map[registry.Scope]map[string]string, error) {
+	env := make(map[registry.Scope]map[string]string)
+	if id == Current {
+		for _, scope := range backupScopes {
+			vars, err := store.List(scope)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s environment: %v", scope, err)
+			}
+			env[scope] = vars
+		}
+		return env, nil
+	}
+
+	backup, err := LoadBackup(id)
+	if err != nil {
+		return nil, err
+	}
+	for _, scope := range backupScopes {
+		env[scope] = backup.vars(scope)
+	}
+	return env, nil
+}
+
+// diffVars returns the changes turning from into to, sorted by name.
+// Names are compared case-insensitively. If only is set, other variables are ignored.
+func diffVars(scope registry.Scope, from, to map[string]string, only string) []Change {
+	type pair struct {
+		name                 string
+		old, new             string
+		oldExists, newExists bool
+	}
+	pairs := make(map[string]*pair)
+	get := func(name string) *pair {
+		key := strings.ToLower(name)
+		if pairs[key] == nil {
+			pairs[key] = &pair{name: name}
+		}
+		return pairs[key]
+	}
+	for name, value := range from {
+		p := get(name)
+		p.old, p.oldExists = value, true
+	}
+	for name, value := range to {
+		p := get(name)
+		p.new, p.newExists = value, true
+	}
+
+	var changes []Change
+	for key, p := range pairs {
+		if only != "" && key != strings.ToLower(only) {
+			continue
+		}
+		if p.oldExists == p.newExists && p.old == p.new {
+			continue
+		}
+		changes = append(changes, Change{
+			Scope:     scope,
+			Name:      p.name,
+			Old:       p.old,
+			New:       p.new,
+			OldExists: p.oldExists,
+			NewExists: p.newExists,
+		})
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return strings.ToLower(changes[i].Name) < strings.ToLower(changes[j].Name)
+	})
+	return changes
+}
+
+// DiffPathList compares two PATH values entry by entry, keeping the order of
+// both. Entries that moved show up as removed at the old position and added
+// at the new one.
+func DiffPathList(old, new string) []PathEdit {
+	a, b := registry.SplitPathList(old), registry.SplitPathList(new)
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if registry.SamePath(a[i], b[j]) {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var edits []PathEdit
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case registry.SamePath(a[i], b[j]):
+			edits = append(edits, PathEdit{Op: " ", Entry: b[j]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			edits = append(edits, PathEdit{Op: "-", Entry: a[i]})
+			i++
+		default:
+			edits = append(edits, PathEdit{Op: "+", Entry: b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		edits = append(edits, PathEdit{Op: "-", Entry: a[i]})
+	}
+	for ; j < len(b); j++ {
+		edits = append(edits, PathEdit{Op: "+", Entry: b[j]})
+	}
+	return edits
+}
+
+// FormatChanges describes changes one per line: + for added variables,
+// - for removed ones and ~ for modified ones. Modified PATH values are
+// listed entry by entry in order.
+func FormatChanges(changes []Change) string {
+	if len(changes) == 0 {
+		return "No changes.\n"
+	}
+
+	var sb strings.Builder
+	for _, c := range changes {
+		switch {
+		case !c.OldExists:
+			fmt.Fprintf(&sb, "+ [%s] %s = %s\n", c.Scope, c.Name, c.New)
+		case !c.NewExists:
+			fmt.Fprintf(&sb, "- [%s] %s (was %s)\n", c.Scope, c.Name, c.Old)
+		case strings.EqualFold(c.Name, registry.PathVariable):
+			fmt.Fprintf(&sb, "~ [%s] %s:\n", c.Scope, c.Name)
+			for _, edit := range DiffPathList(c.Old, c.New) {
+				fmt.Fprintf(&sb, "    %s %s\n", edit.Op, edit.Entry)
+			}
+		default:
+			fmt.Fprintf(&sb, "~ [%s] %s: %s -> %s\n", c.Scope, c.Name, c.Old, c.New)
+		}
+	}
+	return sb.String()
+}
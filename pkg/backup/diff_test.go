@@ -0,0 +1,128 @@
+package backup
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"devpathpro/pkg/registry"
+)
+
+func TestDiffVars(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to map[string]string
+		only     string
+		want     []Change
+	}{
+		{
+			name: "unchanged",
+			from: map[string]string{"GOPATH": "/go"},
+			to:   map[string]string{"GOPATH": "/go"},
+		},
+		{
+			name: "added, removed and modified",
+			from: map[string]string{"JAVA_HOME": "/jdk17", "OLD": "x"},
+			to:   map[string]string{"JAVA_HOME": "/jdk21", "NEW": "y"},
+			want: []Change{
+				{Scope: registry.ScopeUser, Name: "JAVA_HOME", Old: "/jdk17", New: "/jdk21", OldExists: true, NewExists: true},
+				{Scope: registry.ScopeUser, Name: "NEW", New: "y", NewExists: true},
+				{Scope: registry.ScopeUser, Name: "OLD", Old: "x", OldExists: true},
+			},
+		},
+		{
+			name: "names compare case-insensitively",
+			from: map[string]string{"Path": "/a"},
+			to:   map[string]string{"PATH": "/a"},
+		},
+		{
+			name: "empty value differs from a missing one",
+			from: map[string]string{},
+			to:   map[string]string{"EMPTY": ""},
+			want: []Change{{Scope: registry.ScopeUser, Name: "EMPTY", NewExists: true}},
+		},
+		{
+			name: "only one variable",
+			from: map[string]string{"A": "1", "B": "1"},
+			to:   map[string]string{"A": "2", "B": "2"},
+			only: "b",
+			want: []Change{{Scope: registry.ScopeUser, Name: "B", Old: "1", New: "2", OldExists: true, NewExists: true}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffVars(registry.ScopeUser, tt.from, tt.to, tt.only)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffVars() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffPathList(t *testing.T) {
+	join := registry.JoinPathList
+	tests := []struct {
+		name     string
+		old, new []string
+		want     string
+	}{
+		{"unchanged", []string{"/a", "/b"}, []string{"/a", "/b"}, " /a  /b"},
+		{"appended", []string{"/a"}, []string{"/a", "/b"}, " /a +/b"},
+		{"removed", []string{"/a", "/b", "/c"}, []string{"/a", "/c"}, " /a -/b  /c"},
+		{"moved to front", []string{"/a", "/b"}, []string{"/b", "/a"}, "-/a  /b +/a"},
+		{"from empty", nil, []string{"/a"}, "+/a"},
+		{"to empty", []string{"/a"}, nil, "-/a"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, edit := range DiffPathList(join(tt.old), join(tt.new)) {
+				got = append(got, edit.Op+edit.Entry)
+			}
+			if s := strings.Join(got, " "); s != tt.want {
+				t.Errorf("DiffPathList() = %q, want %q", s, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatChanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes []Change
+		want    string
+	}{
+		{"no changes", nil, "No changes.\n"},
+		{
+			"added",
+			[]Change{{Scope: registry.ScopeUser, Name: "GOPATH", New: "/go", NewExists: true}},
+			"+ [user] GOPATH = /go\n",
+		},
+		{
+			"removed",
+			[]Change{{Scope: registry.ScopeMachine, Name: "OLD", Old: "x", OldExists: true}},
+			"- [machine] OLD (was x)\n",
+		},
+		{
+			"modified",
+			[]Change{{Scope: registry.ScopeUser, Name: "JAVA_HOME", Old: "/jdk17", New: "/jdk21", OldExists: true, NewExists: true}},
+			"~ [user] JAVA_HOME: /jdk17 -> /jdk21\n",
+		},
+		{
+			"modified PATH",
+			[]Change{{
+				Scope: registry.ScopeUser, Name: registry.PathVariable,
+				Old: registry.JoinPathList([]string{"/a"}), New: registry.JoinPathList([]string{"/a", "/b"}),
+				OldExists: true, NewExists: true,
+			}},
+			"~ [user] " + registry.PathVariable + ":\n      /a\n    + /b\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatChanges(tt.changes); got != tt.want {
+				t.Errorf("FormatChanges() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
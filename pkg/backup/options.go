@@ -0,0 +1,50 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Options controls where backups are stored and how long they are kept
+type Options struct {
+	// Dir is the backup root; empty means DefaultDir
+	Dir string
+	// MaxCount is the number of backups to keep; 0 keeps all
+	MaxCount int
+	// MaxAge is how long backups and finished transaction journals are kept; 0 keeps them forever
+	MaxAge time.Duration
+	// Compress bundles each backup into a single zip archive
+	Compress bool
+}
+
+// options are the settings used by the package functions
+var options = Options{Dir: DefaultDir()}
+
+// Configure replaces the backup settings used by the package
+func Configure(opts Options) {
+	if opts.Dir == "" {
+		opts.Dir = DefaultDir()
+	}
+	options = opts
+}
+
+// CurrentOptions returns the backup settings in use
+func CurrentOptions() Options {
+	return options
+}
+
+// DefaultDir returns the per-user backup root, falling back to
+// a "backups" directory next to the working directory
+func DefaultDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "backups"
+	}
+	return filepath.Join(dir, "DevPathPro", "backups")
+}
+
+// backupDir is where backups and transaction journals are stored
+func backupDir() string {
+	return options.Dir
+}
@@ -0,0 +1,268 @@
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// manifestVersion is the format version written to manifests
+const manifestVersion = 1
+
+// archiveManifest is the name of the manifest inside a zip archive
+const archiveManifest = "manifest.json"
+
+// ManifestFile is a file of a backup with its checksum
+type ManifestFile struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Manifest lists the files of a backup. Their checksums are verified
+// before a backup is read.
+type Manifest struct {
+	Version int            `json:"version"`
+	ID      string         `json:"id"`
+	Created time.Time      `json:"created"`
+	Files   []ManifestFile `json:"files"`
+}
+
+// backupFile is a file to be stored in a backup
+type backupFile struct {
+	name string
+	data []byte
+}
+
+func envName(id string) string      { return fmt.Sprintf("env_%s.json", id) }
+func regName(id string) string      { return fmt.Sprintf("registry_%s.reg", id) }
+func manifestName(id string) string { return fmt.Sprintf("manifest_%s.json", id) }
+func archiveName(id string) string  { return fmt.Sprintf("backup_%s.zip", id) }
+
+// backupExists reports whether a backup with the given ID is stored
+func backupExists(id string) bool {
+	for _, name := range []string{envName(id), archiveName(id)} {
+		if _, err := os.Stat(filepath.Join(backupDir(), name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// writeBackup stores the files of a backup together with a manifest, either
+// side by side in the backup directory or bundled into one zip archive
+func writeBackup(id string, created time.Time, files []backupFile, compress bool) error {
+	manifest := Manifest{Version: manifestVersion, ID: id, Created: created}
+	for _, f := range files {
+		sum := sha256.Sum256(f.data)
+		manifest.Files = append(manifest.Files, ManifestFile{
+			Name:   f.name,
+			Size:   int64(len(f.data)),
+			SHA256: hex.EncodeToString(sum[:]),
+		})
+	}
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode backup manifest: %v", err)
+	}
+
+	if !compress {
+		for _, f := range files {
+			if err := os.WriteFile(filepath.Join(backupDir(), f.name), f.data, 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %v", f.name, err)
+			}
+		}
+		if err := os.WriteFile(filepath.Join(backupDir(), manifestName(id)), manifestData, 0644); err != nil {
+			return fmt.Errorf("failed to write backup manifest: %v", err)
+		}
+		return nil
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range append(files, backupFile{name: archiveManifest, data: manifestData}) {
+		w, err := zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: created})
+		if err != nil {
+			return fmt.Errorf("failed to add %s to backup archive: %v", f.name, err)
+		}
+		if _, err := w.Write(f.data); err != nil {
+			return fmt.Errorf("failed to add %s to backup archive: %v", f.name, err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("failed to write backup archive: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(backupDir(), archiveName(id)), buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write backup archive: %v", err)
+	}
+	return nil
+}
+
+// readBackupFiles returns the files of a backup by name. With verify set, every
+// file is checked against the manifest and a mismatch is an error. Backups made
+// before manifests existed are read without verification.
+func readBackupFiles(id string, verify bool) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	var manifestData []byte
+
+	archive := filepath.Join(backupDir(), archiveName(id))
+	if zr, err := zip.OpenReader(archive); err == nil {
+		defer zr.Close()
+		for _, zf := range zr.File {
+			rc, err := zf.Open()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s from backup archive: %v", zf.Name, err)
+			}
+			data, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s from backup archive: %v", zf.Name, err)
+			}
+			files[zf.Name] = data
+		}
+		manifestData = files[archiveManifest]
+		delete(files, archiveManifest)
+		if manifestData == nil {
+			return nil, fmt.Errorf("backup archive %s has no manifest", archive)
+		}
+	} else {
+		manifestData, err = os.ReadFile(filepath.Join(backupDir(), manifestName(id)))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read backup manifest: %v", err)
+		}
+		if manifestData == nil {
+			// Backups from older versions only have the environment file
+			data, err := os.ReadFile(filepath.Join(backupDir(), envName(id)))
+			if err != nil {
+				return nil, fmt.Errorf("failed to open environment backup file: %v", err)
+			}
+			files[envName(id)] = data
+			return files, nil
+		}
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse backup manifest: %v", err)
+	}
+	for _, mf := range manifest.Files {
+		data, ok := files[mf.Name]
+		if !ok {
+			if data, err := os.ReadFile(filepath.Join(backupDir(), mf.Name)); err == nil {
+				files[mf.Name], ok = data, true
+			}
+		}
+		if !verify {
+			continue
+		}
+		if !ok {
+			return nil, fmt.Errorf("backup %s is incomplete: %s is missing", id, mf.Name)
+		}
+		if sum := sha256.Sum256(data); hex.EncodeToString(sum[:]) != mf.SHA256 {
+			return nil, fmt.Errorf("backup %s is corrupted: checksum of %s does not match", id, mf.Name)
+		}
+	}
+	return files, nil
+}
+
+// listBackupIDs returns the IDs of all stored backups
+func listBackupIDs() ([]string, error) {
+	entries, err := os.ReadDir(backupDir())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup directory: %v", err)
+	}
+
+	var ids []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() {
+			continue
+		}
+		// The ID is the timestamp in the filename (env_2006-01-02_15-04-05.json
+		// or backup_2006-01-02_15-04-05.zip)
+		switch {
+		case strings.HasPrefix(name, "env_") && strings.HasSuffix(name, ".json"):
+			ids = append(ids, strings.TrimSuffix(strings.TrimPrefix(name, "env_"), ".json"))
+		case strings.HasPrefix(name, "backup_") && strings.HasSuffix(name, ".zip"):
+			ids = append(ids, strings.TrimSuffix(strings.TrimPrefix(name, "backup_"), ".zip"))
+		}
+	}
+	return ids, nil
+}
+
+// DeleteBackup removes every file of a backup
+func DeleteBackup(id string) error {
+	found := false
+	for _, name := range []string{envName(id), regName(id), manifestName(id), archiveName(id)} {
+		err := os.Remove(filepath.Join(backupDir(), name))
+		if err == nil {
+			found = true
+		} else if !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete backup %s: %v", id, err)
+		}
+	}
+	if !found {
+		return fmt.Errorf("backup %s not found", id)
+	}
+	return nil
+}
+
+// Prune deletes the oldest backups beyond the configured count, backups and
+// finished transaction journals older than the configured age, and returns
+// the IDs of the deleted backups
+func Prune() ([]string, error) {
+	backups, err := ListBackups()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var removed []string
+	for i, info := range backups {
+		tooMany := options.MaxCount > 0 && len(backups)-i > options.MaxCount
+		tooOld := options.MaxAge > 0 && !info.Timestamp.IsZero() && now.Sub(info.Timestamp) > options.MaxAge
+		if !tooMany && !tooOld {
+			continue
+		}
+		if err := DeleteBackup(info.ID); err != nil {
+			return removed, err
+		}
+		removed = append(removed, info.ID)
+	}
+
+	if options.MaxAge > 0 {
+		pruneTransactions(now.Add(-options.MaxAge))
+	}
+	return removed, nil
+}
+
+// pruneTransactions removes finished transaction journals started before cutoff.
+// Pending journals are kept so an interrupted transaction can still be rolled back.
+func pruneTransactions(cutoff time.Time) {
+	entries, err := os.ReadDir(transactionDir())
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		path := filepath.Join(transactionDir(), entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var tx Transaction
+		if json.Unmarshal(data, &tx) != nil || tx.Status == StatusPending {
+			continue
+		}
+		if tx.Started.Before(cutoff) {
+			os.Remove(path)
+		}
+	}
+}
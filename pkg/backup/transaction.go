@@ -0,0 +1,216 @@
+package backup
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"devpathpro/pkg/registry"
+)
+
+// Transaction states recorded in the journal
+const (
+	StatusPending        = "pending"
+	StatusCommitted      = "committed"
+	StatusRolledBack     = "rolled_back"
+	StatusRollbackFailed = "rollback_failed"
+)
+
+// Entry is the value a variable had before a transaction first changed it
+type Entry struct {
+	Scope registry.Scope `json:"scope"`
+	Name  string         `json:"name"`
+	Value string         `json:"value,omitempty"`
+	// Existed is false if the variable did not exist; rolling back deletes it
+	Existed bool `json:"existed"`
+}
+
+// Transaction records the prior value of every variable it changes, so a
+// partially applied set of changes can be undone. Unlike CreateBackup it only
+// captures what it touches. The journal is written to disk before each change,
+// which keeps it usable even if the process dies halfway.
+type Transaction struct {
+	ID          string    `json:"id"`
+	Started     time.Time `json:"started"`
+	Status      string    `json:"status"`
+	Entries     []Entry   `json:"entries"`
+	CreatedDirs []string  `json:"createdDirs,omitempty"`
+
+	store registry.EnvStore
+}
+
+// transactionDir is where transaction journals are kept
+func transactionDir() string {
+	return filepath.Join(backupDir(), "transactions")
+}
+
+// Begin starts a transaction writing to store
+func Begin(store registry.EnvStore) (*Transaction, error) {
+	now := time.Now()
+	tx := &Transaction{
+		ID:      fmt.Sprintf("%s_%06d", now.Format("2006-01-02_15-04-05"), now.Nanosecond()/1000),
+		Started: now,
+		Status:  StatusPending,
+		store:   store,
+	}
+	if err := os.MkdirAll(transactionDir(), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create transaction directory: %v", err)
+	}
+	if err := tx.save(); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// LoadTransaction reads a transaction journal so it can be inspected or rolled back
+func LoadTransaction(store registry.EnvStore, id string) (*Transaction, error) {
+	data, err := os.ReadFile(filepath.Join(transactionDir(), id+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read transaction %s: %v", id, err)
+	}
+	var tx Transaction
+	if err := json.Unmarshal(data, &tx); err != nil {
+		return nil, fmt.Errorf("failed to parse transaction %s: %v", id, err)
+	}
+	tx.store = store
+	return &tx, nil
+}
+
+// Path returns the location of the transaction journal
+func (t *Transaction) Path() string {
+	return filepath.Join(transactionDir(), t.ID+".json")
+}
+
+// Set records the current value of a variable, if it was not recorded yet,
+// and then overwrites it
+func (t *Transaction) Set(scope registry.Scope, name, value string) error {
+	if err := t.snapshot(scope, name); err != nil {
+		return err
+	}
+	return t.store.Set(scope, name, value)
+}
+
+// Delete records the current value of a variable, if it was not recorded yet,
+// and then removes it
+func (t *Transaction) Delete(scope registry.Scope, name string) error {
+	if err := t.snapshot(scope, name); err != nil {
+		return err
+	}
+	return t.store.Delete(scope, name)
+}
+
+// MkdirAll creates dir and its missing parents, recording every directory
+// it creates so that rolling back can remove them again
+func (t *Transaction) MkdirAll(dir string) error {
+	var missing []string
+	for d := filepath.Clean(dir); ; d = filepath.Dir(d) {
+		if _, err := os.Stat(d); err == nil {
+			break
+		}
+		missing = append(missing, d)
+		if filepath.Dir(d) == d {
+			break
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	// Record parents first, so rolling back in reverse removes children first
+	for i := len(missing) - 1; i >= 0; i-- {
+		t.CreatedDirs = append(t.CreatedDirs, missing[i])
+	}
+	if err := t.save(); err != nil {
+		return err
+	}
+	return os.MkdirAll(dir, 0755)
+}
+
+// Commit marks the transaction as completed and syncs the store. The changes
+// are already in the store, so it is synced even if the journal cannot be
+// updated; that error is still returned.
+func (t *Transaction) Commit() error {
+	t.Status = StatusCommitted
+	err := t.save()
+	t.sync()
+	return err
+}
+
+// Rollback restores every recorded variable to its prior value, deleting the
+// ones that did not exist, syncs the store and removes the directories the
+// transaction created. Rollback keeps going after errors and reports all of them.
+func (t *Transaction) Rollback() error {
+	var errs []string
+	for i := len(t.Entries) - 1; i >= 0; i-- {
+		entry := t.Entries[i]
+		var err error
+		if entry.Existed {
+			err = t.store.Set(entry.Scope, entry.Name, entry.Value)
+		} else {
+			err = t.store.Delete(entry.Scope, entry.Name)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s %s: %v", entry.Scope, entry.Name, err))
+		}
+	}
+	t.sync()
+
+	for i := len(t.CreatedDirs) - 1; i >= 0; i-- {
+		// Remove fails on directories that are no longer empty,
+		// which must not be deleted anyway
+		os.Remove(t.CreatedDirs[i])
+	}
+
+	t.Status = StatusRolledBack
+	if len(errs) > 0 {
+		t.Status = StatusRollbackFailed
+	}
+	if err := t.save(); err != nil {
+		errs = append(errs, err.Error())
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("rollback failed (journal %s): %s", t.Path(), strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// snapshot records the current value of a variable the first time it is touched
+// and persists the journal before the caller changes anything
+func (t *Transaction) snapshot(scope registry.Scope, name string) error {
+	for _, entry := range t.Entries {
+		if entry.Scope == scope && strings.EqualFold(entry.Name, name) {
+			return nil
+		}
+	}
+
+	value, exists, err := t.store.Get(scope, name)
+	if err != nil {
+		return fmt.Errorf("failed to read %s %s before changing it: %v", scope, name, err)
+	}
+	t.Entries = append(t.Entries, Entry{Scope: scope, Name: name, Value: value, Existed: exists})
+	return t.save()
+}
+
+// sync lets a store that copies its variables elsewhere do so once for
+// all the changes of the transaction, see registry.Syncer
+func (t *Transaction) sync() {
+	if syncer, ok := t.store.(registry.Syncer); ok {
+		syncer.Sync()
+	}
+}
+
+// save writes the journal to disk
+func (t *Transaction) save() error {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode transaction: %v", err)
+	}
+	if err := os.WriteFile(t.Path(), data, 0644); err != nil {
+		return fmt.Errorf("failed to write transaction journal: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package backup
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"devpathpro/pkg/registry"
+)
+
+// syncStore counts the syncs of a memory store
+type syncStore struct {
+	*registry.MemoryStore
+	syncs int
+}
+
+func (s *syncStore) Sync() { s.syncs++ }
+
+func TestCommitSyncsWhenJournalFails(t *testing.T) {
+	Configure(Options{Dir: t.TempDir()})
+	t.Cleanup(func() { Configure(Options{}) })
+
+	store := &syncStore{MemoryStore: registry.NewMemoryStore()}
+	tx, err := Begin(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Set(registry.ScopeUser, "GOPATH", "/go"); err != nil {
+		t.Fatal(err)
+	}
+	// The journal can no longer be written once its directory is gone
+	if err := os.RemoveAll(filepath.Dir(tx.Path())); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := tx.Commit(); err == nil {
+		t.Error("Commit succeeded without writing the journal")
+	}
+	if store.syncs != 1 {
+		t.Errorf("store synced %d times, want 1", store.syncs)
+	}
+}
@@ -0,0 +1,331 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"text/template"
+
+	"devpathpro/pkg/registry"
+)
+
+// CatalogVersion is the catalog file format version
+const CatalogVersion = 1
+
+// Catalog is a file of program definitions merged over the built-in programs
+type Catalog struct {
+	Version  int       `json:"version"`
+	Programs []Program `json:"programs"`
+	// Selection sets selection policies of any program by name, so a team can
+	// share them without redefining built-in programs
+	Selection map[string]*SelectionPolicy `json:"selection,omitempty"`
+}
+
+// Recipe describes the variables and PATH entries that configure a program.
+// Values are Go templates; see TemplateData for what they can refer to.
+type Recipe struct {
+	// InstallDir overrides the installation directory, which defaults to the
+	// parent of BinDir if BinDir is named "bin", otherwise BinDir itself
+	InstallDir string `json:"installDir,omitempty"`
+	// Variables maps variable names to templated values
+	Variables map[string]string `json:"variables,omitempty"`
+	// Options group variables so they can be selected together;
+	// with no selection every variable is set
+	Options []OptionGroup `json:"options,omitempty"`
+	// Path lists templated directories appended to PATH after BinDir;
+	// entries that expand to nothing are skipped
+	Path []string `json:"path,omitempty"`
+	// CreateDirs lists templated directories created before PATH is changed,
+	// skipping those that expand to nothing
+	CreateDirs []string `json:"createDirs,omitempty"`
+	// WindowsOnly lists variables that are only set on Windows, such as ones
+	// pointing into the Windows layout of an installation
+	WindowsOnly []string `json:"windowsOnly,omitempty"`
+}
+
+// OptionGroup is a named set of recipe variables
+type OptionGroup struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Variables   []string `json:"variables"`
+}
+
+// TemplateData is what recipe templates are executed with
+type TemplateData struct {
+	// Executable is the full path of the program's executable
+	Executable string
+	// BinDir is the directory containing the executable
+	BinDir string
+	// InstallDir is the program's installation directory
+	InstallDir string
+}
+
+// templateFuncs are available in recipe templates: {{env "JAVA_HOME"}},
+// {{home}} for the user's home directory on every platform, {{configdir}}
+// for the user's configuration directory (%APPDATA% on Windows), {{tempdir}},
+// {{pathsep}} between the entries of a list such as CLASSPATH, {{goos}},
+// {{join .InstallDir "lib"}} and {{dir .BinDir}}
+var templateFuncs = template.FuncMap{
+	"env":       os.Getenv,
+	"home":      homeDir,
+	"configdir": configDir,
+	"tempdir":   os.TempDir,
+	"pathsep":   func() string { return string(os.PathListSeparator) },
+	"goos":      func() string { return runtime.GOOS },
+	"join":      filepath.Join,
+	"dir":       filepath.Dir,
+}
+
+// homeDir returns the user's home directory, or the empty string if it is unknown
+func homeDir() string {
+	return os.Getenv(homeVariable)
+}
+
+// configDir returns the user's configuration directory, or the empty string
+// if it is unknown
+func configDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return dir
+}
+
+// Applies reports whether a recipe variable is set on this platform
+func (r *Recipe) Applies(name string) bool {
+	if !skipWindowsOnly {
+		return true
+	}
+	for _, only := range r.WindowsOnly {
+		if strings.EqualFold(only, name) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewTemplateData returns the template data for a program installed at
+// executable. A nil recipe uses the default installation directory.
+func (r *Recipe) NewTemplateData(executable string) (TemplateData, error) {
+	data := TemplateData{Executable: executable, BinDir: filepath.Dir(executable)}
+	data.InstallDir = data.BinDir
+	if strings.EqualFold(filepath.Base(data.BinDir), "bin") {
+		data.InstallDir = filepath.Dir(data.BinDir)
+	}
+	if r != nil && r.InstallDir != "" {
+		dir, err := ExpandTemplate(r.InstallDir, data)
+		if err != nil {
+			return data, fmt.Errorf("installDir: %v", err)
+		}
+		data.InstallDir = dir
+	}
+	return data, nil
+}
+
+// ExpandTemplate executes a recipe template
+func ExpandTemplate(text string, data TemplateData) (string, error) {
+	tmpl, err := template.New("value").Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Validate checks that the recipe's templates are valid and that its
+// option groups refer to its own variables
+func (r *Recipe) Validate() error {
+	data, err := r.NewTemplateData(filepath.Join("install", "bin", "tool.exe"))
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(r.Variables))
+	for name := range r.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if name == "" || strings.ContainsAny(name, "= ") {
+			return fmt.Errorf("variables: invalid variable name %q", name)
+		}
+		if _, err := ExpandTemplate(r.Variables[name], data); err != nil {
+			return fmt.Errorf("variables.%s: %v", name, err)
+		}
+	}
+	for i, dir := range r.Path {
+		if _, err := ExpandTemplate(dir, data); err != nil {
+			return fmt.Errorf("path[%d]: %v", i, err)
+		}
+	}
+	for i, dir := range r.CreateDirs {
+		if _, err := ExpandTemplate(dir, data); err != nil {
+			return fmt.Errorf("createDirs[%d]: %v", i, err)
+		}
+	}
+
+	for i, name := range r.WindowsOnly {
+		if _, ok := r.Variables[name]; !ok {
+			return fmt.Errorf("windowsOnly[%d]: unknown variable %q", i, name)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i, opt := range r.Options {
+		if opt.Name == "" {
+			return fmt.Errorf("options[%d]: name is required", i)
+		}
+		if seen[strings.ToLower(opt.Name)] {
+			return fmt.Errorf("options[%d]: duplicate option %q", i, opt.Name)
+		}
+		seen[strings.ToLower(opt.Name)] = true
+		for _, name := range opt.Variables {
+			if _, ok := r.Variables[name]; !ok {
+				return fmt.Errorf("options[%d] (%s): unknown variable %q", i, opt.Name, name)
+			}
+		}
+	}
+	return nil
+}
+
+// Validate checks the required fields of a program definition and its recipe
+func (p *Program) Validate() error {
+	if p.Name == "" || p.ExecutableName == "" {
+		return fmt.Errorf("name and executableName are required")
+	}
+	if p.DefaultScope != "" {
+		if _, err := registry.ParseScope(string(p.DefaultScope)); err != nil {
+			return fmt.Errorf("defaultScope: %v", err)
+		}
+	}
+	if p.Recipe != nil {
+		if err := p.Recipe.Validate(); err != nil {
+			return fmt.Errorf("recipe.%v", err)
+		}
+	}
+	if p.Version != nil {
+		if err := p.Version.Validate(); err != nil {
+			return fmt.Errorf("version.%v", err)
+		}
+	}
+	if p.Selection != nil {
+		if err := p.Selection.Validate(); err != nil {
+			return fmt.Errorf("selection: %v", err)
+		}
+	}
+	return nil
+}
+
+// DefaultCatalogDir returns the directory catalog files are read from by default
+func DefaultCatalogDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "catalog"
+	}
+	return filepath.Join(dir, "DevPathPro", "catalog")
+}
+
+// LoadCatalog reads and validates the *.json catalog files in dir in name order
+// and combines them into one catalog. A missing directory yields an empty catalog.
+// A program defined in more than one file is an error, since it is unclear which
+// definition wins; selection policies of later files override earlier ones.
+func LoadCatalog(dir string) (*Catalog, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("error reading catalog directory: %v", err)
+	}
+	sort.Strings(files)
+
+	combined := &Catalog{Version: CatalogVersion, Selection: make(map[string]*SelectionPolicy)}
+	definedIn := make(map[string]string)
+	for _, file := range files {
+		catalog, err := loadCatalogFile(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, prog := range catalog.Programs {
+			key := strings.ToLower(prog.Name)
+			if other, ok := definedIn[key]; ok {
+				return nil, fmt.Errorf("catalog %s: program %q is already defined in %s", file, prog.Name, other)
+			}
+			definedIn[key] = file
+			combined.Programs = append(combined.Programs, prog)
+		}
+		for name, policy := range catalog.Selection {
+			combined.Selection[name] = combined.Selection[name].Merge(policy)
+		}
+	}
+	return combined, nil
+}
+
+// loadCatalogFile reads and validates one catalog file
+func loadCatalogFile(file string) (*Catalog, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("error reading catalog: %v", err)
+	}
+	var catalog Catalog
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		return nil, fmt.Errorf("catalog %s: %v", file, err)
+	}
+	if catalog.Version != CatalogVersion {
+		return nil, fmt.Errorf("catalog %s: unsupported version %d (expected %d)", file, catalog.Version, CatalogVersion)
+	}
+	for i := range catalog.Programs {
+		prog := &catalog.Programs[i]
+		if err := prog.Validate(); err != nil {
+			return nil, fmt.Errorf("catalog %s: programs[%d] %s: %v", file, i, prog.Name, err)
+		}
+	}
+	if err := validateSelection(catalog.Selection); err != nil {
+		return nil, fmt.Errorf("catalog %s: %v", file, err)
+	}
+	return &catalog, nil
+}
+
+// MergePrograms returns base with every program of overrides applied: a program
+// replaces the one in base with the same name, or is appended if there is none
+func MergePrograms(base, overrides []Program) []Program {
+	programs := append([]Program(nil), base...)
+	for _, override := range overrides {
+		replaced := false
+		for i := range programs {
+			if strings.EqualFold(programs[i].Name, override.Name) {
+				programs[i] = override
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			programs = append(programs, override)
+		}
+	}
+	return programs
+}
+
+// LoadPrograms returns the built-in programs merged with the catalog files
+// and then with the custom programs of settings. Selection policies are
+// applied in the same order, so the user's settings win over the team's catalog.
+func LoadPrograms(settings *Settings) ([]Program, error) {
+	dir := settings.CatalogDir
+	if dir == "" {
+		dir = DefaultCatalogDir()
+	}
+	catalog, err := LoadCatalog(dir)
+	if err != nil {
+		return nil, err
+	}
+	programs := MergePrograms(GetDefaultPrograms(), catalog.Programs)
+	programs = MergePrograms(programs, settings.CustomPrograms)
+	ApplySelection(programs, catalog.Selection)
+	ApplySelection(programs, settings.Selection)
+	return programs, nil
+}
@@ -0,0 +1,381 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"devpathpro/pkg/plan"
+	"devpathpro/pkg/registry"
+)
+
+// Problems reported in ConfigurationIssue.Data["problem"]
+const (
+	ProblemDuplicate     = "duplicate"
+	ProblemNotFound      = "not_found"
+	ProblemTooLong       = "too_long"
+	ProblemUnset         = "unset"
+	ProblemNotWritable   = "not_writable"
+	ProblemNotExecutable = "not_executable"
+	ProblemCredentials   = "credentials"
+	ProblemShadowed      = "shadowed"
+	ProblemDrift         = "drift"
+)
+
+func init() {
+	RegisterCheck(pathCheck{checkInfo{"DPP001", "PATH entries are unique, exist and fit the Windows path length limit", "PATH", SeverityMedium}})
+	RegisterCheck(envCheck{checkInfo{"DPP002", "Tool environment variables are set and point to existing directories", "ENV", SeverityMedium}})
+	RegisterCheck(programCheck{checkInfo{"DPP003", "Known tools are installed in their common locations", "PROGRAM", SeverityMedium}})
+	RegisterCheck(permissionCheck{checkInfo{"DPP004", "Tool executables and directories have the permissions they need", "PERMISSION", SeverityHigh}})
+	RegisterCheck(securityCheck{checkInfo{"DPP005", "No database credentials are stored in environment variables", "SECURITY", SeverityHigh}})
+	RegisterCheck(shadowCheck{checkInfo{"DPP006", "Tool executables found through PATH are the configured installations", "PATH", SeverityMedium}})
+	RegisterCheck(shellCheck{checkInfo{"DPP007", "Managed shell profile blocks match the registry", "SHELL", SeverityLow}})
+}
+
+// checkInfo holds the descriptive part of a built-in check
+type checkInfo struct {
+	id, title, category, severity string
+}
+
+func (c checkInfo) ID() string              { return c.id }
+func (c checkInfo) Title() string           { return c.title }
+func (c checkInfo) Category() string        { return c.category }
+func (c checkInfo) DefaultSeverity() string { return c.severity }
+
+// pathCheck reports duplicate, missing and overlong PATH entries
+type pathCheck struct{ checkInfo }
+
+func (pathCheck) Run(ctx context.Context, env *CheckEnv) []ConfigurationIssue {
+	var issues []ConfigurationIssue
+	var seen []string
+	for _, p := range pathEntries(env) {
+		if registry.ContainsPath(seen, p) {
+			issues = append(issues, ConfigurationIssue{
+				Severity:    SeverityLow,
+				Description: "Duplicate PATH entry found",
+				Value:       p,
+				Solution:    "Remove duplicate entry from PATH",
+				Data:        map[string]string{"problem": ProblemDuplicate, "entry": p},
+				Fixable:     true,
+			})
+		}
+		seen = append(seen, p)
+
+		if _, err := os.Stat(p); os.IsNotExist(err) {
+			issues = append(issues, ConfigurationIssue{
+				Severity:    SeverityMedium,
+				Description: "PATH entry does not exist",
+				Value:       p,
+				Solution:    "Remove non-existent path or create directory",
+				Data:        map[string]string{"problem": ProblemNotFound, "entry": p},
+				Fixable:     true,
+			})
+		}
+
+		if maxPathEntryLength > 0 && len(p) > maxPathEntryLength {
+			issues = append(issues, ConfigurationIssue{
+				Severity:    SeverityHigh,
+				Description: "PATH entry exceeds Windows path length limit",
+				Value:       p,
+				Solution:    "Shorten path or use subst to create drive letter mapping",
+				Data:        map[string]string{"problem": ProblemTooLong, "entry": p},
+			})
+		}
+	}
+	return issues
+}
+
+// PlanFix removes a missing entry from the machine and user PATH, or every
+// occurrence of a duplicate one but the first, the machine PATH coming before
+// the user one as in the process PATH
+func (pathCheck) PlanFix(ctx context.Context, env *CheckEnv, issue ConfigurationIssue, p *plan.ChangePlan) error {
+	entry := issue.Data["entry"]
+	var keepFirst bool
+	switch issue.Data["problem"] {
+	case ProblemDuplicate:
+		keepFirst = true
+	case ProblemNotFound:
+	default:
+		return nil
+	}
+
+	kept := false
+	for _, scope := range pathScopes {
+		err := editPath(env, p, scope, func(entries []string) []string {
+			var result []string
+			for _, e := range entries {
+				if samePathEntry(env, e, entry) {
+					if !keepFirst || kept {
+						continue
+					}
+					kept = true
+				}
+				result = append(result, e)
+			}
+			return result
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// envCheck reports tool variables that are unset or point to missing directories
+type envCheck struct{ checkInfo }
+
+// checkedVariables are the variables envCheck inspects
+var checkedVariables = []struct {
+	name     string
+	desc     string
+	required bool
+}{
+	{"JAVA_HOME", "Java Development Kit", true},
+	{"PYTHON_HOME", "Python", true},
+	{"GOROOT", "Go Programming Language", true},
+	{"GOPATH", "Go Workspace", true},
+	{"NODE_PATH", "Node.js modules", false},
+	{"MAVEN_HOME", "Apache Maven", false},
+	{"GRADLE_HOME", "Gradle", false},
+	{"DOCKER_HOME", "Docker", false},
+	{"KUBECONFIG", "Kubernetes", false},
+	{"RUST_HOME", "Rust", false},
+	{"CARGO_HOME", "Cargo (Rust package manager)", false},
+	{"POSTGRES_HOME", "PostgreSQL", false},
+	{"MYSQL_HOME", "MySQL", false},
+	{"MONGODB_HOME", "MongoDB", false},
+	{"REDIS_HOME", "Redis", false},
+	{"ES_HOME", "Elasticsearch", false},
+	{"NEO4J_HOME", "Neo4j", false},
+	{"INFLUXDB_HOME", "InfluxDB", false},
+}
+
+func (envCheck) Run(ctx context.Context, env *CheckEnv) []ConfigurationIssue {
+	var issues []ConfigurationIssue
+	for _, v := range checkedVariables {
+		value := env.Getenv(v.name)
+
+		if v.required && value == "" {
+			issues = append(issues, ConfigurationIssue{
+				Severity:    SeverityHigh,
+				Description: fmt.Sprintf("Missing required %s environment variable", v.desc),
+				Value:       v.name,
+				Solution:    fmt.Sprintf("Set %s environment variable to the installation directory", v.name),
+				Data:        map[string]string{"problem": ProblemUnset, "variable": v.name},
+				Fixable:     findProgramPath(v.name) != "",
+			})
+			continue
+		}
+
+		if value != "" {
+			if _, err := os.Stat(value); os.IsNotExist(err) {
+				issues = append(issues, ConfigurationIssue{
+					Severity:    SeverityMedium,
+					Description: fmt.Sprintf("%s path does not exist", v.desc),
+					Value:       fmt.Sprintf("%s=%s", v.name, value),
+					Solution:    "Update path to correct installation directory",
+					Data:        map[string]string{"problem": ProblemNotFound, "variable": v.name, "path": value},
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// PlanFix sets an unset variable to the first installation directory found,
+// in the default scope of the tool it belongs to
+func (envCheck) PlanFix(ctx context.Context, env *CheckEnv, issue ConfigurationIssue, p *plan.ChangePlan) error {
+	if issue.Data["problem"] != ProblemUnset {
+		return nil
+	}
+	name := issue.Data["variable"]
+	if value := findProgramPath(name); value != "" {
+		p.SetVar(variableScope(env.Programs, name), name, value)
+	}
+	return nil
+}
+
+// programCheck reports known tools missing from their common locations
+type programCheck struct{ checkInfo }
+
+func (programCheck) Run(ctx context.Context, env *CheckEnv) []ConfigurationIssue {
+	var issues []ConfigurationIssue
+	for _, prog := range env.Programs {
+		if _, found := findInCommonPaths(prog); !found {
+			issues = append(issues, ConfigurationIssue{
+				Description: fmt.Sprintf("%s not found in common installation paths", prog.Name),
+				Value:       prog.Executable(),
+				Solution:    fmt.Sprintf("Install %s or update PATH if already installed", prog.Name),
+				Data:        map[string]string{"problem": ProblemNotFound, "program": prog.Name, "executable": prog.Executable()},
+			})
+		}
+	}
+	return issues
+}
+
+// permissionCheck reports tool executables that cannot run and tool
+// directories that are unset or not writable
+type permissionCheck struct{ checkInfo }
+
+// checkedDirs are the directories permissionCheck inspects, by variable
+var checkedDirs = []struct {
+	variable    string
+	description string
+	required    bool
+}{
+	{"GOPATH", "Go workspace", true},
+	{"MAVEN_REPOSITORY", "Maven repository", false},
+	{"GRADLE_USER_HOME", "Gradle home", false},
+	{"DOCKER_CONFIG", "Docker configuration", true},
+}
+
+func (permissionCheck) Run(ctx context.Context, env *CheckEnv) []ConfigurationIssue {
+	var issues []ConfigurationIssue
+
+	for _, prog := range env.Programs {
+		path, found := findInCommonPaths(prog)
+		if !found {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil && info.Mode().Perm()&0111 == 0 {
+			issues = append(issues, ConfigurationIssue{
+				Description: fmt.Sprintf("%s executable permissions are incorrect", prog.Name),
+				Value:       path,
+				Solution:    "Update file permissions to allow execution",
+				Data:        map[string]string{"problem": ProblemNotExecutable, "program": prog.Name, "path": path},
+			})
+		}
+	}
+
+	type dir struct {
+		path, description string
+		required          bool
+	}
+	dirs := []dir{}
+	for _, d := range checkedDirs {
+		dirs = append(dirs, dir{env.Getenv(d.variable), d.description, d.required})
+	}
+	dirs = append(dirs, dir{filepath.Join(env.Getenv(homeVariable), ".kube"), "Kubernetes configuration", false})
+
+	for _, d := range dirs {
+		if d.path == "" {
+			if d.required {
+				issues = append(issues, ConfigurationIssue{
+					Description: fmt.Sprintf("Required directory path not set: %s", d.description),
+					Value:       d.path,
+					Solution:    "Set correct path and ensure proper permissions",
+					Data:        map[string]string{"problem": ProblemUnset, "directory": d.description},
+				})
+			}
+			continue
+		}
+
+		if info, err := os.Stat(d.path); err == nil && info.Mode().Perm()&0200 == 0 {
+			issues = append(issues, ConfigurationIssue{
+				Description: fmt.Sprintf("No write permission: %s", d.description),
+				Value:       d.path,
+				Solution:    "Grant write permissions to the current user",
+				Data:        map[string]string{"problem": ProblemNotWritable, "directory": d.description, "path": d.path},
+				Fixable:     true,
+			})
+		}
+	}
+	return issues
+}
+
+// Fix makes a directory writable
+func (permissionCheck) Fix(ctx context.Context, env *CheckEnv, issue ConfigurationIssue) error {
+	if issue.Data["problem"] != ProblemNotWritable {
+		return nil
+	}
+	if err := os.Chmod(issue.Data["path"], 0755); err != nil {
+		return fmt.Errorf("failed to update permissions for %s: %v", issue.Data["path"], err)
+	}
+	return nil
+}
+
+// securityCheck reports database credentials kept in environment variables
+type securityCheck struct{ checkInfo }
+
+// credentialVariables are the variables securityCheck looks for, by database
+var credentialVariables = []struct {
+	database string
+	envVars  []string
+	desc     string
+}{
+	{"PostgreSQL", []string{"PGPASSWORD", "PGUSER"}, "PostgreSQL credentials in environment"},
+	{"MySQL", []string{"MYSQL_ROOT_PASSWORD", "MYSQL_USER"}, "MySQL credentials in environment"},
+	{"MongoDB", []string{"MONGO_INITDB_ROOT_PASSWORD", "MONGO_INITDB_ROOT_USERNAME"}, "MongoDB credentials in environment"},
+}
+
+func (securityCheck) Run(ctx context.Context, env *CheckEnv) []ConfigurationIssue {
+	var issues []ConfigurationIssue
+	for _, check := range credentialVariables {
+		for _, envVar := range check.envVars {
+			if env.Getenv(envVar) != "" {
+				issues = append(issues, ConfigurationIssue{
+					Description: fmt.Sprintf("%s: %s", check.database, check.desc),
+					Value:       envVar,
+					Solution:    "Use configuration files instead of environment variables for credentials",
+					Data:        map[string]string{"problem": ProblemCredentials, "database": check.database, "variable": envVar},
+				})
+			}
+		}
+	}
+	return issues
+}
+
+// pathEntries returns the entries of the checked PATH in search order
+func pathEntries(env *CheckEnv) []string {
+	return registry.SplitPathList(env.Getenv(registry.PathVariable))
+}
+
+// findInCommonPaths looks for a program's executable directly in its common paths
+func findInCommonPaths(prog Program) (string, bool) {
+	executable := prog.Executable()
+	for _, path := range prog.CommonPaths {
+		path = os.ExpandEnv(path)
+
+		if strings.Contains(path, "*") {
+			matches, err := filepath.Glob(path)
+			if err != nil {
+				continue
+			}
+			for _, match := range matches {
+				execPath := filepath.Join(match, executable)
+				if _, err := os.Stat(execPath); err == nil {
+					return execPath, true
+				}
+			}
+		} else {
+			execPath := filepath.Join(path, executable)
+			if _, err := os.Stat(execPath); err == nil {
+				return execPath, true
+			}
+		}
+	}
+	return "", false
+}
+
+// findProgramPath returns the first existing installation directory of the
+// tool a variable belongs to
+func findProgramPath(envVar string) string {
+	for _, pathPattern := range installDirPatterns[envVar] {
+		pathPattern = os.ExpandEnv(pathPattern)
+
+		if strings.Contains(pathPattern, "*") {
+			if matches, err := filepath.Glob(pathPattern); err == nil {
+				for _, match := range matches {
+					if _, err := os.Stat(match); err == nil {
+						return match
+					}
+				}
+			}
+		} else if _, err := os.Stat(pathPattern); err == nil {
+			return pathPattern
+		}
+	}
+	return ""
+}
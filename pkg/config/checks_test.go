@@ -0,0 +1,83 @@
+package config
+
+import (
+	"context"
+	"testing"
+
+	"devpathpro/pkg/plan"
+	"devpathpro/pkg/registry"
+)
+
+func TestPathCheckPlanFix(t *testing.T) {
+	join := registry.JoinPathList
+	tests := []struct {
+		name    string
+		problem string
+		entry   string
+		machine []string
+		user    []string
+		// wantMachine and wantUser are the PATH values once the fix is applied
+		wantMachine, wantUser string
+	}{
+		{
+			name:    "duplicate in one scope",
+			problem: ProblemDuplicate, entry: "/a",
+			user:        []string{"/a", "/b", "/a/"},
+			wantMachine: "", wantUser: join([]string{"/a", "/b"}),
+		},
+		{
+			name:    "duplicate across scopes keeps the machine entry",
+			problem: ProblemDuplicate, entry: "/a",
+			machine:     []string{"/m", "/a"},
+			user:        []string{"/a", "/b"},
+			wantMachine: join([]string{"/m", "/a"}), wantUser: "/b",
+		},
+		{
+			name:    "duplicate through a variable reference",
+			problem: ProblemDuplicate, entry: "/tools/bin",
+			user:        []string{"/tools/bin", registry.VarReference("TOOLS") + "/bin"},
+			wantMachine: "", wantUser: "/tools/bin",
+		},
+		{
+			name:    "missing entry in both scopes",
+			problem: ProblemNotFound, entry: "/gone",
+			machine:     []string{"/gone", "/m"},
+			user:        []string{"/b", "/gone"},
+			wantMachine: "/m", wantUser: "/b",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := registry.NewMemoryStore()
+			env := &CheckEnv{
+				Getenv: func(name string) string { return map[string]string{"TOOLS": "/tools"}[name] },
+				Store:  store,
+			}
+			for scope, entries := range map[registry.Scope][]string{registry.ScopeMachine: tt.machine, registry.ScopeUser: tt.user} {
+				if len(entries) > 0 {
+					if err := store.Set(scope, registry.PathVariable, join(entries)); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			issue := ConfigurationIssue{Data: map[string]string{"problem": tt.problem, "entry": tt.entry}, Fixable: true}
+			p := plan.New()
+			if err := (pathCheck{}).PlanFix(context.Background(), env, issue, p); err != nil {
+				t.Fatal(err)
+			}
+			for scope, want := range map[registry.Scope]string{registry.ScopeMachine: tt.wantMachine, registry.ScopeUser: tt.wantUser} {
+				got, _, err := p.Value(store, scope, registry.PathVariable)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != want {
+					t.Errorf("%s PATH = %q, want %q", scope, got, want)
+				}
+			}
+			if got, _, _ := store.Get(registry.ScopeProcess, registry.PathVariable); got != "" {
+				t.Errorf("the fix wrote the process PATH: %q", got)
+			}
+		})
+	}
+}
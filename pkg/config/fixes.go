@@ -0,0 +1,127 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"devpathpro/pkg/plan"
+	"devpathpro/pkg/registry"
+	"devpathpro/pkg/utils"
+)
+
+// PlanFixer is implemented by checks whose fixes change environment variables.
+// Instead of writing them, PlanFix adds the changes to p, so the fixes of all
+// issues are previewed, backed up and applied together in one transaction to
+// the user and machine variables the process environment is built from.
+type PlanFixer interface {
+	PlanFix(ctx context.Context, env *CheckEnv, issue ConfigurationIssue, p *plan.ChangePlan) error
+}
+
+// CanFix reports whether a check can fix some of its issues
+func CanFix(c Check) bool {
+	_, fixer := c.(Fixer)
+	_, planFixer := c.(PlanFixer)
+	return fixer || planFixer
+}
+
+// pathScopes are the scopes whose PATH make up the process PATH, in the
+// order Windows joins them
+var pathScopes = []registry.Scope{registry.ScopeMachine, registry.ScopeUser}
+
+// FixPlan returns the variable and PATH changes fixing the fixable issues.
+// Each fix sees the changes of the ones before it.
+func FixPlan(store registry.EnvStore, issues []ConfigurationIssue) (*plan.ChangePlan, error) {
+	ctx := context.Background()
+	env := DefaultCheckEnv(store)
+	p := plan.New()
+	for _, issue := range issues {
+		if !issue.Fixable {
+			continue
+		}
+		c, ok := FindCheck(issue.CheckID)
+		if !ok {
+			return nil, fmt.Errorf("unknown check %q", issue.CheckID)
+		}
+		if fixer, ok := c.(PlanFixer); ok {
+			if err := fixer.PlanFix(ctx, env, issue, p); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return p.Minimize(checkStore(env))
+}
+
+// FixConfigurationIssues fixes the fixable issues. The changes of FixPlan are
+// written to the user and machine variables of the store in one transaction,
+// after a backup; the other fixes, such as file permissions and shell
+// profiles, run afterwards.
+func FixConfigurationIssues(store registry.EnvStore, issues []ConfigurationIssue) error {
+	p, err := FixPlan(store, issues)
+	if err != nil {
+		return err
+	}
+	if !p.Empty() {
+		if p.RequiresAdmin() && !registry.IsAdmin() {
+			return fmt.Errorf("the fixes change machine variables, which requires administrator privileges")
+		}
+		if _, err := p.CreateBackup(store, "fix"); err != nil {
+			utils.Warnf("backup before fixing issues failed: %v", err)
+		}
+		if err := p.Apply(store); err != nil {
+			return err
+		}
+	}
+
+	ctx := context.Background()
+	env := DefaultCheckEnv(store)
+	for _, issue := range issues {
+		if !issue.Fixable {
+			continue
+		}
+		c, ok := FindCheck(issue.CheckID)
+		if !ok {
+			return fmt.Errorf("unknown check %q", issue.CheckID)
+		}
+		fixer, ok := c.(Fixer)
+		if !ok {
+			continue
+		}
+		if err := fixer.Fix(ctx, env, issue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// editPath adds a change setting the PATH of scope to what edit makes of the
+// entries it will have once p is applied, unless edit changes nothing
+func editPath(env *CheckEnv, p *plan.ChangePlan, scope registry.Scope, edit func([]string) []string) error {
+	value, _, err := p.Value(checkStore(env), scope, registry.PathVariable)
+	if err != nil {
+		return err
+	}
+	if updated := registry.JoinPathList(edit(registry.SplitPathList(value))); updated != value {
+		p.SetVar(scope, registry.PathVariable, updated)
+	}
+	return nil
+}
+
+// samePathEntry reports whether two PATH entries name the same directory
+// once their variable references are resolved
+func samePathEntry(env *CheckEnv, a, b string) bool {
+	return registry.SamePath(env.Expand(a), env.Expand(b))
+}
+
+// variableScope returns the scope a tool variable is written to: the default
+// scope of the program it belongs to, else the user scope
+func variableScope(programs []Program, name string) registry.Scope {
+	for _, prog := range programs {
+		for _, home := range prog.HomeVariables() {
+			if strings.EqualFold(home, name) && prog.DefaultScope != "" {
+				return prog.DefaultScope
+			}
+		}
+	}
+	return registry.ScopeUser
+}
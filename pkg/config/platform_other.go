@@ -0,0 +1,117 @@
+//go:build !windows
+
+package config
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// maxPathEntryLength is the longest PATH entry checked for; 0 checks none
+const maxPathEntryLength = 0
+
+// shellIntegration is off: the profile store always writes the startup files
+const shellIntegration = false
+
+// skipWindowsOnly leaves out the recipe variables that only apply on Windows
+const skipWindowsOnly = true
+
+// homeVariable holds the user's home directory
+const homeVariable = "HOME"
+
+// installDirPatterns are where the tool a variable belongs to is usually installed
+var installDirPatterns = map[string][]string{
+	"JAVA_HOME": {
+		"/usr/lib/jvm/default-java",
+		"/usr/lib/jvm/*",
+		"/Library/Java/JavaVirtualMachines/*/Contents/Home",
+		"/opt/homebrew/opt/openjdk",
+	},
+	"PYTHON_HOME": {
+		"/opt/homebrew/opt/python@3*",
+		"/usr/local/opt/python@3*",
+	},
+	"GOROOT": {
+		"/usr/local/go",
+		"/usr/lib/go",
+		"/opt/homebrew/opt/go/libexec",
+	},
+	"NODE_PATH": {
+		"/usr/local/lib/node_modules",
+		"/usr/lib/node_modules",
+		"/opt/homebrew/lib/node_modules",
+	},
+	"RUST_HOME": {
+		"$HOME/.cargo",
+	},
+}
+
+// unixPaths are the directories the built-in programs are searched in on
+// Linux and macOS, replacing their Windows CommonPaths. Programs without
+// an entry are only found through PATH.
+var unixPaths = map[string][]string{
+	"CMake":         {"/usr/local/bin", "/opt/homebrew/bin", "/snap/bin"},
+	"Make":          {"/usr/bin", "/usr/local/bin"},
+	"Ninja":         {"/usr/bin", "/usr/local/bin", "/opt/homebrew/bin"},
+	"Maven":         {"/usr/share/maven/bin", "/opt/maven/bin", "/opt/homebrew/opt/maven/bin", "$HOME/.sdkman/candidates/maven"},
+	"Gradle":        {"/opt/gradle", "/usr/share/gradle/bin", "/opt/homebrew/opt/gradle/bin", "$HOME/.sdkman/candidates/gradle"},
+	"Git":           {"/usr/bin", "/usr/local/bin", "/opt/homebrew/bin"},
+	"VS Code":       {"/usr/share/code/bin", "/snap/bin", "/Applications/Visual Studio Code.app/Contents/Resources/app/bin"},
+	"LLVM":          {"/usr/lib/llvm-*/bin", "/opt/homebrew/opt/llvm/bin"},
+	"Python":        {"/usr/bin", "/usr/local/bin", "/opt/homebrew/bin", "$HOME/.pyenv/versions"},
+	"Node.js":       {"/usr/bin", "/usr/local/bin", "/opt/homebrew/bin", "$HOME/.nvm/versions/node"},
+	"Java":          {"/usr/lib/jvm", "/Library/Java/JavaVirtualMachines", "/opt/homebrew/opt/openjdk/bin", "$HOME/.sdkman/candidates/java"},
+	"Go":            {"/usr/local/go/bin", "/usr/lib/go/bin", "/opt/homebrew/opt/go/libexec/bin"},
+	".NET Core":     {"/usr/share/dotnet", "/usr/local/share/dotnet", "$HOME/.dotnet"},
+	"Ruby":          {"/usr/bin", "/opt/homebrew/opt/ruby/bin", "$HOME/.rbenv/versions"},
+	"Rust":          {"$HOME/.cargo/bin"},
+	"Perl":          {"/usr/bin", "/usr/local/bin"},
+	"Scala":         {"/usr/share/scala/bin", "$HOME/.sdkman/candidates/scala"},
+	"Kotlin":        {"$HOME/.sdkman/candidates/kotlin"},
+	"Swift":         {"/usr/bin", "/usr/share/swift/usr/bin"},
+	"Haskell":       {"$HOME/.ghcup/bin"},
+	"Erlang":        {"/usr/bin", "/usr/lib/erlang/bin", "/opt/homebrew/opt/erlang/bin"},
+	"Elixir":        {"/usr/bin", "/opt/homebrew/opt/elixir/bin"},
+	"vcpkg":         {"$HOME/vcpkg", "/opt/vcpkg"},
+	"Conan":         {"/usr/local/bin", "$HOME/.local/bin"},
+	"PostgreSQL":    {"/usr/lib/postgresql", "/usr/bin", "/opt/homebrew/opt/postgresql*/bin"},
+	"MySQL":         {"/usr/bin", "/usr/local/mysql/bin", "/opt/homebrew/opt/mysql/bin"},
+	"MongoDB":       {"/usr/bin", "/opt/homebrew/opt/mongodb-community/bin"},
+	"Redis":         {"/usr/bin", "/opt/homebrew/opt/redis/bin"},
+	"Elasticsearch": {"/usr/share/elasticsearch/bin"},
+	"SQLite":        {"/usr/bin", "/opt/homebrew/opt/sqlite/bin"},
+	"Cassandra":     {"/usr/sbin", "/opt/cassandra/bin"},
+	"Neo4j":         {"/usr/bin", "/usr/share/neo4j/bin"},
+	"InfluxDB":      {"/usr/bin"},
+	"Docker":        {"/usr/bin", "/usr/local/bin"},
+	"Kubernetes":    {"/usr/local/bin", "/snap/bin"},
+	"Podman":        {"/usr/bin", "/opt/homebrew/bin"},
+	"Terraform":     {"/usr/bin", "/usr/local/bin", "/opt/homebrew/bin"},
+	"Ansible":       {"/usr/bin", "$HOME/.local/bin"},
+	"Helm":          {"/usr/local/bin", "/opt/homebrew/bin"},
+	"Skaffold":      {"/usr/local/bin"},
+}
+
+// unixExecutables name executables that are not just the Windows name without extension
+var unixExecutables = map[string]string{
+	"Python":  "python3",
+	"Grafana": "grafana",
+}
+
+// platformPaths returns the directories a built-in program is searched in
+func platformPaths(prog Program) []string {
+	return unixPaths[prog.Name]
+}
+
+// platformExecutable returns the file name of a program's executable:
+// the Windows name without its .exe, .cmd or .bat extension
+func platformExecutable(prog Program) string {
+	if name, ok := unixExecutables[prog.Name]; ok {
+		return name
+	}
+	switch ext := strings.ToLower(filepath.Ext(prog.ExecutableName)); ext {
+	case ".exe", ".cmd", ".bat":
+		return strings.TrimSuffix(prog.ExecutableName, filepath.Ext(prog.ExecutableName))
+	}
+	return prog.ExecutableName
+}
@@ -0,0 +1,57 @@
+//go:build windows
+
+package config
+
+// maxPathEntryLength is the longest PATH entry Windows handles without long path support
+const maxPathEntryLength = 260
+
+// shellIntegration is set where shell startup files only get a managed
+// block when Settings.Shells asks for one
+const shellIntegration = true
+
+// skipWindowsOnly is off: every recipe variable applies on Windows
+const skipWindowsOnly = false
+
+// homeVariable holds the user's home directory
+const homeVariable = "USERPROFILE"
+
+// installDirPatterns are where the tool a variable belongs to is usually installed
+var installDirPatterns = map[string][]string{
+	"JAVA_HOME": {
+		`C:\Program Files\Java\*`,
+		`C:\Program Files (x86)\Java\*`,
+		`C:\Program Files\Eclipse Foundation\*`,
+	},
+	"PYTHON_HOME": {
+		`C:\Python3*`,
+		`C:\Program Files\Python*`,
+		`C:\Program Files (x86)\Python*`,
+		`C:\Users\%USERNAME%\AppData\Local\Programs\Python\Python*`,
+	},
+	"GOROOT": {
+		`C:\Go`,
+		`C:\Program Files\Go`,
+	},
+	"NODE_PATH": {
+		`C:\Program Files\nodejs`,
+		`C:\Program Files (x86)\nodejs`,
+	},
+	"DOCKER_HOME": {
+		`C:\Program Files\Docker`,
+		`C:\Program Files\Docker\Docker`,
+	},
+	"RUST_HOME": {
+		`C:\Users\%USERNAME%\.cargo`,
+		`C:\Program Files\Rust`,
+	},
+}
+
+// platformPaths returns the directories a built-in program is searched in
+func platformPaths(prog Program) []string {
+	return prog.CommonPaths
+}
+
+// platformExecutable returns the file name of a program's executable
+func platformExecutable(prog Program) string {
+	return prog.ExecutableName
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+
+	"devpathpro/pkg/version"
+)
+
+// VersionProbe describes how the version of an installation is detected.
+// The command is tried first, then the files; on Windows the version
+// resource of the executable is the last resort.
+type VersionProbe struct {
+	// Args are passed to the program's executable, e.g. ["--version"];
+	// empty means the executable is not run
+	Args []string `json:"args,omitempty"`
+	// Pattern is matched against the command output; the first non-empty
+	// capture group is the version
+	Pattern string `json:"pattern,omitempty"`
+	// Files are read when the command gives no version
+	Files []VersionFile `json:"files,omitempty"`
+	// LTS is a version constraint matching long-term support releases, e.g. "18 || 20 || 22"
+	LTS string `json:"lts,omitempty"`
+}
+
+// VersionFile is a file that contains an installation's version
+type VersionFile struct {
+	// Path is a template, e.g. {{join .InstallDir "release"}}
+	Path string `json:"path"`
+	// Pattern is matched against the file contents like VersionProbe.Pattern
+	Pattern string `json:"pattern"`
+}
+
+// Validate checks the probe's patterns and file templates
+func (v *VersionProbe) Validate() error {
+	if len(v.Args) > 0 {
+		if err := validatePattern(v.Pattern); err != nil {
+			return fmt.Errorf("pattern: %v", err)
+		}
+	}
+	if v.LTS != "" {
+		if _, err := version.ParseConstraint(v.LTS); err != nil {
+			return fmt.Errorf("lts: %v", err)
+		}
+	}
+	var recipe *Recipe
+	data, _ := recipe.NewTemplateData("tool.exe")
+	for i, f := range v.Files {
+		if _, err := ExpandTemplate(f.Path, data); err != nil {
+			return fmt.Errorf("files[%d].path: %v", i, err)
+		}
+		if err := validatePattern(f.Pattern); err != nil {
+			return fmt.Errorf("files[%d].pattern: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// validatePattern checks that a version pattern compiles and captures something
+func validatePattern(pattern string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	if re.NumSubexp() == 0 {
+		return fmt.Errorf("%q has no capture group for the version", pattern)
+	}
+	return nil
+}
+
+// builtinVersionProbes are the version probes of the built-in programs, by program name.
+// Programs that must not be run (servers, GUI applications) have no command.
+var builtinVersionProbes = map[string]*VersionProbe{
+	"CMake":     {Args: []string{"--version"}, Pattern: `cmake version (\S+)`},
+	"MSBuild":   {Args: []string{"-version", "-nologo"}, Pattern: `(\d+\.\d+\.\d+(?:\.\d+)?)`},
+	"Make":      {Args: []string{"--version"}, Pattern: `GNU Make (\S+)`},
+	"Ninja":     {Args: []string{"--version"}, Pattern: `(\d+\.\d+\.\d+)`},
+	"Maven":     {Args: []string{"-v"}, Pattern: `Apache Maven (\S+)`},
+	"Gradle":    {Args: []string{"--version"}, Pattern: `Gradle (\S+)`},
+	"Git":       {Args: []string{"--version"}, Pattern: `git version (\S+)`},
+	"LLVM":      {Args: []string{"--version"}, Pattern: `clang version (\S+)`},
+	"SonarQube": {Args: []string{"--version"}, Pattern: `SonarScanner (?:CLI )?(\S+)`},
+	"Grafana":   {Args: []string{"-v"}, Pattern: `[Vv]ersion (\S+)`},
+	"Python":    {Args: []string{"--version"}, Pattern: `Python (\S+)`},
+	"Node.js":   {Args: []string{"-v"}, Pattern: `v(\d+\S*)`, LTS: "4 || 6 || 8 || 10 || 12 || 14 || 16 || 18 || 20 || 22 || 24"},
+	"Java": {
+		Args:    []string{"-version"},
+		Pattern: `javac (\S+)`,
+		LTS:     "1.8 || 8 || 11 || 17 || 21 || 25",
+		Files:   []VersionFile{{Path: `{{join .InstallDir "release"}}`, Pattern: `JAVA_VERSION="([^"]+)"`}},
+	},
+	"Go": {
+		Args:    []string{"version"},
+		Pattern: `go version go(\S+)`,
+		Files:   []VersionFile{{Path: `{{join .InstallDir "VERSION"}}`, Pattern: `go(\d+\S*)`}},
+	},
+	".NET Core":     {Args: []string{"--version"}, Pattern: `(?m)^(\d+\.\d+\.\d+\S*)`},
+	"Ruby":          {Args: []string{"--version"}, Pattern: `ruby (\S+)`},
+	"Rust":          {Args: []string{"--version"}, Pattern: `rustc (\S+)`},
+	"Perl":          {Args: []string{"-v"}, Pattern: `\(v(\d+\.\d+\.\d+)\)`},
+	"Scala":         {Args: []string{"-version"}, Pattern: `version (\d+\.\d+\.\d+\S*)`},
+	"Kotlin":        {Args: []string{"-version"}, Pattern: `Kotlin version (\S+)`},
+	"Swift":         {Args: []string{"--version"}, Pattern: `Swift version (\S+)`},
+	"Haskell":       {Args: []string{"--version"}, Pattern: `version (\S+)`},
+	"Elixir":        {Args: []string{"--version"}, Pattern: `Elixir (\S+)`},
+	"vcpkg":         {Args: []string{"version"}, Pattern: `version (\S+)`},
+	"Conan":         {Args: []string{"--version"}, Pattern: `Conan version (\S+)`},
+	"PostgreSQL":    {Args: []string{"--version"}, Pattern: `\(PostgreSQL\) (\S+)`},
+	"MySQL":         {Args: []string{"--version"}, Pattern: `(?:Ver|Distrib) (\d+\.\d+\.\d+)`},
+	"MongoDB":       {Args: []string{"--version"}, Pattern: `db version v(\S+)`},
+	"Redis":         {Args: []string{"--version"}, Pattern: `v=(\S+)`},
+	"Elasticsearch": {Args: []string{"--version"}, Pattern: `Version: (\S+?),?\s`},
+	"SQLite":        {Args: []string{"--version"}, Pattern: `(?m)^(\d+\.\d+\.\d+)`},
+	"Oracle":        {Args: []string{"-v"}, Pattern: `Release (\S+)`},
+	"Cassandra":     {Args: []string{"-v"}, Pattern: `(?m)^(\d+\.\d+\.\d+)`},
+	"Neo4j":         {Args: []string{"version"}, Pattern: `(\d+\.\d+\.\d+)`},
+	"InfluxDB":      {Args: []string{"version"}, Pattern: `InfluxDB v?(\S+)`},
+	"Docker":        {Args: []string{"--version"}, Pattern: `Docker version ([^,\s]+)`},
+	"Kubernetes":    {Args: []string{"version", "--client"}, Pattern: `(?:Client Version: v|GitVersion:"v)([^"\s]+)`},
+	"Podman":        {Args: []string{"--version"}, Pattern: `podman version (\S+)`},
+	"Terraform":     {Args: []string{"version"}, Pattern: `Terraform v(\S+)`},
+	"Ansible":       {Args: []string{"--version"}, Pattern: `ansible \[core (\S+)\]|ansible (\S+)`},
+	"Helm":          {Args: []string{"version", "--short"}, Pattern: `v(\d+\.\d+\.\d+)`},
+	"Skaffold":      {Args: []string{"version"}, Pattern: `v(\d+\.\d+\.\d+\S*)`},
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"devpathpro/pkg/registry"
+)
+
+// Profile is a named set of tool selections, such as "legacy" with JDK 11
+// and Maven, that the environment can be switched to in one step
+type Profile struct {
+	Name  string        `json:"name" yaml:"name"`
+	Tools []ProfileTool `json:"tools" yaml:"tools"`
+}
+
+// ProfileTool is how a profile configures one tool
+type ProfileTool struct {
+	Tool string `json:"tool" yaml:"tool"`
+	// Path is the executable of the installation
+	Path string `json:"path" yaml:"path"`
+	// Options are the option groups to set; empty sets every variable
+	Options []string `json:"options,omitempty" yaml:"options,omitempty"`
+	// Scope is where the variables are written; empty uses the tool's default scope
+	Scope registry.Scope `json:"scope,omitempty" yaml:"scope,omitempty"`
+}
+
+// FindProfile returns the profile with the given name, ignoring case
+func FindProfile(profiles []Profile, name string) (Profile, bool) {
+	for _, p := range profiles {
+		if strings.EqualFold(p.Name, name) {
+			return p, true
+		}
+	}
+	return Profile{}, false
+}
+
+// Profiles returns the profiles saved in the settings
+func (c *Configuration) Profiles() []Profile {
+	if c.Settings == nil {
+		return nil
+	}
+	return c.Settings.Profiles
+}
+
+// PutProfile saves a profile to the settings file, replacing the one with the same name
+func (c *Configuration) PutProfile(p Profile) error {
+	profiles := make([]Profile, 0, len(c.Profiles())+1)
+	replaced := false
+	for _, existing := range c.Profiles() {
+		if strings.EqualFold(existing.Name, p.Name) {
+			existing = p
+			replaced = true
+		}
+		profiles = append(profiles, existing)
+	}
+	if !replaced {
+		profiles = append(profiles, p)
+	}
+	return c.saveProfiles(profiles)
+}
+
+// DeleteProfile removes a profile from the settings file and reports whether it existed
+func (c *Configuration) DeleteProfile(name string) (bool, error) {
+	var profiles []Profile
+	for _, existing := range c.Profiles() {
+		if !strings.EqualFold(existing.Name, name) {
+			profiles = append(profiles, existing)
+		}
+	}
+	if len(profiles) == len(c.Profiles()) {
+		return false, nil
+	}
+	return true, c.saveProfiles(profiles)
+}
+
+// saveProfiles writes the settings file with the given profiles and keeps
+// the other settings as they were loaded
+func (c *Configuration) saveProfiles(profiles []Profile) error {
+	updated := Settings{}
+	if c.Settings != nil {
+		updated = *c.Settings
+	}
+	updated.Profiles = profiles
+	if err := SaveSettings(c.SettingsPath, &updated); err != nil {
+		return err
+	}
+	c.Settings = &updated
+	return nil
+}
+
+// validateProfiles checks that profiles have unique names and complete tool selections
+func validateProfiles(profiles []Profile) error {
+	seen := make(map[string]bool)
+	for i, p := range profiles {
+		name := strings.TrimSpace(p.Name)
+		if name == "" {
+			return fmt.Errorf("profiles[%d]: name is required", i)
+		}
+		if seen[strings.ToLower(name)] {
+			return fmt.Errorf("profiles: duplicate profile %q", name)
+		}
+		seen[strings.ToLower(name)] = true
+
+		tools := make(map[string]bool)
+		for j, t := range p.Tools {
+			if t.Tool == "" || t.Path == "" {
+				return fmt.Errorf("profiles.%s: tools[%d]: tool and path are required", name, j)
+			}
+			if tools[strings.ToLower(t.Tool)] {
+				return fmt.Errorf("profiles.%s: %s is listed twice", name, t.Tool)
+			}
+			tools[strings.ToLower(t.Tool)] = true
+			if t.Scope != "" {
+				if _, err := registry.ParseScope(string(t.Scope)); err != nil {
+					return fmt.Errorf("profiles.%s: %s: %v", name, t.Tool, err)
+				}
+			}
+		}
+	}
+	return nil
+}
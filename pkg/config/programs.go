@@ -1,8 +1,10 @@
 package config
 
+import "devpathpro/pkg/registry"
+
 // GetDefaultPrograms returns the default list of supported programs
 func GetDefaultPrograms() []Program {
-	return []Program{
+	programs := []Program{
 		// Build Systems
 		{
 			Name:           "CMake",
@@ -26,7 +28,8 @@ func GetDefaultPrograms() []Program {
 				`C:\Windows\Microsoft.NET\Framework\v4.0.30319`,
 				`C:\Windows\Microsoft.NET\Framework64\v4.0.30319`,
 			},
-			Category: "Build Systems",
+			Category:     "Build Systems",
+			DefaultScope: registry.ScopeMachine,
 		},
 		{
 			Name:           "Make",
@@ -87,7 +90,8 @@ func GetDefaultPrograms() []Program {
 				`C:\Program Files\Microsoft Visual Studio\2022\Professional\Common7\IDE`,
 				`C:\Program Files\Microsoft Visual Studio\2022\Enterprise\Common7\IDE`,
 			},
-			Category: "Development Tools",
+			Category:     "Development Tools",
+			DefaultScope: registry.ScopeMachine,
 		},
 		{
 			Name:           "VS Code",
@@ -114,7 +118,8 @@ func GetDefaultPrograms() []Program {
 				`C:\Program Files (x86)\Windows Kits\10\bin\*\x64`,
 				`C:\Program Files (x86)\Windows Kits\10\bin\*\x86`,
 			},
-			Category: "Development Tools",
+			Category:     "Development Tools",
+			DefaultScope: registry.ScopeMachine,
 		},
 		{
 			Name:           "WDK",
@@ -123,7 +128,8 @@ func GetDefaultPrograms() []Program {
 				`C:\Program Files (x86)\Windows Kits\10\Tools\*\x64`,
 				`C:\Program Files (x86)\Windows Kits\10\Tools\*\x86`,
 			},
-			Category: "Development Tools",
+			Category:     "Development Tools",
+			DefaultScope: registry.ScopeMachine,
 		},
 		{
 			Name:           "Jenkins",
@@ -132,7 +138,8 @@ func GetDefaultPrograms() []Program {
 				`C:\Program Files\Jenkins`,
 				`C:\Program Files (x86)\Jenkins`,
 			},
-			Category: "Development Tools",
+			Category:     "Development Tools",
+			DefaultScope: registry.ScopeMachine,
 		},
 		{
 			Name:           "SonarQube",
@@ -316,7 +323,8 @@ func GetDefaultPrograms() []Program {
 				`C:\Program Files\PostgreSQL\*\bin`,
 				`C:\Program Files (x86)\PostgreSQL\*\bin`,
 			},
-			Category: "Databases",
+			Category:     "Databases",
+			DefaultScope: registry.ScopeMachine,
 		},
 		{
 			Name:           "MySQL",
@@ -326,7 +334,8 @@ func GetDefaultPrograms() []Program {
 				`C:\Program Files (x86)\MySQL\MySQL Server *\bin`,
 				`C:\Program Files\MariaDB *\bin`,
 			},
-			Category: "Databases",
+			Category:     "Databases",
+			DefaultScope: registry.ScopeMachine,
 		},
 		{
 			Name:           "MongoDB",
@@ -335,7 +344,8 @@ func GetDefaultPrograms() []Program {
 				`C:\Program Files\MongoDB\Server\*\bin`,
 				`C:\Program Files\MongoDB\*\bin`,
 			},
-			Category: "Databases",
+			Category:     "Databases",
+			DefaultScope: registry.ScopeMachine,
 		},
 		{
 			Name:           "Redis",
@@ -344,7 +354,8 @@ func GetDefaultPrograms() []Program {
 				`C:\Program Files\Redis`,
 				`C:\Program Files (x86)\Redis`,
 			},
-			Category: "Databases",
+			Category:     "Databases",
+			DefaultScope: registry.ScopeMachine,
 		},
 		{
 			Name:           "Elasticsearch",
@@ -353,7 +364,8 @@ func GetDefaultPrograms() []Program {
 				`C:\Program Files\Elastic\Elasticsearch\*\bin`,
 				`C:\Program Files (x86)\Elastic\Elasticsearch\*\bin`,
 			},
-			Category: "Databases",
+			Category:     "Databases",
+			DefaultScope: registry.ScopeMachine,
 		},
 		{
 			Name:           "SQLite",
@@ -362,7 +374,8 @@ func GetDefaultPrograms() []Program {
 				`C:\Program Files\SQLite`,
 				`C:\Program Files (x86)\SQLite`,
 			},
-			Category: "Databases",
+			Category:     "Databases",
+			DefaultScope: registry.ScopeMachine,
 		},
 		{
 			Name:           "Oracle",
@@ -371,7 +384,8 @@ func GetDefaultPrograms() []Program {
 				`C:\Program Files\Oracle\*\bin`,
 				`C:\Program Files (x86)\Oracle\*\bin`,
 			},
-			Category: "Databases",
+			Category:     "Databases",
+			DefaultScope: registry.ScopeMachine,
 		},
 		{
 			Name:           "Cassandra",
@@ -380,7 +394,8 @@ func GetDefaultPrograms() []Program {
 				`C:\Program Files\Apache\cassandra\bin`,
 				`C:\Program Files (x86)\Apache\cassandra\bin`,
 			},
-			Category: "Databases",
+			Category:     "Databases",
+			DefaultScope: registry.ScopeMachine,
 		},
 		{
 			Name:           "Neo4j",
@@ -389,7 +404,8 @@ func GetDefaultPrograms() []Program {
 				`C:\Program Files\Neo4j*\bin`,
 				`C:\Program Files (x86)\Neo4j*\bin`,
 			},
-			Category: "Databases",
+			Category:     "Databases",
+			DefaultScope: registry.ScopeMachine,
 		},
 		{
 			Name:           "InfluxDB",
@@ -398,7 +414,8 @@ func GetDefaultPrograms() []Program {
 				`C:\Program Files\InfluxData\InfluxDB\bin`,
 				`C:\Program Files (x86)\InfluxData\InfluxDB\bin`,
 			},
-			Category: "Databases",
+			Category:     "Databases",
+			DefaultScope: registry.ScopeMachine,
 		},
 
 		// Infrastructure
@@ -409,7 +426,8 @@ func GetDefaultPrograms() []Program {
 				`C:\Program Files\Docker\Docker\resources\bin`,
 				`C:\Program Files\Docker Toolbox`,
 			},
-			Category: "Infrastructure",
+			Category:     "Infrastructure",
+			DefaultScope: registry.ScopeMachine,
 		},
 		{
 			Name:           "Kubernetes",
@@ -467,4 +485,11 @@ func GetDefaultPrograms() []Program {
 			Category: "Infrastructure",
 		},
 	}
+
+	for i := range programs {
+		programs[i].CommonPaths = platformPaths(programs[i])
+		programs[i].Recipe = builtinRecipes[programs[i].Name]
+		programs[i].Version = builtinVersionProbes[programs[i].Name]
+	}
+	return programs
 } 
\ No newline at end of file
@@ -0,0 +1,473 @@
+package config
+
+// builtinRecipes are the recipes of the built-in programs, by program name.
+// Programs without a recipe only get the directory of their executable added to PATH.
+var builtinRecipes = map[string]*Recipe{
+	"Python": {
+		Variables: map[string]string{
+			"PYTHON_HOME":                   "{{.InstallDir}}",
+			"PYTHONPATH":                    `{{.InstallDir}};{{join .InstallDir "Lib" "site-packages"}}`,
+			"PYTHONUNBUFFERED":              "1",
+			"PYTHONDONTWRITEBYTECODE":       "1",
+			"PYTHONIOENCODING":              "utf-8",
+			"PYTHONUTF8":                    "1",
+			"PYTHONWARNINGS":                "default",
+			"PYTHONDEBUG":                   "1",
+			"PYTHONOPTIMIZE":                "1",
+			"PIP_CONFIG_FILE":               `{{if eq goos "windows"}}{{join configdir "pip" "pip.ini"}}{{else}}{{join configdir "pip" "pip.conf"}}{{end}}`,
+			"PIP_DEFAULT_TIMEOUT":           "100",
+			"PIP_DISABLE_PIP_VERSION_CHECK": "1",
+			"VIRTUAL_ENV_DISABLE_PROMPT":    "1",
+		},
+		Options: []OptionGroup{
+			{
+				Name:        "Basic",
+				Description: "Basic Python configuration (HOME and PATH)",
+				Variables:   []string{"PYTHON_HOME", "PYTHONPATH"},
+			},
+			{
+				Name:        "Environment",
+				Description: "Python environment settings (encoding, buffering, etc.)",
+				Variables:   []string{"PYTHONUNBUFFERED", "PYTHONDONTWRITEBYTECODE", "PYTHONIOENCODING", "PYTHONUTF8"},
+			},
+			{
+				Name:        "Development",
+				Description: "Development settings (warnings, debug, optimize)",
+				Variables:   []string{"PYTHONWARNINGS", "PYTHONDEBUG", "PYTHONOPTIMIZE"},
+			},
+			{
+				Name:        "Pip",
+				Description: "Pip package manager settings",
+				Variables:   []string{"PIP_CONFIG_FILE", "PIP_DEFAULT_TIMEOUT", "PIP_DISABLE_PIP_VERSION_CHECK"},
+			},
+		},
+		// The Windows layout keeps packages and their scripts apart from the
+		// interpreter; elsewhere scripts share its bin directory
+		Path:        []string{`{{if eq goos "windows"}}{{join .InstallDir "Scripts"}}{{end}}`},
+		WindowsOnly: []string{"PYTHONPATH"},
+	},
+	"Java": {
+		Variables: map[string]string{
+			"JAVA_HOME":     "{{.InstallDir}}",
+			"CLASSPATH":     `{{join .InstallDir "lib" "tools.jar"}}{{pathsep}}{{join .InstallDir "lib" "dt.jar"}}{{with env "CLASSPATH"}}{{pathsep}}{{.}}{{end}}`,
+			"_JAVA_OPTIONS": "-Xmx2048m -Xms512m",
+		},
+		Options: []OptionGroup{
+			{
+				Name:        "Basic",
+				Description: "Basic Java configuration (HOME and CLASSPATH)",
+				Variables:   []string{"JAVA_HOME", "CLASSPATH"},
+			},
+			{
+				Name:        "JVM",
+				Description: "JVM memory and performance settings",
+				Variables:   []string{"_JAVA_OPTIONS"},
+			},
+		},
+		Path: []string{`{{join .InstallDir "bin"}}`},
+	},
+	"Node.js": {
+		Variables: map[string]string{
+			"NODE_PATH":           `{{join .InstallDir "node_modules"}}`,
+			"NPM_CONFIG_PREFIX":   `{{join configdir "npm"}}`,
+			"NPM_CONFIG_CACHE":    `{{join configdir "npm-cache"}}`,
+			"NPM_CONFIG_TMP":      `{{join tempdir "npm"}}`,
+			"NPM_CONFIG_REGISTRY": "https://registry.npmjs.org/",
+		},
+		Options: []OptionGroup{
+			{
+				Name:        "Basic",
+				Description: "Basic Node.js configuration",
+				Variables:   []string{"NODE_PATH"},
+			},
+			{
+				Name:        "NPM",
+				Description: "NPM package manager settings",
+				Variables:   []string{"NPM_CONFIG_PREFIX", "NPM_CONFIG_CACHE", "NPM_CONFIG_TMP", "NPM_CONFIG_REGISTRY"},
+			},
+		},
+		// Global packages put their commands in the prefix on Windows and in
+		// its bin directory elsewhere
+		CreateDirs: []string{`{{if eq goos "windows"}}{{join configdir "npm"}}{{else}}{{join configdir "npm" "bin"}}{{end}}`},
+		Path:       []string{`{{if eq goos "windows"}}{{join configdir "npm"}}{{else}}{{join configdir "npm" "bin"}}{{end}}`},
+	},
+	"Go": {
+		Variables: map[string]string{
+			"GOROOT":      "{{.InstallDir}}",
+			"GOPATH":      `{{join home "go"}}`,
+			"GOBIN":       `{{join home "go" "bin"}}`,
+			"GO111MODULE": "on",
+			"GOCACHE":     `{{join home "go" "cache"}}`,
+			"GOTMPDIR":    `{{join tempdir "go-build"}}`,
+			"GOPROXY":     "https://proxy.golang.org,direct",
+			"GOSUMDB":     "sum.golang.org",
+		},
+		Options: []OptionGroup{
+			{
+				Name:        "Basic",
+				Description: "Basic Go configuration",
+				Variables:   []string{"GOROOT", "GOPATH", "GOBIN", "GO111MODULE", "GOCACHE", "GOTMPDIR", "GOPROXY", "GOSUMDB"},
+			},
+		},
+		CreateDirs: []string{`{{join home "go" "bin"}}`},
+		Path:       []string{`{{join .InstallDir "bin"}}`, `{{join home "go" "bin"}}`},
+	},
+	"Rust": {
+		Variables: map[string]string{
+			"RUST_HOME":        "{{.InstallDir}}",
+			"CARGO_HOME":       `{{join home ".cargo"}}`,
+			"RUSTUP_HOME":      `{{join home ".rustup"}}`,
+			"RUST_BACKTRACE":   "1",
+			"RUSTC_WRAPPER":    "sccache",
+			"CARGO_TARGET_DIR": `{{join home ".cargo" "target"}}`,
+			"RUSTDOC_THEME":    "dark",
+		},
+		Options: []OptionGroup{
+			{
+				Name:        "Basic",
+				Description: "Basic Rust configuration",
+				Variables:   []string{"RUST_HOME", "CARGO_HOME", "RUSTUP_HOME", "RUST_BACKTRACE", "RUSTC_WRAPPER", "CARGO_TARGET_DIR", "RUSTDOC_THEME"},
+			},
+		},
+		Path: []string{`{{join home ".cargo" "bin"}}`},
+	},
+	"Maven": {
+		Variables: map[string]string{
+			"M2_HOME":          "{{.InstallDir}}",
+			"MAVEN_HOME":       "{{.InstallDir}}",
+			"MAVEN_OPTS":       "-Xmx2048m -Xms1024m",
+			"MAVEN_CONFIG":     `{{join home ".m2"}}`,
+			"MAVEN_REPOSITORY": `{{join home ".m2" "repository"}}`,
+			"MAVEN_DEBUG_OPTS": "-Xdebug -Xnoagent -Djava.compiler=NONE -Xrunjdwp:transport=dt_socket,server=y,suspend=n,address=8000",
+		},
+		Options: []OptionGroup{
+			{
+				Name:        "Basic",
+				Description: "Basic Maven configuration",
+				Variables:   []string{"M2_HOME", "MAVEN_HOME", "MAVEN_OPTS", "MAVEN_CONFIG", "MAVEN_REPOSITORY", "MAVEN_DEBUG_OPTS"},
+			},
+		},
+		Path: []string{`{{join .InstallDir "bin"}}`},
+	},
+	"Gradle": {
+		Variables: map[string]string{
+			"GRADLE_HOME":      "{{.InstallDir}}",
+			"GRADLE_USER_HOME": `{{join home ".gradle"}}`,
+			"GRADLE_OPTS":      "-Xmx2048m -Xms512m -XX:MaxPermSize=512m -XX:+HeapDumpOnOutOfMemoryError",
+			"GRADLE_CACHE":     `{{join home ".gradle" "caches"}}`,
+			"GRADLE_DAEMON":    "true",
+			"GRADLE_WORKERS":   "4",
+		},
+		Options: []OptionGroup{
+			{
+				Name:        "Basic",
+				Description: "Basic Gradle configuration",
+				Variables:   []string{"GRADLE_HOME", "GRADLE_USER_HOME", "GRADLE_OPTS", "GRADLE_CACHE", "GRADLE_DAEMON", "GRADLE_WORKERS"},
+			},
+		},
+		Path: []string{`{{join .InstallDir "bin"}}`},
+	},
+	"Scala": {
+		Variables: map[string]string{
+			"SCALA_HOME":     "{{.InstallDir}}",
+			"SCALA_OPTS":     "-Xmx2048m -Xms1024m",
+			"SBT_OPTS":       "-Xmx2G -XX:+UseConcMarkSweepGC -XX:+CMSClassUnloadingEnabled",
+			"SBT_HOME":       `{{join home ".sbt"}}`,
+			"COURSIER_CACHE": `{{join home ".coursier" "cache"}}`,
+			"SCALA_CACHE":    `{{join home ".scala" "cache"}}`,
+		},
+		Options: []OptionGroup{
+			{
+				Name:        "Basic",
+				Description: "Basic Scala configuration",
+				Variables:   []string{"SCALA_HOME", "SCALA_OPTS", "SBT_OPTS", "SBT_HOME", "COURSIER_CACHE", "SCALA_CACHE"},
+			},
+		},
+		Path: []string{`{{join .InstallDir "bin"}}`},
+	},
+	"Kotlin": {
+		Variables: map[string]string{
+			"KOTLIN_HOME":           "{{.InstallDir}}",
+			"KOTLINC_OPTS":          "-Xmx2G -Xms512M",
+			"KOTLIN_COMPILER_OPTS":  "-Xjvm-default=enable -Xopt-in=kotlin.RequiresOptIn",
+			"KOTLIN_DAEMON_OPTS":    "-Xmx2G -Xms512M",
+			"KOTLIN_CACHE_DIR":      `{{join home ".kotlin" "cache"}}`,
+			"KOTLIN_COMPILER_CACHE": `{{join home ".kotlin" "daemon"}}`,
+		},
+		Options: []OptionGroup{
+			{
+				Name:        "Basic",
+				Description: "Basic Kotlin configuration",
+				Variables:   []string{"KOTLIN_HOME", "KOTLINC_OPTS", "KOTLIN_COMPILER_OPTS", "KOTLIN_DAEMON_OPTS", "KOTLIN_CACHE_DIR", "KOTLIN_COMPILER_CACHE"},
+			},
+		},
+		Path: []string{`{{join .InstallDir "bin"}}`},
+	},
+	"Erlang": {
+		Variables: map[string]string{
+			"ERLANG_HOME":        "{{.InstallDir}}",
+			"ERL_LIBS":           `{{join .InstallDir "lib"}}`,
+			"ERL_CRASH_DUMP":     `{{join home ".erlang_crash.dump"}}`,
+			"ERL_AFLAGS":         "-kernel shell_history enabled",
+			"ERL_EPMD_PORT":      "4369",
+			"ERL_MAX_PORTS":      "32768",
+			"ERL_MAX_ETS_TABLES": "32768",
+		},
+		Options: []OptionGroup{
+			{
+				Name:        "Basic",
+				Description: "Basic Erlang configuration",
+				Variables:   []string{"ERLANG_HOME", "ERL_LIBS", "ERL_CRASH_DUMP", "ERL_AFLAGS", "ERL_EPMD_PORT", "ERL_MAX_PORTS", "ERL_MAX_ETS_TABLES"},
+			},
+		},
+		Path: []string{`{{join .InstallDir "bin"}}`},
+	},
+	"Elixir": {
+		Variables: map[string]string{
+			"ELIXIR_HOME":        "{{.InstallDir}}",
+			"MIX_HOME":           `{{join home ".mix"}}`,
+			"HEX_HOME":           `{{join home ".hex"}}`,
+			"MIX_ARCHIVES":       `{{join home ".mix" "archives"}}`,
+			"MIX_DEBUG":          "1",
+			"MIX_ENV":            "dev",
+			"ELIXIR_EDITOR":      "code --wait",
+			"ELIXIR_ERL_OPTIONS": "-kernel shell_history enabled",
+		},
+		Options: []OptionGroup{
+			{
+				Name:        "Basic",
+				Description: "Basic Elixir configuration",
+				Variables:   []string{"ELIXIR_HOME", "MIX_HOME", "HEX_HOME", "MIX_ARCHIVES", "MIX_DEBUG", "MIX_ENV", "ELIXIR_EDITOR", "ELIXIR_ERL_OPTIONS"},
+			},
+		},
+		Path: []string{`{{join .InstallDir "bin"}}`},
+	},
+	"Docker": {
+		Variables: map[string]string{
+			"DOCKER_HOME":              "{{.InstallDir}}",
+			"DOCKER_CONFIG":            `{{join home ".docker"}}`,
+			"DOCKER_CLI_EXPERIMENTAL":  "enabled",
+			"DOCKER_BUILDKIT":          "1",
+			"COMPOSE_DOCKER_CLI_BUILD": "1",
+			"DOCKER_HOST":              "tcp://localhost:2375",
+		},
+		Options: []OptionGroup{
+			{
+				Name:        "Basic",
+				Description: "Basic Docker configuration",
+				Variables:   []string{"DOCKER_HOME", "DOCKER_CONFIG", "DOCKER_CLI_EXPERIMENTAL", "DOCKER_BUILDKIT", "COMPOSE_DOCKER_CLI_BUILD", "DOCKER_HOST"},
+			},
+		},
+		Path: []string{`{{join .InstallDir "bin"}}`},
+		// Elsewhere the client talks to the daemon over its default socket
+		WindowsOnly: []string{"DOCKER_HOST"},
+	},
+	"Kubernetes": {
+		Variables: map[string]string{
+			"KUBECONFIG":             `{{join home ".kube" "config"}}`,
+			"KUBE_EDITOR":            "code --wait",
+			"HELM_HOME":              `{{join home ".helm"}}`,
+			"HELM_REPOSITORY_CACHE":  `{{join home ".helm" "repository" "cache"}}`,
+			"HELM_REPOSITORY_CONFIG": `{{join home ".helm" "repository" "repositories.yaml"}}`,
+		},
+		Options: []OptionGroup{
+			{
+				Name:        "Basic",
+				Description: "Basic Kubernetes configuration",
+				Variables:   []string{"KUBECONFIG", "KUBE_EDITOR", "HELM_HOME", "HELM_REPOSITORY_CACHE", "HELM_REPOSITORY_CONFIG"},
+			},
+		},
+	},
+	"PostgreSQL": {
+		Variables: map[string]string{
+			"POSTGRES_HOME":     "{{.InstallDir}}",
+			"PGDATA":            `{{join .InstallDir "data"}}`,
+			"PGHOST":            "localhost",
+			"PGPORT":            "5432",
+			"PGLOCALEDIR":       `{{join .InstallDir "share" "locale"}}`,
+			"PGLOG":             `{{join .InstallDir "log" "postgresql.log"}}`,
+			"PGDATABASE":        "postgres",
+			"PGUSER":            "postgres",
+			"PGPASSWORD":        "postgres",
+			"PGTZ":              "UTC",
+			"PGCLIENTENCODING":  "UTF8",
+			"PGSSLMODE":         "prefer",
+			"PGCONNECT_TIMEOUT": "10",
+			"PGPOOL_PORT":       "9999",
+			"PGBOUNCER_PORT":    "6432",
+			"PGADMIN_PORT":      "5050",
+		},
+		Options: []OptionGroup{
+			{
+				Name:        "Basic",
+				Description: "Basic PostgreSQL configuration",
+				Variables:   []string{"POSTGRES_HOME", "PGDATA", "PGHOST", "PGPORT", "PGLOCALEDIR", "PGLOG", "PGDATABASE", "PGUSER", "PGPASSWORD", "PGTZ", "PGCLIENTENCODING", "PGSSLMODE", "PGCONNECT_TIMEOUT", "PGPOOL_PORT", "PGBOUNCER_PORT", "PGADMIN_PORT"},
+			},
+		},
+		Path: []string{`{{join .InstallDir "bin"}}`},
+	},
+	"MySQL": {
+		Variables: map[string]string{
+			"MYSQL_HOME":        "{{.InstallDir}}",
+			"MYSQL_TCP_PORT":    "3306",
+			"MYSQL_UNIX_PORT":   "3306",
+			"MYSQL_DATA_DIR":    `{{join .InstallDir "data"}}`,
+			"MYSQL_LOG_DIR":     `{{join .InstallDir "log"}}`,
+			"MYSQL_CONFIG_FILE": `{{if eq goos "windows"}}{{join .InstallDir "my.ini"}}{{else}}{{join .InstallDir "my.cnf"}}{{end}}`,
+		},
+		Options: []OptionGroup{
+			{
+				Name:        "Basic",
+				Description: "Basic MySQL configuration",
+				Variables:   []string{"MYSQL_HOME", "MYSQL_TCP_PORT", "MYSQL_UNIX_PORT", "MYSQL_DATA_DIR", "MYSQL_LOG_DIR", "MYSQL_CONFIG_FILE"},
+			},
+		},
+		Path: []string{`{{join .InstallDir "bin"}}`},
+	},
+	"MongoDB": {
+		Variables: map[string]string{
+			"MONGODB_HOME":   "{{.InstallDir}}",
+			"MONGO_DATA_DIR": `{{join .InstallDir "data" "db"}}`,
+			"MONGO_LOG_DIR":  `{{join .InstallDir "log"}}`,
+			"MONGO_CONFIG":   `{{join .InstallDir "mongod.cfg"}}`,
+			"MONGO_PORT":     "27017",
+		},
+		Options: []OptionGroup{
+			{
+				Name:        "Basic",
+				Description: "Basic MongoDB configuration",
+				Variables:   []string{"MONGODB_HOME", "MONGO_DATA_DIR", "MONGO_LOG_DIR", "MONGO_CONFIG", "MONGO_PORT"},
+			},
+		},
+		Path: []string{`{{join .InstallDir "bin"}}`},
+	},
+	"Redis": {
+		Variables: map[string]string{
+			"REDIS_HOME":        "{{.InstallDir}}",
+			"REDIS_PORT":        "6379",
+			"REDIS_CONFIG_FILE": `{{if eq goos "windows"}}{{join .InstallDir "redis.windows.conf"}}{{else}}{{join .InstallDir "redis.conf"}}{{end}}`,
+			"REDIS_DATA_DIR":    `{{join .InstallDir "data"}}`,
+			"REDIS_LOG_FILE":    `{{join .InstallDir "log" "redis.log"}}`,
+		},
+		Options: []OptionGroup{
+			{
+				Name:        "Basic",
+				Description: "Basic Redis configuration",
+				Variables:   []string{"REDIS_HOME", "REDIS_PORT", "REDIS_CONFIG_FILE", "REDIS_DATA_DIR", "REDIS_LOG_FILE"},
+			},
+		},
+		Path: []string{`{{join .InstallDir "bin"}}`},
+	},
+	"Elasticsearch": {
+		Variables: map[string]string{
+			"ES_HOME":           "{{.InstallDir}}",
+			"ES_PATH_CONF":      `{{join .InstallDir "config"}}`,
+			"ES_PATH_DATA":      `{{join .InstallDir "data"}}`,
+			"ES_PATH_LOGS":      `{{join .InstallDir "logs"}}`,
+			"ES_JAVA_OPTS":      "-Xms1g -Xmx1g",
+			"ES_PORT":           "9200",
+			"ES_TRANSPORT_PORT": "9300",
+		},
+		Options: []OptionGroup{
+			{
+				Name:        "Basic",
+				Description: "Basic Elasticsearch configuration",
+				Variables:   []string{"ES_HOME", "ES_PATH_CONF", "ES_PATH_DATA", "ES_PATH_LOGS", "ES_JAVA_OPTS", "ES_PORT", "ES_TRANSPORT_PORT"},
+			},
+		},
+		Path: []string{`{{join .InstallDir "bin"}}`},
+	},
+	"Oracle": {
+		Variables: map[string]string{
+			"ORACLE_HOME": "{{.InstallDir}}",
+			"ORACLE_BASE": "{{dir .InstallDir}}",
+			"ORACLE_SID":  "ORCL",
+			"NLS_LANG":    "AMERICAN_AMERICA.AL32UTF8",
+			"TNS_ADMIN":   `{{join .InstallDir "network" "admin"}}`,
+			"ORACLE_TERM": "xterm",
+		},
+		Options: []OptionGroup{
+			{
+				Name:        "Basic",
+				Description: "Basic Oracle configuration",
+				Variables:   []string{"ORACLE_HOME", "ORACLE_BASE", "ORACLE_SID", "NLS_LANG", "TNS_ADMIN", "ORACLE_TERM"},
+			},
+		},
+		Path: []string{`{{join .InstallDir "bin"}}`},
+	},
+	"Cassandra": {
+		Variables: map[string]string{
+			"CASSANDRA_HOME": "{{.InstallDir}}",
+			"CASSANDRA_CONF": `{{join .InstallDir "conf"}}`,
+			"CASSANDRA_DATA": `{{join .InstallDir "data"}}`,
+			"CASSANDRA_LOGS": `{{join .InstallDir "logs"}}`,
+			"MAX_HEAP_SIZE":  "1G",
+			"HEAP_NEWSIZE":   "250M",
+			"CASSANDRA_PORT": "9042",
+			"JMX_PORT":       "7199",
+		},
+		Options: []OptionGroup{
+			{
+				Name:        "Basic",
+				Description: "Basic Cassandra configuration",
+				Variables:   []string{"CASSANDRA_HOME", "CASSANDRA_CONF", "CASSANDRA_DATA", "CASSANDRA_LOGS", "MAX_HEAP_SIZE", "HEAP_NEWSIZE", "CASSANDRA_PORT", "JMX_PORT"},
+			},
+		},
+		Path: []string{`{{join .InstallDir "bin"}}`},
+	},
+	"Neo4j": {
+		Variables: map[string]string{
+			"NEO4J_HOME":                          "{{.InstallDir}}",
+			"NEO4J_CONF":                          `{{join .InstallDir "conf"}}`,
+			"NEO4J_DATA":                          `{{join .InstallDir "data"}}`,
+			"NEO4J_LOGS":                          `{{join .InstallDir "logs"}}`,
+			"NEO4J_HEAP_MEMORY":                   "4G",
+			"NEO4J_CACHE_MEMORY":                  "2G",
+			"NEO4J_PAGE_CACHE":                    "2G",
+			"NEO4J_HTTP_PORT":                     "7474",
+			"NEO4J_BOLT_PORT":                     "7687",
+			"NEO4J_HTTPS_PORT":                    "7473",
+			"NEO4J_ACCEPT_LICENSE_AGREEMENT":      "yes",
+			"NEO4J_AUTH":                          "neo4j/neo4j",
+			"NEO4J_dbms_memory_pagecache_size":    "2G",
+			"NEO4J_dbms_memory_heap_initial_size": "2G",
+			"NEO4J_dbms_memory_heap_max_size":     "4G",
+		},
+		Options: []OptionGroup{
+			{
+				Name:        "Basic",
+				Description: "Basic Neo4j configuration",
+				Variables:   []string{"NEO4J_HOME", "NEO4J_CONF", "NEO4J_DATA", "NEO4J_LOGS", "NEO4J_HEAP_MEMORY", "NEO4J_CACHE_MEMORY", "NEO4J_PAGE_CACHE", "NEO4J_HTTP_PORT", "NEO4J_BOLT_PORT", "NEO4J_HTTPS_PORT", "NEO4J_ACCEPT_LICENSE_AGREEMENT", "NEO4J_AUTH", "NEO4J_dbms_memory_pagecache_size", "NEO4J_dbms_memory_heap_initial_size", "NEO4J_dbms_memory_heap_max_size"},
+			},
+		},
+		Path: []string{`{{join .InstallDir "bin"}}`},
+	},
+	"InfluxDB": {
+		Variables: map[string]string{
+			"INFLUXDB_HOME":                       "{{.InstallDir}}",
+			"INFLUXDB_CONFIG_PATH":                `{{join .InstallDir "influxdb.conf"}}`,
+			"INFLUXDB_DATA_DIR":                   `{{join .InstallDir "data"}}`,
+			"INFLUXDB_META_DIR":                   `{{join .InstallDir "meta"}}`,
+			"INFLUXDB_WAL_DIR":                    `{{join .InstallDir "wal"}}`,
+			"INFLUXDB_HTTP_PORT":                  "8086",
+			"INFLUXDB_RPC_PORT":                   "8088",
+			"INFLUXDB_RETENTION":                  "52w",
+			"INFLUXDB_MAX_SERIES_PER_DATABASE":    "1000000",
+			"INFLUXDB_MAX_VALUES_PER_TAG":         "100000",
+			"INFLUXDB_CACHE_MAX_MEMORY_SIZE":      "1g",
+			"INFLUXDB_CACHE_SNAPSHOT_MEMORY_SIZE": "256m",
+			"INFLUXDB_QUERY_TIMEOUT":              "60s",
+			"INFLUXDB_HTTP_AUTH_ENABLED":          "true",
+			"INFLUXDB_ADMIN_USER":                 "admin",
+			"INFLUXDB_ADMIN_PASSWORD":             "admin",
+		},
+		Options: []OptionGroup{
+			{
+				Name:        "Basic",
+				Description: "Basic InfluxDB configuration",
+				Variables:   []string{"INFLUXDB_HOME", "INFLUXDB_CONFIG_PATH", "INFLUXDB_DATA_DIR", "INFLUXDB_META_DIR", "INFLUXDB_WAL_DIR", "INFLUXDB_HTTP_PORT", "INFLUXDB_RPC_PORT", "INFLUXDB_RETENTION", "INFLUXDB_MAX_SERIES_PER_DATABASE", "INFLUXDB_MAX_VALUES_PER_TAG", "INFLUXDB_CACHE_MAX_MEMORY_SIZE", "INFLUXDB_CACHE_SNAPSHOT_MEMORY_SIZE", "INFLUXDB_QUERY_TIMEOUT", "INFLUXDB_HTTP_AUTH_ENABLED", "INFLUXDB_ADMIN_USER", "INFLUXDB_ADMIN_PASSWORD"},
+			},
+		},
+		Path: []string{`{{join .InstallDir "bin"}}`},
+	},
+}
@@ -0,0 +1,100 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestRecipesUnixPaths renders every built-in recipe for a Unix install
+// directory and checks that each path it sets is absolute
+func TestRecipesUnixPaths(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("renders Unix paths")
+	}
+	root := t.TempDir()
+	t.Setenv("HOME", filepath.Join(root, "home"))
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(root, "config"))
+	t.Setenv("TMPDIR", filepath.Join(root, "tmp"))
+	for _, name := range []string{"APPDATA", "TEMP", "CLASSPATH"} {
+		t.Setenv(name, "")
+	}
+
+	names := make([]string, 0, len(builtinRecipes))
+	for name := range builtinRecipes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		recipe := builtinRecipes[name]
+		t.Run(name, func(t *testing.T) {
+			data, err := recipe.NewTemplateData(filepath.Join("/opt", "tool", "bin", "tool"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			expand := func(field, text string) string {
+				value, err := ExpandTemplate(text, data)
+				if err != nil {
+					t.Fatalf("%s: %v", field, err)
+				}
+				return value
+			}
+
+			for variable, text := range recipe.Variables {
+				// Only templated values are paths; the others are plain settings
+				if !recipe.Applies(variable) || !strings.Contains(text, "{{") {
+					continue
+				}
+				value := expand(variable, text)
+				if strings.Contains(value, ";") {
+					t.Errorf("%s=%q uses the Windows list separator", variable, value)
+				}
+				for _, entry := range filepath.SplitList(value) {
+					if !filepath.IsAbs(entry) {
+						t.Errorf("%s=%q holds the relative path %q", variable, value, entry)
+					}
+				}
+			}
+			for i, text := range append(append([]string(nil), recipe.Path...), recipe.CreateDirs...) {
+				if value := expand("path", text); value != "" && !filepath.IsAbs(value) {
+					t.Errorf("directory %d is the relative path %q", i, value)
+				}
+			}
+		})
+	}
+}
+
+func TestRecipeApplies(t *testing.T) {
+	recipe := &Recipe{
+		Variables:   map[string]string{"TOOL_HOME": "{{.InstallDir}}", "TOOL_HOST": "tcp://localhost:1"},
+		WindowsOnly: []string{"tool_host"},
+	}
+	if !recipe.Applies("TOOL_HOME") {
+		t.Error("TOOL_HOME does not apply")
+	}
+	if got, want := recipe.Applies("TOOL_HOST"), runtime.GOOS == "windows"; got != want {
+		t.Errorf("Applies(TOOL_HOST) = %v, want %v", got, want)
+	}
+
+	recipe.WindowsOnly = []string{"TOOL_PORT"}
+	if err := recipe.Validate(); err == nil || !strings.Contains(err.Error(), "windowsOnly[0]") {
+		t.Errorf("Validate() = %v, want an unknown windowsOnly variable", err)
+	}
+}
+
+func TestConfigDirTemplate(t *testing.T) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		t.Skip(err)
+	}
+	got, err := ExpandTemplate(`{{join configdir "npm"}}`, TemplateData{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "npm"); got != want {
+		t.Errorf("configdir template = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"devpathpro/pkg/version"
+)
+
+// Preferences of a selection policy
+const (
+	PreferNewest = "newest"
+	PreferLTS    = "lts"
+	Prefer64Bit  = "64bit"
+)
+
+// SelectionPolicy chooses among several installations of a program without asking
+type SelectionPolicy struct {
+	// Constraint excludes installations whose version does not match, e.g. ">=17 <22"
+	Constraint string `json:"constraint,omitempty"`
+	// Root prefers installations under this directory over all other preferences
+	Root string `json:"root,omitempty"`
+	// Prefer lists newest, lts and 64bit in order of priority
+	Prefer []string `json:"prefer,omitempty"`
+}
+
+// Validate checks the constraint and preferences of the policy
+func (s *SelectionPolicy) Validate() error {
+	if s.Constraint != "" {
+		if _, err := version.ParseConstraint(s.Constraint); err != nil {
+			return err
+		}
+	}
+	for _, prefer := range s.Prefer {
+		switch prefer {
+		case PreferNewest, PreferLTS, Prefer64Bit:
+		default:
+			return fmt.Errorf("unknown preference %q: expected %s, %s or %s", prefer, PreferNewest, PreferLTS, Prefer64Bit)
+		}
+	}
+	return nil
+}
+
+// Merge returns the policy with the fields set in override replacing its own
+func (s *SelectionPolicy) Merge(override *SelectionPolicy) *SelectionPolicy {
+	if s == nil {
+		return override
+	}
+	if override == nil {
+		return s
+	}
+	merged := *s
+	if override.Constraint != "" {
+		merged.Constraint = override.Constraint
+	}
+	if override.Root != "" {
+		merged.Root = override.Root
+	}
+	if len(override.Prefer) > 0 {
+		merged.Prefer = override.Prefer
+	}
+	return &merged
+}
+
+// ApplySelection merges policies, keyed by program name, into the programs.
+// A policy for "*" applies to every program, before its own policy.
+func ApplySelection(programs []Program, policies map[string]*SelectionPolicy) {
+	for i := range programs {
+		prog := &programs[i]
+		prog.Selection = prog.Selection.Merge(policies["*"])
+		for name, policy := range policies {
+			if strings.EqualFold(name, prog.Name) {
+				prog.Selection = prog.Selection.Merge(policy)
+			}
+		}
+	}
+}
+
+// validateSelection checks policies keyed by program name
+func validateSelection(policies map[string]*SelectionPolicy) error {
+	for name, policy := range policies {
+		if policy == nil {
+			continue
+		}
+		if err := policy.Validate(); err != nil {
+			return fmt.Errorf("selection.%s: %v", name, err)
+		}
+	}
+	return nil
+}
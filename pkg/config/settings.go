@@ -0,0 +1,182 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"devpathpro/pkg/registry"
+	"devpathpro/pkg/shell"
+	"devpathpro/pkg/utils"
+)
+
+// Settings are DevPathPro's own preferences, stored as JSON in the user's config directory
+type Settings struct {
+	// DefaultScope is used when no scope is given on the command line;
+	// empty uses each tool's default scope
+	DefaultScope registry.Scope `json:"defaultScope,omitempty"`
+	// BackupDir is the backup root; empty uses the per-user default
+	BackupDir string `json:"backupDir,omitempty"`
+	// BackupKeep is the number of backups to keep; 0 keeps all
+	BackupKeep int `json:"backupKeep,omitempty"`
+	// BackupMaxAge is how long backups are kept, e.g. "720h"; empty keeps them forever
+	BackupMaxAge string `json:"backupMaxAge,omitempty"`
+	// BackupCompress bundles each backup into a zip archive
+	BackupCompress bool `json:"backupCompress,omitempty"`
+	// LogLevel is debug, info, warn, error or off
+	LogLevel string `json:"logLevel,omitempty"`
+	// ExcludedSearchDirs are skipped when searching for programs, by name or full path
+	ExcludedSearchDirs []string `json:"excludedSearchDirs,omitempty"`
+	// DeepSearchDrives limits deep search to these drive letters; empty searches all drives.
+	// Only Windows has drive letters; elsewhere deep search starts at the root.
+	DeepSearchDrives []string `json:"deepSearchDrives,omitempty"`
+	// CatalogDir holds catalog files of program definitions; empty uses the per-user default
+	CatalogDir string `json:"catalogDir,omitempty"`
+	// CustomPrograms are merged over the built-in and catalog programs, replacing those with the same name
+	CustomPrograms []Program `json:"customPrograms,omitempty"`
+	// DefaultOptions are the option groups preselected per tool, e.g. {"Python": ["Basic", "Pip"]}
+	DefaultOptions map[string][]string `json:"defaultOptions,omitempty"`
+	// Selection sets selection policies by program name, or "*" for all programs
+	Selection map[string]*SelectionPolicy `json:"selection,omitempty"`
+	// Checks enable, disable or change the severity of verify checks by ID, e.g. {"DPP003": {"enabled": false}}
+	Checks map[string]CheckSettings `json:"checks,omitempty"`
+	// Shells get the tool variables in a managed block of their startup files,
+	// rewritten whenever the environment changes: powershell, bash, zsh or fish.
+	// Windows only; elsewhere the variables always live in the startup files.
+	Shells []string `json:"shells,omitempty"`
+	// ShellProfiles are more startup files to keep in sync, e.g. a .bashrc inside WSL
+	ShellProfiles []shell.Profile `json:"shellProfiles,omitempty"`
+	// Profiles are named sets of tool selections to switch between with "devpathpro profile switch"
+	Profiles []Profile `json:"profiles,omitempty"`
+}
+
+// DefaultSettingsPath returns the location of the settings file in the user's config directory
+func DefaultSettingsPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "settings.json"
+	}
+	return filepath.Join(dir, "DevPathPro", "settings.json")
+}
+
+// LoadSettings reads the settings file. A missing file yields empty settings.
+func LoadSettings(path string) (*Settings, error) {
+	settings := &Settings{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return settings, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading settings: %v", err)
+	}
+	if err := json.Unmarshal(data, settings); err != nil {
+		return nil, fmt.Errorf("error parsing settings %s: %v", path, err)
+	}
+	if err := settings.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid settings %s: %v", path, err)
+	}
+	return settings, nil
+}
+
+// SaveSettings validates the settings and writes them to path
+func SaveSettings(path string, settings *Settings) error {
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding settings: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("error creating settings directory: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("error writing settings: %v", err)
+	}
+	return nil
+}
+
+// Validate checks the values that have a fixed format
+func (s *Settings) Validate() error {
+	if s.DefaultScope != "" {
+		if _, err := registry.ParseScope(string(s.DefaultScope)); err != nil {
+			return fmt.Errorf("defaultScope: %v", err)
+		}
+	}
+	if s.BackupKeep < 0 {
+		return fmt.Errorf("backupKeep must not be negative")
+	}
+	if _, err := s.BackupAge(); err != nil {
+		return err
+	}
+	if _, err := utils.ParseLogLevel(s.LogLevel); err != nil {
+		return fmt.Errorf("logLevel: %v", err)
+	}
+	for _, drive := range s.DeepSearchDrives {
+		if len(strings.TrimSuffix(drive, ":")) != 1 {
+			return fmt.Errorf("deepSearchDrives: %q is not a drive letter", drive)
+		}
+	}
+	if err := validateSelection(s.Selection); err != nil {
+		return err
+	}
+	if err := validateChecks(s.Checks); err != nil {
+		return err
+	}
+	if err := validateShells(s.Shells, s.ShellProfiles); err != nil {
+		return err
+	}
+	if err := validateProfiles(s.Profiles); err != nil {
+		return err
+	}
+	for i := range s.CustomPrograms {
+		if err := s.CustomPrograms[i].Validate(); err != nil {
+			return fmt.Errorf("customPrograms[%d] %s: %v", i, s.CustomPrograms[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// BackupAge returns BackupMaxAge as a duration; 0 means no limit
+func (s *Settings) BackupAge() (time.Duration, error) {
+	if s.BackupMaxAge == "" {
+		return 0, nil
+	}
+	age, err := time.ParseDuration(s.BackupMaxAge)
+	if err != nil || age < 0 {
+		return 0, fmt.Errorf("backupMaxAge: invalid duration %q", s.BackupMaxAge)
+	}
+	return age, nil
+}
+
+// DefaultOptionsFor returns the option groups preselected for a tool
+func (s *Settings) DefaultOptionsFor(tool string) []string {
+	if s == nil {
+		return nil
+	}
+	for name, options := range s.DefaultOptions {
+		if strings.EqualFold(name, tool) {
+			return options
+		}
+	}
+	return nil
+}
+
+// NewConfiguration builds the configuration used by the CLI and GUI from settings,
+// loading the catalog files. Its programs become the ones verify checks.
+func NewConfiguration(settings *Settings, settingsPath string) (*Configuration, error) {
+	programs, err := LoadPrograms(settings)
+	if err != nil {
+		return nil, err
+	}
+	checkedPrograms = programs
+	return &Configuration{
+		LogFile:      "devpathpro.log",
+		Programs:     programs,
+		Settings:     settings,
+		SettingsPath: settingsPath,
+	}, nil
+}
@@ -0,0 +1,232 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"devpathpro/pkg/plan"
+	"devpathpro/pkg/registry"
+)
+
+// shadowCheck reports tools whose executable, looked up through PATH, is not
+// the installation their home variable points to: an earlier PATH entry such
+// as a WindowsApps stub, an old JRE in System32 or Git's usr\bin wins instead
+type shadowCheck struct{ checkInfo }
+
+func (shadowCheck) Run(ctx context.Context, env *CheckEnv) []ConfigurationIssue {
+	var issues []ConfigurationIssue
+	entries := pathEntries(env)
+
+	for _, prog := range env.Programs {
+		if prog.ExecutableName == "" {
+			continue
+		}
+		found := lookPath(entries, prog.Executable(), env.Expand)
+		if len(found) == 0 {
+			continue
+		}
+		winner := found[0]
+
+		variable, home := setHomeVariable(env, prog)
+		if home != "" {
+			if registry.IsUnderPath(winner.path, env.Expand(home)) {
+				continue
+			}
+			issues = append(issues, shadowIssue(prog, winner, found, variable, home, env.Expand, func(m pathMatch) bool {
+				return registry.IsUnderPath(m.path, env.Expand(home))
+			}))
+			continue
+		}
+
+		// Without a home variable only an app execution alias winning over
+		// a real installation later in PATH is known to be wrong
+		if isAppAlias(winner.path) && len(found) > 1 {
+			issues = append(issues, shadowIssue(prog, winner, found, "", "", env.Expand, func(m pathMatch) bool {
+				return !isAppAlias(m.path)
+			}))
+		}
+	}
+	return issues
+}
+
+// PlanFix moves the PATH entry of the configured installation right before
+// the entry shadowing it, in the user or machine PATH holding that entry, and
+// inserts it there if it is missing. Copies in the other scope are removed so
+// the move does not leave a duplicate. If neither scope holds the shadowing
+// entry, as when a startup script adds it, the entry is put first in the user PATH.
+func (shadowCheck) PlanFix(ctx context.Context, env *CheckEnv, issue ConfigurationIssue, p *plan.ChangePlan) error {
+	expected, winner := issue.Data["expectedEntry"], issue.Data["entry"]
+	if issue.Data["problem"] != ProblemShadowed || expected == "" {
+		return nil
+	}
+	same := func(a, b string) bool { return samePathEntry(env, a, b) }
+
+	target := registry.Scope("")
+	for _, scope := range pathScopes {
+		value, _, err := p.Value(checkStore(env), scope, registry.PathVariable)
+		if err != nil {
+			return err
+		}
+		if indexOfEntry(registry.SplitPathList(value), winner, same) >= 0 {
+			target = scope
+			break
+		}
+	}
+	if target == "" {
+		target, winner = registry.ScopeUser, ""
+	}
+
+	for _, scope := range pathScopes {
+		scope := scope
+		err := editPath(env, p, scope, func(entries []string) []string {
+			if scope == target {
+				return moveBefore(entries, expected, winner, same)
+			}
+			var rest []string
+			for _, e := range entries {
+				if !same(e, expected) {
+					rest = append(rest, e)
+				}
+			}
+			return rest
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pathMatch is an executable found in a PATH entry
+type pathMatch struct {
+	entry string
+	// position is the index of the entry in PATH
+	position int
+	path     string
+}
+
+// lookPath returns every copy of an executable in PATH order; the first one is
+// what runs. Entries are resolved with expand, so %VAR% references work on Windows.
+func lookPath(entries []string, executable string, expand func(string) string) []pathMatch {
+	var found []pathMatch
+	for i, entry := range entries {
+		if entry == "" {
+			continue
+		}
+		path := filepath.Join(expand(entry), executable)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			found = append(found, pathMatch{entry: entry, position: i, path: path})
+		}
+	}
+	return found
+}
+
+// setHomeVariable returns the first home variable of a program that is set and its value
+func setHomeVariable(env *CheckEnv, prog Program) (string, string) {
+	for _, name := range prog.HomeVariables() {
+		if home := env.Getenv(name); home != "" {
+			return name, home
+		}
+	}
+	return "", ""
+}
+
+// shadowIssue describes the winner shadowing the first match that is
+// expected, or the installation in home when none is
+func shadowIssue(prog Program, winner pathMatch, found []pathMatch, variable, home string, expand func(string) string, expected func(pathMatch) bool) ConfigurationIssue {
+	data := map[string]string{
+		"problem":    ProblemShadowed,
+		"program":    prog.Name,
+		"executable": winner.path,
+		"entry":      winner.entry,
+		"position":   strconv.Itoa(winner.position + 1),
+	}
+	if home != "" {
+		data["variable"] = variable
+		data["home"] = home
+	}
+
+	issue := ConfigurationIssue{
+		Value: winner.path,
+		Data:  data,
+	}
+	if home != "" {
+		issue.Description = fmt.Sprintf("%s runs %s from PATH entry %s instead of the installation in %s=%s",
+			prog.Executable(), winner.path, winner.entry, variable, home)
+	} else {
+		issue.Description = fmt.Sprintf("%s runs the app execution alias %s instead of an installed %s",
+			prog.Executable(), winner.path, prog.Name)
+	}
+
+	for _, m := range found[1:] {
+		if expected(m) {
+			data["expectedEntry"] = m.entry
+			data["expectedPosition"] = strconv.Itoa(m.position + 1)
+			issue.Solution = fmt.Sprintf("Move PATH entry %s (position %d) before %s (position %d)",
+				m.entry, m.position+1, winner.entry, winner.position+1)
+			issue.Fixable = true
+			return issue
+		}
+	}
+	if home != "" {
+		dir := homeBinDir(home, prog.Executable(), expand)
+		data["expectedEntry"] = dir
+		issue.Solution = fmt.Sprintf("Add %s to PATH before %s (position %d)", dir, winner.entry, winner.position+1)
+		issue.Fixable = true
+	} else {
+		issue.Solution = fmt.Sprintf("Turn off the %s app execution alias in Windows settings", prog.Executable())
+	}
+	return issue
+}
+
+// homeBinDir returns the directory under home holding the executable: its
+// bin directory, else home itself, else the bin directory it should be in
+func homeBinDir(home, executable string, expand func(string) string) string {
+	bin := filepath.Join(home, "bin")
+	for _, dir := range []string{bin, home} {
+		if info, err := os.Stat(filepath.Join(expand(dir), executable)); err == nil && !info.IsDir() {
+			return dir
+		}
+	}
+	return bin
+}
+
+// isAppAlias reports whether path is a Windows app execution alias, the empty
+// stub files in WindowsApps that open the Microsoft Store
+func isAppAlias(path string) bool {
+	if !strings.Contains(strings.ToLower(path), `\microsoft\windowsapps\`) {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.Size() == 0
+}
+
+// moveBefore moves entry so that it comes right before target, inserting it
+// if it is missing; it goes first if target is missing
+func moveBefore(entries []string, entry, target string, same func(a, b string) bool) []string {
+	moved := entry
+	if i := indexOfEntry(entries, entry, same); i >= 0 {
+		moved = entries[i]
+		entries = append(append([]string(nil), entries[:i]...), entries[i+1:]...)
+	}
+	i := indexOfEntry(entries, target, same)
+	if target == "" || i < 0 {
+		i = 0
+	}
+	return append(append(append([]string(nil), entries[:i]...), moved), entries[i:]...)
+}
+
+// indexOfEntry returns the index of the first of entries naming the same
+// directory as entry, or -1
+func indexOfEntry(entries []string, entry string, same func(a, b string) bool) int {
+	for i, e := range entries {
+		if same(e, entry) {
+			return i
+		}
+	}
+	return -1
+}
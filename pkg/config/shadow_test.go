@@ -0,0 +1,184 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"devpathpro/pkg/plan"
+	"devpathpro/pkg/registry"
+)
+
+// shadowFixture creates an old installation and a JDK whose bin directory
+// holds the executable of prog and returns their directories
+func shadowFixture(t *testing.T, prog Program) (string, string) {
+	t.Helper()
+	root := t.TempDir()
+	old := filepath.Join(root, "oldjre", "bin")
+	home := filepath.Join(root, "jdk-21")
+	for _, dir := range []string{old, filepath.Join(home, "bin")} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, prog.Executable()), []byte("#!/bin/sh\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return old, home
+}
+
+func shadowEnv(prog Program, vars map[string]string) *CheckEnv {
+	return &CheckEnv{
+		Getenv:   func(name string) string { return vars[name] },
+		Programs: []Program{prog},
+		Store:    registry.NewMemoryStore(),
+	}
+}
+
+var shadowJava = Program{
+	Name:           "Java",
+	ExecutableName: "javac.exe",
+	Recipe:         &Recipe{Variables: map[string]string{"JAVA_HOME": "{{.InstallDir}}"}},
+}
+
+func TestShadowCheckReordersHomeEntry(t *testing.T) {
+	old, home := shadowFixture(t, shadowJava)
+	bin := filepath.Join(home, "bin")
+	env := shadowEnv(shadowJava, map[string]string{
+		"JAVA_HOME":           home,
+		registry.PathVariable: registry.JoinPathList([]string{old, bin}),
+	})
+
+	issues := shadowCheck{}.Run(context.Background(), env)
+	if len(issues) != 1 {
+		t.Fatalf("got %d issues, want 1: %+v", len(issues), issues)
+	}
+	issue := issues[0]
+	if !issue.Fixable {
+		t.Errorf("issue is not fixable: %+v", issue)
+	}
+	if got := issue.Data["expectedEntry"]; got != bin {
+		t.Errorf("expectedEntry = %q, want %q", got, bin)
+	}
+	if got := issue.Data["variable"]; got != "JAVA_HOME" {
+		t.Errorf("variable = %q, want JAVA_HOME", got)
+	}
+	if !strings.Contains(issue.Description, "JAVA_HOME="+home) {
+		t.Errorf("description does not name JAVA_HOME: %s", issue.Description)
+	}
+}
+
+func TestShadowCheckAddsMissingHomeEntry(t *testing.T) {
+	old, home := shadowFixture(t, shadowJava)
+	bin := filepath.Join(home, "bin")
+	env := shadowEnv(shadowJava, map[string]string{
+		"JAVA_HOME":           home,
+		registry.PathVariable: old,
+	})
+
+	issues := shadowCheck{}.Run(context.Background(), env)
+	if len(issues) != 1 {
+		t.Fatalf("got %d issues, want 1: %+v", len(issues), issues)
+	}
+	issue := issues[0]
+	if !issue.Fixable || issue.Data["expectedEntry"] != bin {
+		t.Fatalf("want a fixable issue adding %s, got %+v", bin, issue)
+	}
+
+	// The fix goes to the stored user PATH, not the process environment
+	if err := env.Store.Set(registry.ScopeUser, registry.PathVariable, old); err != nil {
+		t.Fatal(err)
+	}
+	p := plan.New()
+	if err := (shadowCheck{}).PlanFix(context.Background(), env, issue, p); err != nil {
+		t.Fatal(err)
+	}
+	got, _, err := p.Value(env.Store, registry.ScopeUser, registry.PathVariable)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := registry.JoinPathList([]string{bin, old}); got != want {
+		t.Errorf("user PATH after fix = %q, want %q", got, want)
+	}
+}
+
+func TestShadowCheckFixMovesEntryIntoWinnerScope(t *testing.T) {
+	old, home := shadowFixture(t, shadowJava)
+	bin := filepath.Join(home, "bin")
+	other := filepath.Join(t.TempDir(), "other")
+	env := shadowEnv(shadowJava, map[string]string{
+		"JAVA_HOME":           home,
+		registry.PathVariable: registry.JoinPathList([]string{other, old, bin}),
+	})
+	// The old JRE is in the machine PATH, which comes first, and the JDK in the user PATH
+	stored := map[registry.Scope][]string{
+		registry.ScopeMachine: {other, old},
+		registry.ScopeUser:    {bin},
+	}
+	for scope, entries := range stored {
+		if err := env.Store.Set(scope, registry.PathVariable, registry.JoinPathList(entries)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	issues := shadowCheck{}.Run(context.Background(), env)
+	if len(issues) != 1 {
+		t.Fatalf("got %d issues, want 1: %+v", len(issues), issues)
+	}
+	p := plan.New()
+	if err := (shadowCheck{}).PlanFix(context.Background(), env, issues[0], p); err != nil {
+		t.Fatal(err)
+	}
+	want := map[registry.Scope]string{
+		registry.ScopeMachine: registry.JoinPathList([]string{other, bin, old}),
+		registry.ScopeUser:    "",
+	}
+	for scope, value := range want {
+		got, _, err := p.Value(env.Store, scope, registry.PathVariable)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != value {
+			t.Errorf("%s PATH after fix = %q, want %q", scope, got, value)
+		}
+	}
+	if !p.RequiresAdmin() {
+		t.Error("a fix of the machine PATH does not require administrator privileges")
+	}
+}
+
+func TestShadowCheckAcceptsHomeEntry(t *testing.T) {
+	old, home := shadowFixture(t, shadowJava)
+	env := shadowEnv(shadowJava, map[string]string{
+		"JAVA_HOME":           home,
+		registry.PathVariable: registry.JoinPathList([]string{filepath.Join(home, "bin"), old}),
+	})
+
+	if issues := (shadowCheck{}).Run(context.Background(), env); len(issues) != 0 {
+		t.Errorf("got issues for the configured installation: %+v", issues)
+	}
+}
+
+func TestShadowCheckResolvesStoreVariables(t *testing.T) {
+	old, home := shadowFixture(t, shadowJava)
+	// The entry references a variable only the store has, as after configuring
+	// a tool without restarting
+	entry := filepath.Join(registry.VarReference("TOOLS_JDK"), "bin")
+	env := shadowEnv(shadowJava, map[string]string{
+		"JAVA_HOME":           home,
+		registry.PathVariable: registry.JoinPathList([]string{old, entry}),
+	})
+	if err := env.Store.Set(registry.ScopeUser, "TOOLS_JDK", home); err != nil {
+		t.Fatal(err)
+	}
+
+	issues := shadowCheck{}.Run(context.Background(), env)
+	if len(issues) != 1 {
+		t.Fatalf("got %d issues, want 1: %+v", len(issues), issues)
+	}
+	if got := issues[0].Data["expectedEntry"]; got != entry {
+		t.Errorf("expectedEntry = %q, want %q", got, entry)
+	}
+}
@@ -0,0 +1,385 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"devpathpro/pkg/registry"
+	"devpathpro/pkg/shell"
+	"devpathpro/pkg/utils"
+)
+
+// Shell profile states reported by ShellProfileState
+const (
+	ShellInSync  = "in sync"
+	ShellMissing = "missing"
+	ShellDrifted = "drifted"
+)
+
+// shellProfiles are the startup files kept in sync with the environment
+var shellProfiles []shell.Profile
+
+// ConfigureShells sets the startup files that get a managed block with the
+// tool variables: the profiles of the named shells followed by the others
+// given. The settings are expected to be valid.
+func ConfigureShells(shells []string, profiles []shell.Profile) {
+	home, _ := os.UserHomeDir()
+	detected, _ := shell.WindowsProfiles(home, shells)
+	shellProfiles = append(detected, profiles...)
+}
+
+// ShellProfiles returns the startup files kept in sync with the environment
+func ShellProfiles() []shell.Profile {
+	return append([]shell.Profile(nil), shellProfiles...)
+}
+
+// KnownShellProfiles returns the configured startup files followed, on
+// Windows, by those of every supported shell, so that blocks written under
+// earlier settings are found as well
+func KnownShellProfiles() []shell.Profile {
+	profiles := ShellProfiles()
+	if !shellIntegration {
+		return profiles
+	}
+	home, _ := os.UserHomeDir()
+	all, _ := shell.WindowsProfiles(home, shell.Shells)
+	for _, p := range all {
+		known := false
+		for _, q := range profiles {
+			if registry.SamePath(p.Path, q.Path) {
+				known = true
+				break
+			}
+		}
+		if !known {
+			profiles = append(profiles, p)
+		}
+	}
+	return profiles
+}
+
+// ShellEnv returns what the managed blocks set: the variables of the known
+// tools, from the user scope or else the machine scope, and the PATH entries
+// of both scopes that belong to a tool, machine first as Windows searches
+// them. Variable references are expanded since the shells do not share them.
+func ShellEnv(store registry.EnvStore, programs []Program) (shell.Env, error) {
+	stored := func(name string) (string, bool) {
+		for _, scope := range []registry.Scope{registry.ScopeUser, registry.ScopeMachine} {
+			if value, ok, err := store.Get(scope, name); err == nil && ok {
+				return value, true
+			}
+		}
+		return "", false
+	}
+	lookup := func(name string) (string, bool) {
+		if value, ok := stored(name); ok {
+			return value, true
+		}
+		return os.LookupEnv(name)
+	}
+
+	env := shell.Env{Vars: make(map[string]string)}
+	for _, prog := range programs {
+		names := []string{prog.EnvVar}
+		if prog.Recipe != nil {
+			for name := range prog.Recipe.Variables {
+				names = append(names, name)
+			}
+		}
+		for _, name := range names {
+			if name == "" || strings.EqualFold(name, registry.PathVariable) {
+				continue
+			}
+			if value, ok := stored(name); ok {
+				env.Vars[name] = registry.ExpandVars(value, lookup)
+			}
+		}
+	}
+
+	for _, scope := range []registry.Scope{registry.ScopeMachine, registry.ScopeUser} {
+		value, _, err := store.Get(scope, registry.PathVariable)
+		if err != nil {
+			return shell.Env{}, fmt.Errorf("failed to read %s PATH: %v", scope, err)
+		}
+		for _, entry := range registry.SplitPathList(value) {
+			entry = registry.ExpandVars(entry, lookup)
+			if registry.ContainsPath(env.Path, entry) || FindOwner(entry, programs, lookup) == "" {
+				continue
+			}
+			env.Path = append(env.Path, entry)
+		}
+	}
+	return env, nil
+}
+
+// ShellProfileState compares the managed block of a profile with the one env
+// gives: ShellInSync, ShellMissing if the profile has no block but should,
+// or ShellDrifted if its block differs or should be gone
+func ShellProfileState(profile shell.Profile, env shell.Env) (string, error) {
+	current, ok, err := shell.ReadBlock(profile.Path)
+	if err != nil {
+		return "", err
+	}
+	if env.Empty() {
+		if ok {
+			return ShellDrifted, nil
+		}
+		return ShellInSync, nil
+	}
+	expected, err := profile.Render(env)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case !ok:
+		return ShellMissing, nil
+	case normalizeNewlines(current) != expected:
+		return ShellDrifted, nil
+	}
+	return ShellInSync, nil
+}
+
+// SyncShellProfile writes the managed block env gives into a profile, or
+// removes the block if env is empty. It reports whether the file changed.
+func SyncShellProfile(profile shell.Profile, env shell.Env) (bool, error) {
+	if env.Empty() {
+		return shell.RemoveBlock(profile)
+	}
+	block, err := profile.Render(env)
+	if err != nil {
+		return false, err
+	}
+	return shell.WriteBlock(profile.Path, block)
+}
+
+// SyncShells writes the managed block into every configured startup file and
+// returns the paths of the files that changed
+func SyncShells(store registry.EnvStore, programs []Program) ([]string, error) {
+	if len(shellProfiles) == 0 {
+		return nil, nil
+	}
+	env, err := ShellEnv(store, programs)
+	if err != nil {
+		return nil, err
+	}
+	var changed []string
+	for _, profile := range shellProfiles {
+		updated, err := SyncShellProfile(profile, env)
+		if err != nil {
+			return changed, err
+		}
+		if updated {
+			changed = append(changed, profile.Path)
+		}
+	}
+	return changed, nil
+}
+
+// RemoveShellBlocks removes the managed block from the given startup files
+// and returns the paths of the files that changed
+func RemoveShellBlocks(profiles []shell.Profile) ([]string, error) {
+	var changed []string
+	for _, profile := range profiles {
+		updated, err := shell.RemoveBlock(profile)
+		if err != nil {
+			return changed, err
+		}
+		if updated {
+			changed = append(changed, profile.Path)
+		}
+	}
+	return changed, nil
+}
+
+// shellSyncStore rewrites the configured startup files once a transaction
+// changing the user or machine scope of the store it wraps ends
+type shellSyncStore struct {
+	registry.EnvStore
+	programs []Program
+	// dirty is set by changes the startup files don't reflect yet
+	dirty bool
+}
+
+// NewShellSyncStore returns a store that keeps the configured startup files
+// in sync with store, rewriting them when a backup transaction commits or
+// rolls back. Without configured startup files it returns store.
+// Failing to update a startup file is logged, not returned: the variables
+// were written and the drift check reports the file.
+func NewShellSyncStore(store registry.EnvStore, programs []Program) registry.EnvStore {
+	if len(shellProfiles) == 0 {
+		return store
+	}
+	return &shellSyncStore{EnvStore: store, programs: programs}
+}
+
+// OpenStore opens the backend named by a -store value (see registry.OpenStore).
+// Only the default store is copied to the shell startup files, so the memory
+// and file stores leave the real environment untouched.
+func OpenStore(spec string, programs []Program) (registry.EnvStore, error) {
+	store, err := registry.OpenStore(spec)
+	if err != nil {
+		return nil, err
+	}
+	switch store.(type) {
+	case *registry.MemoryStore, *registry.FileStore:
+		return store, nil
+	}
+	return NewShellSyncStore(store, programs), nil
+}
+
+// Set writes a variable; the startup files are rewritten by Sync
+func (s *shellSyncStore) Set(scope registry.Scope, name, value string) error {
+	s.changed(scope)
+	return s.EnvStore.Set(scope, name, value)
+}
+
+// Delete removes a variable; the startup files are rewritten by Sync
+func (s *shellSyncStore) Delete(scope registry.Scope, name string) error {
+	s.changed(scope)
+	return s.EnvStore.Delete(scope, name)
+}
+
+// Sync rewrites the startup files if the user or machine scope changed since the last call
+func (s *shellSyncStore) Sync() {
+	if !s.dirty {
+		return
+	}
+	s.dirty = false
+	if _, err := SyncShells(s.EnvStore, s.programs); err != nil {
+		utils.Warnf("failed to update shell profiles: %v", err)
+	}
+}
+
+// changed marks the startup files for rewriting after a change to scope
+func (s *shellSyncStore) changed(scope registry.Scope) {
+	if scope != registry.ScopeProcess {
+		s.dirty = true
+	}
+}
+
+// shellCheck reports configured startup files whose managed block is missing
+// or no longer matches the variables in the store
+type shellCheck struct{ checkInfo }
+
+func (shellCheck) Run(ctx context.Context, env *CheckEnv) []ConfigurationIssue {
+	if len(shellProfiles) == 0 {
+		return nil
+	}
+	expected, err := ShellEnv(checkStore(env), env.Programs)
+	if err != nil {
+		return []ConfigurationIssue{{
+			Description: "Cannot read the variables shell profiles are compared with",
+			Value:       err.Error(),
+			Solution:    "Check access to the environment variables",
+		}}
+	}
+
+	var issues []ConfigurationIssue
+	for _, profile := range shellProfiles {
+		state, err := ShellProfileState(profile, expected)
+		if err != nil {
+			issues = append(issues, ConfigurationIssue{
+				Description: "Shell profile cannot be read",
+				Value:       profile.Path,
+				Solution:    err.Error(),
+			})
+			continue
+		}
+		if state == ShellInSync {
+			continue
+		}
+		description := fmt.Sprintf("%s profile has no DevPathPro block", profile.Kind)
+		if state == ShellDrifted {
+			description = fmt.Sprintf("%s profile block differs from the registry", profile.Kind)
+		}
+		issues = append(issues, ConfigurationIssue{
+			Description: description,
+			Value:       profile.Path,
+			Solution:    "Run \"devpathpro shell sync\" to rewrite the block",
+			Data:        map[string]string{"problem": ProblemDrift, "state": state, "profile": profile.Path},
+			Fixable:     true,
+		})
+	}
+	return issues
+}
+
+// Fix rewrites the managed block of the profile
+func (shellCheck) Fix(ctx context.Context, env *CheckEnv, issue ConfigurationIssue) error {
+	if issue.Data["problem"] != ProblemDrift {
+		return nil
+	}
+	expected, err := ShellEnv(checkStore(env), env.Programs)
+	if err != nil {
+		return err
+	}
+	for _, profile := range shellProfiles {
+		if profile.Path == issue.Data["profile"] {
+			if _, err := SyncShellProfile(profile, expected); err != nil {
+				return fmt.Errorf("failed to update %s: %v", profile.Path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// checkStore returns the store of a check environment, or the default
+// store when checks only inspect the process environment
+func checkStore(env *CheckEnv) registry.EnvStore {
+	if env.Store != nil {
+		return env.Store
+	}
+	return registry.NewDefaultStore()
+}
+
+// FindOwner returns the name of the tool a directory belongs to: the first
+// program whose executable is in it, else the first whose home variable,
+// read with lookup, contains it. It returns the empty string for other directories.
+func FindOwner(dir string, programs []Program, lookup func(string) (string, bool)) string {
+	for _, prog := range programs {
+		if prog.ExecutableName == "" {
+			continue
+		}
+		if info, err := os.Stat(filepath.Join(dir, prog.Executable())); err == nil && !info.IsDir() {
+			return prog.Name
+		}
+	}
+	for _, prog := range programs {
+		for _, name := range prog.HomeVariables() {
+			if home, ok := lookup(name); ok && registry.IsUnderPath(dir, registry.ExpandVars(home, lookup)) {
+				return prog.Name
+			}
+		}
+	}
+	return ""
+}
+
+// validateShells checks the shell settings
+func validateShells(shells []string, profiles []shell.Profile) error {
+	if !shellIntegration && (len(shells) > 0 || len(profiles) > 0) {
+		return fmt.Errorf("shells: only supported on Windows; the variables are always written to the startup files here")
+	}
+	if _, err := shell.WindowsProfiles("", shells); err != nil {
+		return fmt.Errorf("shells: %v", err)
+	}
+	for i, p := range profiles {
+		if p.Path == "" {
+			return fmt.Errorf("shellProfiles[%d]: path is required", i)
+		}
+		if _, err := shell.ParseKind(string(p.Kind)); err != nil {
+			return fmt.Errorf("shellProfiles[%d]: %v", i, err)
+		}
+		if _, err := shell.ParsePathStyle(string(p.Style)); err != nil {
+			return fmt.Errorf("shellProfiles[%d]: %v", i, err)
+		}
+	}
+	return nil
+}
+
+// normalizeNewlines converts CRLF line endings, which editors on Windows may
+// leave in a profile, so they do not count as drift
+func normalizeNewlines(s string) string {
+	return strings.ReplaceAll(s, "\r\n", "\n")
+}
@@ -1,16 +1,78 @@
 package config
 
+import (
+	"sort"
+	"strings"
+
+	"devpathpro/pkg/registry"
+)
+
 // Program structure holds information about a development tool
 type Program struct {
-	Name           string   `json:"name"`
-	ExecutableName string   `json:"executableName"`
-	CommonPaths    []string `json:"commonPaths"`
-	Category       string   `json:"category"`
-	EnvVar         string   `json:"envVar"`
+	Name           string         `json:"name"`
+	ExecutableName string         `json:"executableName"`
+	CommonPaths    []string       `json:"commonPaths"`
+	Category       string         `json:"category"`
+	EnvVar         string         `json:"envVar"`
+	DefaultScope   registry.Scope `json:"defaultScope,omitempty"` // user when empty
+	// Recipe describes how the program is configured
+	Recipe *Recipe `json:"recipe,omitempty"`
+	// Version describes how the version of an installation is detected
+	Version *VersionProbe `json:"version,omitempty"`
+	// Selection chooses among several installations; nil asks the user
+	Selection *SelectionPolicy `json:"selection,omitempty"`
+}
+
+// Executable returns the file name of the program's executable on this
+// platform; outside Windows ExecutableName loses its .exe, .cmd or .bat extension
+func (p Program) Executable() string {
+	return platformExecutable(p)
+}
+
+// HomeVariables returns the variables pointing at the installation of the
+// program: its EnvVar first, then the recipe variables set to the install
+// directory in name order, each name once whatever its case
+func (p Program) HomeVariables() []string {
+	var names []string
+	seen := make(map[string]bool)
+	if p.EnvVar != "" {
+		names = append(names, p.EnvVar)
+		seen[strings.ToUpper(p.EnvVar)] = true
+	}
+	if p.Recipe == nil {
+		return names
+	}
+	var recipe []string
+	for name, value := range p.Recipe.Variables {
+		if strings.ReplaceAll(value, " ", "") == "{{.InstallDir}}" && p.Recipe.Applies(name) {
+			recipe = append(recipe, name)
+		}
+	}
+	sort.Strings(recipe)
+	for _, name := range recipe {
+		if !seen[strings.ToUpper(name)] {
+			names = append(names, name)
+			seen[strings.ToUpper(name)] = true
+		}
+	}
+	return names
+}
+
+// FindProgram returns the program with the given name, ignoring case
+func FindProgram(programs []Program, name string) (Program, bool) {
+	for _, prog := range programs {
+		if strings.EqualFold(prog.Name, name) {
+			return prog, true
+		}
+	}
+	return Program{}, false
 }
 
 // Configuration holds the global configuration
 type Configuration struct {
 	Programs []Program
 	LogFile  string
+	// Settings are the user's preferences loaded from SettingsPath
+	Settings     *Settings
+	SettingsPath string
 } 
\ No newline at end of file
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHomeVariables(t *testing.T) {
+	tests := []struct {
+		name string
+		prog Program
+		want []string
+	}{
+		{name: "no variables", prog: Program{}},
+		{name: "env var only", prog: Program{EnvVar: "JAVA_HOME"}, want: []string{"JAVA_HOME"}},
+		{
+			name: "env var before recipe variables",
+			prog: Program{EnvVar: "ZIG_HOME", Recipe: &Recipe{Variables: map[string]string{
+				"B_HOME": "{{.InstallDir}}",
+				"A_HOME": "{{ .InstallDir }}",
+				"A_BIN":  "{{.BinDir}}",
+			}}},
+			want: []string{"ZIG_HOME", "A_HOME", "B_HOME"},
+		},
+		{
+			name: "recipe repeats env var in another case",
+			prog: Program{EnvVar: "JAVA_HOME", Recipe: &Recipe{Variables: map[string]string{
+				"java_home": "{{.InstallDir}}",
+				"JDK_HOME":  "{{.InstallDir}}",
+			}}},
+			want: []string{"JAVA_HOME", "JDK_HOME"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.prog.HomeVariables(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HomeVariables() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"devpathpro/pkg/config"
+	"devpathpro/pkg/plan"
+	"devpathpro/pkg/registry"
+	"devpathpro/pkg/tools"
+)
+
+// Status is the outcome of checking one requirement
+type Status string
+
+const (
+	// StatusOK means a matching installation is found and configured
+	StatusOK Status = "ok"
+	// StatusUnconfigured means a matching installation is found but the environment differs
+	StatusUnconfigured Status = "unconfigured"
+	// StatusMissing means no installation is found
+	StatusMissing Status = "missing"
+	// StatusWrongVersion means installations are found but none satisfies the constraint
+	StatusWrongVersion Status = "wrong_version"
+)
+
+// Result is the outcome of checking one requirement of a manifest
+type Result struct {
+	Tool        string
+	Requirement Requirement
+	Status      Status
+	// Path and Version are the chosen installation, if any
+	Path    string
+	Version string
+	// Message explains the status
+	Message string
+	// Plan configures the chosen installation; Changes counts what it would change
+	Plan    *plan.ChangePlan
+	Changes int
+}
+
+// Met reports whether a matching installation was found, configured or not
+func (r Result) Met() bool {
+	return r.Status == StatusOK || r.Status == StatusUnconfigured
+}
+
+// Check discovers an installation for every required tool, checks its version
+// and compares the environment with what configuring it would write. scope
+// overrides the manifest's scope when it is not empty.
+func (m *Manifest) Check(store registry.EnvStore, programs []config.Program, scope registry.Scope) ([]Result, error) {
+	if scope == "" {
+		scope = m.Scope
+	}
+
+	var results []Result
+	for _, name := range m.ToolNames() {
+		req := m.Tools[name]
+		result := Result{Tool: name, Requirement: req}
+
+		prog, ok := config.FindProgram(programs, name)
+		if !ok {
+			return nil, fmt.Errorf("unknown tool %q", name)
+		}
+		result.Tool = prog.Name
+		prog.Selection = prog.Selection.Merge(req.policy())
+
+		var paths []string
+		if req.Path != "" {
+			path := m.resolvePath(req.Path)
+			if _, err := os.Stat(path); err != nil {
+				result.Status = StatusMissing
+				result.Message = fmt.Sprintf("%s not found: %s does not exist", prog.Name, path)
+				results = append(results, result)
+				continue
+			}
+			paths = []string{path}
+		} else {
+			paths = tools.FindProgram(prog)
+		}
+		if len(paths) == 0 {
+			result.Status = StatusMissing
+			result.Message = fmt.Sprintf("%s not found", prog.Name)
+			results = append(results, result)
+			continue
+		}
+
+		selection, err := tools.ChooseInstallation(prog, paths, tools.DetectVersions(prog, paths))
+		if err != nil {
+			result.Status = StatusWrongVersion
+			result.Message = err.Error()
+			results = append(results, result)
+			continue
+		}
+		result.Path = selection.Path
+		result.Version = selection.Version
+
+		selectedVars, err := tools.OptionVariables(prog, req.Options)
+		if err != nil {
+			return nil, err
+		}
+		result.Plan = tools.BuildPlan(tools.ResolveScope(prog, scope), prog, selection.Path, selectedVars)
+		changes, err := result.Plan.Diff(store)
+		if err != nil {
+			return nil, fmt.Errorf("error checking %s: %v", prog.Name, err)
+		}
+		result.Changes = plan.CountChanged(changes)
+
+		if result.Changes == 0 {
+			result.Status = StatusOK
+			result.Message = "configured"
+		} else {
+			result.Status = StatusUnconfigured
+			result.Message = fmt.Sprintf("%d change(s) needed", result.Changes)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// resolvePath returns an explicit executable path of a requirement; relative
+// paths are relative to the directory of the manifest file, not the working
+// directory, so a checked-in manifest works wherever it is checked from
+func (m *Manifest) resolvePath(path string) string {
+	if filepath.IsAbs(path) || m.Path == "" {
+		return path
+	}
+	return filepath.Join(filepath.Dir(m.Path), path)
+}
+
+// Plan combines the plans of every found but unconfigured tool
+func Plan(results []Result) *plan.ChangePlan {
+	combined := plan.New()
+	for _, result := range results {
+		if result.Status == StatusUnconfigured {
+			combined.Merge(result.Plan)
+		}
+	}
+	return combined
+}
+
+// AllMet reports whether every requirement has a matching installation
+func AllMet(results []Result) bool {
+	for _, result := range results {
+		if !result.Met() {
+			return false
+		}
+	}
+	return true
+}
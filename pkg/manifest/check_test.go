@@ -0,0 +1,60 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"devpathpro/pkg/config"
+	"devpathpro/pkg/registry"
+)
+
+func TestCheckResolvesRelativePath(t *testing.T) {
+	root := t.TempDir()
+	exe := filepath.Join(root, "tools", "bin", "fake")
+	if err := os.MkdirAll(filepath.Dir(exe), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(exe, nil, 0755); err != nil {
+		t.Fatal(err)
+	}
+	programs := []config.Program{{Name: "Fake", ExecutableName: "fake"}}
+
+	tests := []struct {
+		name    string
+		path    string
+		status  Status
+		message string
+	}{
+		{"relative to the manifest", filepath.Join("tools", "bin", "fake"), StatusUnconfigured, ""},
+		{"absolute", exe, StatusUnconfigured, ""},
+		{"missing", filepath.Join("tools", "bin", "other"), StatusMissing, filepath.Join(root, "tools", "bin", "other")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Manifest{
+				Scope: registry.ScopeUser,
+				Tools: map[string]Requirement{"Fake": {Path: tt.path}},
+				Path:  filepath.Join(root, FileName),
+			}
+			results, err := m.Check(registry.NewMemoryStore(), programs, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("got %d results, want 1", len(results))
+			}
+			result := results[0]
+			if result.Status != tt.status {
+				t.Errorf("status = %s (%s), want %s", result.Status, result.Message, tt.status)
+			}
+			if tt.status == StatusUnconfigured && result.Path != exe {
+				t.Errorf("path = %s, want %s", result.Path, exe)
+			}
+			if !strings.Contains(result.Message, tt.message) {
+				t.Errorf("message %q does not name %s", result.Message, tt.message)
+			}
+		})
+	}
+}
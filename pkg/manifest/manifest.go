@@ -0,0 +1,138 @@
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"devpathpro/pkg/config"
+	"devpathpro/pkg/registry"
+	"devpathpro/pkg/tools"
+	"devpathpro/pkg/version"
+)
+
+// FileName is the name of a project manifest
+const FileName = ".devpathpro.toml"
+
+// Manifest lists the tools a project needs:
+//
+//	scope = "user"
+//
+//	[tools.Java]
+//	version = ">=17 <22"
+//	options = ["Basic"]
+//
+//	[tools."Node.js"]
+//	version = "20"
+type Manifest struct {
+	// Scope is where variables are written; empty uses each tool's default
+	Scope registry.Scope `toml:"scope"`
+	// Tools are the requirements by program name
+	Tools map[string]Requirement `toml:"tools"`
+
+	// Path is the file the manifest was loaded from
+	Path string `toml:"-"`
+}
+
+// Requirement describes one tool a project needs
+type Requirement struct {
+	// Version is a version constraint, e.g. ">=17 <22"; empty accepts any version
+	Version string `toml:"version"`
+	// Options are the option groups to configure; empty configures all variables
+	Options []string `toml:"options"`
+	// Prefer and Root choose among several matching installations, see config.SelectionPolicy
+	Prefer []string `toml:"prefer"`
+	Root   string   `toml:"root"`
+	// Path is an explicit executable to use instead of searching; a relative
+	// path is relative to the directory of the manifest
+	Path string `toml:"path"`
+}
+
+// Find looks for a manifest in dir and its parents and returns its path
+func Find(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		path := filepath.Join(dir, FileName)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no %s found in %s or its parents", FileName, dir)
+		}
+		dir = parent
+	}
+}
+
+// Load reads a manifest and checks it against the known programs
+func Load(path string, programs []config.Program) (*Manifest, error) {
+	var m Manifest
+	meta, err := toml.DecodeFile(path, &m)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", path, err)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		return nil, fmt.Errorf("%s: unknown key %s", path, undecoded[0])
+	}
+	m.Path = path
+	if err := m.Validate(programs); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return &m, nil
+}
+
+// Validate checks that every required tool is known and that its
+// constraint, preferences and option groups are valid
+func (m *Manifest) Validate(programs []config.Program) error {
+	if m.Scope != "" {
+		if _, err := registry.ParseScope(string(m.Scope)); err != nil {
+			return fmt.Errorf("scope: %v", err)
+		}
+	}
+	if len(m.Tools) == 0 {
+		return fmt.Errorf("no tools listed")
+	}
+	for _, name := range m.ToolNames() {
+		req := m.Tools[name]
+		prog, ok := config.FindProgram(programs, name)
+		if !ok {
+			return fmt.Errorf("tools.%s: unknown tool", name)
+		}
+		if req.Version != "" {
+			if _, err := version.ParseConstraint(req.Version); err != nil {
+				return fmt.Errorf("tools.%s: %v", name, err)
+			}
+		}
+		if err := req.policy().Validate(); err != nil {
+			return fmt.Errorf("tools.%s: %v", name, err)
+		}
+		if _, err := tools.OptionVariables(prog, req.Options); err != nil {
+			return fmt.Errorf("tools.%s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// ToolNames returns the required tools in name order
+func (m *Manifest) ToolNames() []string {
+	names := make([]string, 0, len(m.Tools))
+	for name := range m.Tools {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return strings.ToLower(names[i]) < strings.ToLower(names[j])
+	})
+	return names
+}
+
+// policy returns the selection policy the requirement imposes
+func (r Requirement) policy() *config.SelectionPolicy {
+	return &config.SelectionPolicy{Constraint: r.Version, Prefer: r.Prefer, Root: r.Root}
+}
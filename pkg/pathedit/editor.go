@@ -0,0 +1,300 @@
+// Package pathedit lists and edits the entries of PATH: reordering,
+// pinning, removing, deduplicating and expanding %VAR% references.
+package pathedit
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"devpathpro/pkg/backup"
+	"devpathpro/pkg/plan"
+	"devpathpro/pkg/registry"
+	"devpathpro/pkg/utils"
+)
+
+// pathVariable is the variable the editor changes
+const pathVariable = registry.PathVariable
+
+// Editor changes the PATH of one scope. Edits are made in memory and written
+// by Apply in a single backup transaction.
+type Editor struct {
+	store registry.EnvStore
+	scope registry.Scope
+	// original is the PATH value read from the store
+	original string
+	// initial are the entries of original
+	initial []string
+	entries []string
+	lookup  func(string) (string, bool)
+}
+
+// Open reads the PATH of a machine, user or process scope for editing
+func Open(store registry.EnvStore, scope registry.Scope) (*Editor, error) {
+	switch scope {
+	case registry.ScopeMachine, registry.ScopeUser, registry.ScopeProcess:
+	default:
+		return nil, fmt.Errorf("invalid scope %q: expected user, machine or process", scope)
+	}
+
+	value, _, err := store.Get(scope, pathVariable)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s PATH: %v", scope, err)
+	}
+	entries := registry.SplitPathList(value)
+	return &Editor{
+		store:    store,
+		scope:    scope,
+		original: value,
+		initial:  entries,
+		entries:  append([]string(nil), entries...),
+		lookup:   Lookup(store, scope),
+	}, nil
+}
+
+// Lookup returns a function resolving %VAR% references in the PATH of scope:
+// variables of the scope come first, then machine variables, then the
+// environment of the running process
+func Lookup(store registry.EnvStore, scope registry.Scope) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		for _, s := range []registry.Scope{scope, registry.ScopeMachine} {
+			if value, ok, err := store.Get(s, name); err == nil && ok {
+				return value, true
+			}
+		}
+		return os.LookupEnv(name)
+	}
+}
+
+// Scope returns the scope being edited
+func (e *Editor) Scope() registry.Scope {
+	return e.scope
+}
+
+// Entries returns the edited PATH entries
+func (e *Editor) Entries() []string {
+	return append([]string(nil), e.entries...)
+}
+
+// Value returns the edited PATH value
+func (e *Editor) Value() string {
+	return registry.JoinPathList(e.entries)
+}
+
+// Changed reports whether the entries differ from the ones read
+func (e *Editor) Changed() bool {
+	if len(e.entries) != len(e.initial) {
+		return true
+	}
+	for i := range e.entries {
+		if e.entries[i] != e.initial[i] {
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns the index of an entry given as a 1-based position or as a
+// directory. Directories are compared after expanding %VAR% references.
+func (e *Editor) Find(ref string) (int, error) {
+	if n, err := strconv.Atoi(ref); err == nil {
+		if n < 1 || n > len(e.entries) {
+			return 0, fmt.Errorf("position %d is out of range: %s PATH has %d entries", n, e.scope, len(e.entries))
+		}
+		return n - 1, nil
+	}
+	for i, entry := range e.entries {
+		if e.same(entry, ref) {
+			return i, nil
+		}
+	}
+	return 0, fmt.Errorf("%s is not in the %s PATH", ref, e.scope)
+}
+
+// Move moves the entry at index from to index to, shifting the ones in between
+func (e *Editor) Move(from, to int) error {
+	if from < 0 || from >= len(e.entries) {
+		return fmt.Errorf("position %d is out of range: %s PATH has %d entries", from+1, e.scope, len(e.entries))
+	}
+	if to < 0 {
+		to = 0
+	}
+	if to >= len(e.entries) {
+		to = len(e.entries) - 1
+	}
+	entry := e.entries[from]
+	rest := append(append([]string(nil), e.entries[:from]...), e.entries[from+1:]...)
+	e.entries = append(rest[:to], append([]string{entry}, rest[to:]...)...)
+	return nil
+}
+
+// Pin puts entry right before the entry before, so that its programs win over
+// the ones in before. An entry that is not in PATH yet is inserted.
+func (e *Editor) Pin(entry, before string) error {
+	target, err := e.Find(before)
+	if err != nil {
+		return err
+	}
+	if from, err := e.Find(entry); err == nil {
+		if from < target {
+			return nil
+		}
+		return e.Move(from, target)
+	}
+	e.entries = append(e.entries[:target], append([]string{entry}, e.entries[target:]...)...)
+	return nil
+}
+
+// Remove removes every entry naming the same directory as entry
+// and returns how many were removed
+func (e *Editor) Remove(entry string) (int, error) {
+	if i, err := strconv.Atoi(entry); err == nil {
+		if _, err := e.Find(entry); err != nil {
+			return 0, err
+		}
+		entry = e.entries[i-1]
+	}
+	var kept []string
+	for _, existing := range e.entries {
+		if !e.same(existing, entry) {
+			kept = append(kept, existing)
+		}
+	}
+	removed := len(e.entries) - len(kept)
+	if removed == 0 {
+		return 0, fmt.Errorf("%s is not in the %s PATH", entry, e.scope)
+	}
+	e.entries = kept
+	return removed, nil
+}
+
+// Dedupe removes entries naming the same directory as an earlier one, after
+// expanding %VAR% references and ignoring case and trailing slashes.
+// It returns the removed entries.
+func (e *Editor) Dedupe() []string {
+	var kept, removed []string
+	for _, entry := range e.entries {
+		duplicate := false
+		for _, k := range kept {
+			if e.same(k, entry) {
+				duplicate = true
+				break
+			}
+		}
+		if duplicate {
+			removed = append(removed, entry)
+		} else {
+			kept = append(kept, entry)
+		}
+	}
+	e.entries = kept
+	return removed
+}
+
+// Expand replaces %VAR% references with their values in the entries at the
+// given indexes, or in all entries if none are given. It returns how many changed.
+func (e *Editor) Expand(indexes ...int) int {
+	return e.rewrite(indexes, func(entry string) string {
+		return registry.ExpandVars(entry, e.lookup)
+	})
+}
+
+// Unexpand replaces the longest matching variable value at the start of the
+// entries at the given indexes, or of all entries, with a %VAR% reference.
+// It returns how many changed.
+func (e *Editor) Unexpand(vars []registry.VarRef, indexes ...int) int {
+	return e.rewrite(indexes, func(entry string) string {
+		return registry.UnexpandVars(entry, vars)
+	})
+}
+
+// Vars resolves the named variables for Unexpand, skipping unset ones
+func (e *Editor) Vars(names []string) []registry.VarRef {
+	var vars []registry.VarRef
+	for _, name := range names {
+		if value, ok := e.lookup(name); ok && value != "" {
+			vars = append(vars, registry.VarRef{Name: name, Value: value})
+		}
+	}
+	return vars
+}
+
+// Change describes the edit for previews
+func (e *Editor) Change() backup.Change {
+	return backup.Change{
+		Scope:     e.scope,
+		Name:      pathVariable,
+		Old:       registry.JoinPathList(e.initial),
+		New:       e.Value(),
+		OldExists: true,
+		NewExists: true,
+	}
+}
+
+// Preview returns the edit as a PATH diff, or "No changes."
+func (e *Editor) Preview() string {
+	if !e.Changed() {
+		return backup.FormatChanges(nil)
+	}
+	return backup.FormatChanges([]backup.Change{e.Change()})
+}
+
+// Plan returns the plan writing the edited PATH
+func (e *Editor) Plan() *plan.ChangePlan {
+	p := plan.New()
+	p.SetVar(e.scope, pathVariable, e.Value())
+	return p
+}
+
+// Apply backs up the environment and writes the edited PATH in a transaction.
+// It fails without changing anything if PATH was changed by someone else
+// since the editor read it.
+func (e *Editor) Apply() error {
+	if !e.Changed() {
+		return nil
+	}
+	current, _, err := e.store.Get(e.scope, pathVariable)
+	if err != nil {
+		return fmt.Errorf("error reading %s PATH: %v", e.scope, err)
+	}
+	if current != e.original {
+		return fmt.Errorf("%s PATH changed since it was read; reload it and try again", e.scope)
+	}
+
+	p := e.Plan()
+	if _, err := p.CreateBackup(e.store, "path"); err != nil {
+		utils.Warnf("backup before editing %s PATH failed: %v", e.scope, err)
+	}
+	if err := p.Apply(e.store); err != nil {
+		return err
+	}
+	e.original = e.Value()
+	e.initial = e.Entries()
+	return nil
+}
+
+// same reports whether two entries name the same directory once expanded
+func (e *Editor) same(a, b string) bool {
+	return registry.SamePath(registry.ExpandVars(a, e.lookup), registry.ExpandVars(b, e.lookup))
+}
+
+// rewrite replaces the entries at indexes, or all entries, with fn applied to
+// them and returns how many changed
+func (e *Editor) rewrite(indexes []int, fn func(string) string) int {
+	if len(indexes) == 0 {
+		for i := range e.entries {
+			indexes = append(indexes, i)
+		}
+	}
+	changed := 0
+	for _, i := range indexes {
+		if i < 0 || i >= len(e.entries) {
+			continue
+		}
+		if updated := fn(e.entries[i]); updated != e.entries[i] {
+			e.entries[i] = updated
+			changed++
+		}
+	}
+	return changed
+}
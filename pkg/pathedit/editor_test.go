@@ -0,0 +1,197 @@
+package pathedit
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"devpathpro/pkg/backup"
+	"devpathpro/pkg/registry"
+)
+
+// openEditor returns an editor for a user PATH holding entries, with the
+// given user variables set
+func openEditor(t *testing.T, entries []string, vars map[string]string) *Editor {
+	t.Helper()
+	store := registry.NewMemoryStore()
+	for name, value := range vars {
+		if err := store.Set(registry.ScopeUser, name, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.Set(registry.ScopeUser, pathVariable, registry.JoinPathList(entries)); err != nil {
+		t.Fatal(err)
+	}
+	e, err := Open(store, registry.ScopeUser)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestEditorEdits(t *testing.T) {
+	ref := registry.VarReference
+	vars := map[string]string{"JAVA_HOME": "/jdk", "GOPATH": "/go"}
+	tests := []struct {
+		name    string
+		entries []string
+		edit    func(e *Editor) error
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "move to the front",
+			entries: []string{"/a", "/b", "/c"},
+			edit:    func(e *Editor) error { return e.Move(2, 0) },
+			want:    []string{"/c", "/a", "/b"},
+		},
+		{
+			name:    "move past the end",
+			entries: []string{"/a", "/b", "/c"},
+			edit:    func(e *Editor) error { return e.Move(0, 10) },
+			want:    []string{"/b", "/c", "/a"},
+		},
+		{
+			name:    "move out of range",
+			entries: []string{"/a"},
+			edit:    func(e *Editor) error { return e.Move(3, 0) },
+			want:    []string{"/a"},
+			wantErr: true,
+		},
+		{
+			name:    "pin moves an entry before another",
+			entries: []string{"/old/bin", "/a", "/jdk/bin"},
+			edit:    func(e *Editor) error { return e.Pin("/jdk/bin", "/old/bin") },
+			want:    []string{"/jdk/bin", "/old/bin", "/a"},
+		},
+		{
+			name:    "pin keeps an entry already before",
+			entries: []string{"/jdk/bin", "/old/bin"},
+			edit:    func(e *Editor) error { return e.Pin("/jdk/bin", "2") },
+			want:    []string{"/jdk/bin", "/old/bin"},
+		},
+		{
+			name:    "pin inserts a missing entry",
+			entries: []string{"/a", "/old/bin"},
+			edit:    func(e *Editor) error { return e.Pin("/jdk/bin", "/old/bin") },
+			want:    []string{"/a", "/jdk/bin", "/old/bin"},
+		},
+		{
+			name:    "pin matches references",
+			entries: []string{"/old/bin", ref("JAVA_HOME") + "/bin"},
+			edit:    func(e *Editor) error { return e.Pin("/jdk/bin", "/old/bin") },
+			want:    []string{ref("JAVA_HOME") + "/bin", "/old/bin"},
+		},
+		{
+			name:    "remove every copy",
+			entries: []string{"/a", "/b", "/a/"},
+			edit:    func(e *Editor) error { _, err := e.Remove("/a"); return err },
+			want:    []string{"/b"},
+		},
+		{
+			name:    "remove by position",
+			entries: []string{"/a", "/b"},
+			edit:    func(e *Editor) error { _, err := e.Remove("2"); return err },
+			want:    []string{"/a"},
+		},
+		{
+			name:    "remove a missing entry",
+			entries: []string{"/a"},
+			edit:    func(e *Editor) error { _, err := e.Remove("/b"); return err },
+			want:    []string{"/a"},
+			wantErr: true,
+		},
+		{
+			name:    "dedupe keeps the first",
+			entries: []string{"/go/bin", "/a", ref("GOPATH") + "/bin", "/a/"},
+			edit:    func(e *Editor) error { e.Dedupe(); return nil },
+			want:    []string{"/go/bin", "/a"},
+		},
+		{
+			name:    "expand",
+			entries: []string{ref("JAVA_HOME") + "/bin", "/a"},
+			edit:    func(e *Editor) error { e.Expand(); return nil },
+			want:    []string{"/jdk/bin", "/a"},
+		},
+		{
+			name:    "unexpand",
+			entries: []string{"/jdk/bin", "/a"},
+			edit: func(e *Editor) error {
+				e.Unexpand(e.Vars([]string{"JAVA_HOME", "UNSET"}))
+				return nil
+			},
+			want: []string{ref("JAVA_HOME") + "/bin", "/a"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := openEditor(t, tt.entries, vars)
+			err := tt.edit(e)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("edit error = %v, want error: %v", err, tt.wantErr)
+			}
+			if got := e.Entries(); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("entries = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditorFind(t *testing.T) {
+	e := openEditor(t, []string{"/a", "/b"}, nil)
+	tests := []struct {
+		ref     string
+		want    int
+		wantErr bool
+	}{
+		{ref: "1", want: 0},
+		{ref: "2", want: 1},
+		{ref: "/b/", want: 1},
+		{ref: "0", wantErr: true},
+		{ref: "3", wantErr: true},
+		{ref: "/c", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := e.Find(tt.ref)
+		if (err != nil) != tt.wantErr || (err == nil && got != tt.want) {
+			t.Errorf("Find(%q) = %d, %v; want %d, error: %v", tt.ref, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestEditorApply(t *testing.T) {
+	backup.Configure(backup.Options{Dir: filepath.Join(t.TempDir(), "backups")})
+	t.Cleanup(func() { backup.Configure(backup.Options{}) })
+
+	e := openEditor(t, []string{"/a", "/b"}, nil)
+	if err := e.Move(1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if !e.Changed() {
+		t.Fatal("Changed() = false after a move")
+	}
+	if err := e.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	if e.Changed() {
+		t.Error("Changed() = true after Apply")
+	}
+	got, _, _ := e.store.Get(registry.ScopeUser, pathVariable)
+	if want := registry.JoinPathList([]string{"/b", "/a"}); got != want {
+		t.Errorf("PATH = %q, want %q", got, want)
+	}
+
+	// A PATH changed behind the editor's back is not overwritten
+	if err := e.Move(1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.store.Set(registry.ScopeUser, pathVariable, "/c"); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Apply(); err == nil {
+		t.Error("Apply() overwrote a PATH changed by someone else")
+	}
+	if got, _, _ := e.store.Get(registry.ScopeUser, pathVariable); got != "/c" {
+		t.Errorf("PATH = %q, want /c", got)
+	}
+}
@@ -0,0 +1,104 @@
+package pathedit
+
+import (
+	"fmt"
+	"os"
+
+	"devpathpro/pkg/config"
+	"devpathpro/pkg/registry"
+)
+
+// DefaultUnexpandVars are the variables Unexpand uses unless told otherwise,
+// besides the home variables of the known tools
+var DefaultUnexpandVars = []string{
+	"SystemRoot",
+	"ProgramFiles",
+	"ProgramFiles(x86)",
+	"ProgramData",
+	"LOCALAPPDATA",
+	"APPDATA",
+	"USERPROFILE",
+	"HOME",
+}
+
+// Entry is a PATH entry with what is known about its directory
+type Entry struct {
+	Scope registry.Scope `json:"scope" yaml:"scope"`
+	// Position is the 1-based position of the entry in the PATH of its scope
+	Position int    `json:"position" yaml:"position"`
+	Value    string `json:"value" yaml:"value"`
+	// Expanded is Value with its %VAR% references resolved
+	Expanded string `json:"expanded" yaml:"expanded"`
+	// Exists is set if the directory exists
+	Exists bool `json:"exists" yaml:"exists"`
+	// DuplicateOf is the position of an earlier entry of the same scope naming the same directory
+	DuplicateOf int `json:"duplicateOf,omitempty" yaml:"duplicateOf,omitempty"`
+	// Owner is the tool the directory belongs to: the one whose executable
+	// it contains, or whose home variable points above it
+	Owner string `json:"owner,omitempty" yaml:"owner,omitempty"`
+}
+
+// Notes describes what is missing, duplicated or expanded about the entry
+func (e Entry) Notes() []string {
+	var notes []string
+	if e.Expanded != e.Value {
+		notes = append(notes, "= "+e.Expanded)
+	}
+	if !e.Exists {
+		notes = append(notes, "missing")
+	}
+	if e.DuplicateOf > 0 {
+		notes = append(notes, fmt.Sprintf("duplicate of %d", e.DuplicateOf))
+	}
+	return notes
+}
+
+// List returns the annotated PATH entries of a scope. ScopeBoth lists the
+// machine entries and then the user ones, the order Windows searches them in.
+func List(store registry.EnvStore, scope registry.Scope, programs []config.Program) ([]Entry, error) {
+	var entries []Entry
+	for _, target := range scope.Targets() {
+		e, err := Open(store, target)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e.Describe(programs)...)
+	}
+	return entries, nil
+}
+
+// Describe annotates the edited entries
+func (e *Editor) Describe(programs []config.Program) []Entry {
+	entries := make([]Entry, 0, len(e.entries))
+	for i, value := range e.entries {
+		expanded := registry.ExpandVars(value, e.lookup)
+		entry := Entry{
+			Scope:    e.scope,
+			Position: i + 1,
+			Value:    value,
+			Expanded: expanded,
+			Owner:    config.FindOwner(expanded, programs, e.lookup),
+		}
+		if info, err := os.Stat(expanded); err == nil && info.IsDir() {
+			entry.Exists = true
+		}
+		for _, earlier := range entries {
+			if registry.SamePath(earlier.Expanded, expanded) {
+				entry.DuplicateOf = earlier.Position
+				break
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// UnexpandVars returns the variables Unexpand uses by default: the well-known
+// Windows folders and the home variables of programs that are set
+func (e *Editor) UnexpandVars(programs []config.Program) []registry.VarRef {
+	names := append([]string(nil), DefaultUnexpandVars...)
+	for _, prog := range programs {
+		names = append(names, prog.HomeVariables()...)
+	}
+	return e.Vars(names)
+}
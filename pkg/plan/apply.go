@@ -0,0 +1,98 @@
+package plan
+
+import (
+	"fmt"
+
+	"devpathpro/pkg/backup"
+	"devpathpro/pkg/registry"
+)
+
+// ApplyError reports the operation that made Apply fail and
+// whether the changes made before it were rolled back
+type ApplyError struct {
+	// Index is the position of the failing operation in the plan
+	Index int
+	Op    Operation
+	Err   error
+	// RollbackErr is set if restoring the previous values failed too
+	RollbackErr error
+	// Transaction is the ID of the transaction journal in the backup directory
+	Transaction string
+}
+
+func (e *ApplyError) Error() string {
+	msg := fmt.Sprintf("operation %d (%s) failed: %v", e.Index+1, e.Op, e.Err)
+	if e.RollbackErr != nil {
+		return fmt.Sprintf("%s; %v", msg, e.RollbackErr)
+	}
+	return msg + "; previous changes were rolled back"
+}
+
+func (e *ApplyError) Unwrap() error {
+	return e.Err
+}
+
+// Apply executes the plan against the store inside a backup transaction.
+// Every value is recorded before it is first changed; if an operation fails,
+// all changes made so far are restored and an *ApplyError is returned.
+func (p *ChangePlan) Apply(store registry.EnvStore) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+
+	tx, err := backup.Begin(store)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %v", err)
+	}
+
+	for i, op := range p.Operations {
+		if err := applyOp(tx, store, op); err != nil {
+			return &ApplyError{
+				Index:       i,
+				Op:          op,
+				Err:         err,
+				RollbackErr: tx.Rollback(),
+				Transaction: tx.ID,
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	registry.NotifyEnvironmentChange()
+	return nil
+}
+
+// CreateBackup backs up the environment before the plan is applied,
+// recording action, the plan's tools and the scope it writes to
+func (p *ChangePlan) CreateBackup(store registry.EnvStore, action string) (backup.Info, error) {
+	return backup.CreateBackup(store, backup.Metadata{
+		Action: action,
+		Tools:  p.Tools,
+		Scope:  p.Scope(),
+	})
+}
+
+// applyOp performs a single operation through the transaction
+func applyOp(tx *backup.Transaction, store registry.EnvStore, op Operation) error {
+	if op.Type == OpCreateDir {
+		return tx.MkdirAll(op.Value)
+	}
+
+	value, exists, err := store.Get(op.Scope, op.Variable())
+	if err != nil {
+		return err
+	}
+	if op.Type == OpDeleteVar {
+		if !exists {
+			return nil
+		}
+		return tx.Delete(op.Scope, op.Name)
+	}
+	updated, changed := op.next(varState{value: value, exists: exists})
+	if !changed {
+		return nil
+	}
+	return tx.Set(op.Scope, op.Variable(), updated)
+}
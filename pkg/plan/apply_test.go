@@ -0,0 +1,84 @@
+package plan
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"devpathpro/pkg/backup"
+	"devpathpro/pkg/registry"
+)
+
+// failingStore fails every write of one variable
+type failingStore struct {
+	*registry.MemoryStore
+	name string
+}
+
+func (s failingStore) Set(scope registry.Scope, name, value string) error {
+	if name == s.name {
+		return errors.New("access denied")
+	}
+	return s.MemoryStore.Set(scope, name, value)
+}
+
+func useTempBackups(t *testing.T) {
+	t.Helper()
+	backup.Configure(backup.Options{Dir: filepath.Join(t.TempDir(), "backups")})
+	t.Cleanup(func() { backup.Configure(backup.Options{}) })
+}
+
+func TestApply(t *testing.T) {
+	useTempBackups(t)
+	store := registry.NewMemoryStore()
+	store.Set(registry.ScopeUser, "OLD", "x")
+	dir := filepath.Join(t.TempDir(), "npm")
+
+	p := New()
+	p.SetVar(registry.ScopeUser, "GOPATH", "/go")
+	p.DeleteVar(registry.ScopeUser, "OLD")
+	p.CreateDir(dir)
+	p.AppendPath(registry.ScopeUser, "/go/bin")
+	p.PrependPath(registry.ScopeUser, dir)
+	if err := p.Apply(store); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"GOPATH":              "/go",
+		registry.PathVariable: registry.JoinPathList([]string{dir, "/go/bin"}),
+	}
+	got, _ := store.List(registry.ScopeUser)
+	if len(got) != len(want) {
+		t.Errorf("user variables = %v, want %v", got, want)
+	}
+	for name, value := range want {
+		if got[name] != value {
+			t.Errorf("%s = %q, want %q", name, got[name], value)
+		}
+	}
+}
+
+func TestApplyRollsBack(t *testing.T) {
+	useTempBackups(t)
+	store := failingStore{MemoryStore: registry.NewMemoryStore(), name: "JAVA_HOME"}
+	store.MemoryStore.Set(registry.ScopeUser, "GOPATH", "/old")
+
+	p := New()
+	p.SetVar(registry.ScopeUser, "GOPATH", "/go")
+	p.AppendPath(registry.ScopeUser, "/go/bin")
+	p.SetVar(registry.ScopeUser, "JAVA_HOME", "/jdk")
+	err := p.Apply(store)
+
+	var applyErr *ApplyError
+	if !errors.As(err, &applyErr) {
+		t.Fatalf("Apply() = %v, want an ApplyError", err)
+	}
+	if applyErr.Index != 2 || applyErr.RollbackErr != nil {
+		t.Errorf("ApplyError = %+v, want index 2 and no rollback error", applyErr)
+	}
+	got, _ := store.List(registry.ScopeUser)
+	if len(got) != 1 || got["GOPATH"] != "/old" {
+		t.Errorf("user variables after rollback = %v, want only GOPATH=/old", got)
+	}
+}
@@ -0,0 +1,209 @@
+package plan
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"devpathpro/pkg/registry"
+)
+
+// Change is the effect an operation would have on the current environment
+type Change struct {
+	Op Operation
+	// Old is the value of the variable before the operation
+	Old string
+	// OldExists reports whether the variable existed before the operation
+	OldExists bool
+	// New is the value of the variable after the operation
+	New string
+	// Changed reports whether the operation modifies anything
+	Changed bool
+}
+
+// varKey identifies a variable in a scope; names are case-insensitive
+type varKey struct {
+	scope registry.Scope
+	name  string
+}
+
+func keyOf(op Operation) varKey {
+	return varKey{scope: op.Scope, name: strings.ToLower(op.Variable())}
+}
+
+// varState is the value of a variable and whether it exists
+type varState struct {
+	value  string
+	exists bool
+}
+
+// next returns the value of the operation's variable after applying
+// the operation to the current value, and whether it differs
+func (op Operation) next(current varState) (string, bool) {
+	switch op.Type {
+	case OpSetVar:
+		return op.Value, !current.exists || current.value != op.Value
+	case OpDeleteVar:
+		return "", current.exists
+	case OpAppendPath:
+		return registry.AppendPathEntry(current.value, op.Value)
+	case OpPrependPath:
+		return registry.PrependPathEntry(current.value, op.Value)
+	case OpRemovePath:
+		return registry.RemovePathEntry(current.value, op.Value)
+	}
+	return current.value, false
+}
+
+// Diff computes the effect of every operation against the values currently in
+// the store. Operations are evaluated in order, so later operations see the
+// result of earlier ones.
+func (p *ChangePlan) Diff(store registry.EnvStore) ([]Change, error) {
+	state := make(map[varKey]varState)
+	changes := make([]Change, 0, len(p.Operations))
+
+	for _, op := range p.Operations {
+		if op.Type == OpCreateDir {
+			_, err := os.Stat(op.Value)
+			changes = append(changes, Change{Op: op, Changed: os.IsNotExist(err)})
+			continue
+		}
+
+		key := keyOf(op)
+		current, ok := state[key]
+		if !ok {
+			value, exists, err := store.Get(op.Scope, op.Variable())
+			if err != nil {
+				return nil, fmt.Errorf("error reading %s %s: %v", op.Scope, op.Variable(), err)
+			}
+			current = varState{value: value, exists: exists}
+		}
+
+		value, changed := op.next(current)
+		changes = append(changes, Change{
+			Op:        op,
+			Old:       current.value,
+			OldExists: current.exists,
+			New:       value,
+			Changed:   changed,
+		})
+		state[key] = varState{value: value, exists: op.Type != OpDeleteVar && (current.exists || changed)}
+	}
+	return changes, nil
+}
+
+// Minimize returns a plan holding only the operations of p that change
+// something in the store, evaluated in order as Diff does
+func (p *ChangePlan) Minimize(store registry.EnvStore) (*ChangePlan, error) {
+	changes, err := p.Diff(store)
+	if err != nil {
+		return nil, err
+	}
+	minimal := New()
+	minimal.Tools = p.Tools
+	for _, c := range changes {
+		if c.Changed {
+			minimal.Operations = append(minimal.Operations, c.Op)
+		}
+	}
+	return minimal, nil
+}
+
+// Value returns the value a variable of scope will have once the plan is
+// applied to the store, and whether it will exist
+func (p *ChangePlan) Value(store registry.EnvStore, scope registry.Scope, name string) (string, bool, error) {
+	changes, err := p.Diff(store)
+	if err != nil {
+		return "", false, err
+	}
+	for i := len(changes) - 1; i >= 0; i-- {
+		c := changes[i]
+		if c.Op.Type != OpCreateDir && c.Op.Scope == scope && strings.EqualFold(c.Op.Variable(), name) {
+			return c.New, c.Op.Type != OpDeleteVar && (c.OldExists || c.Changed), nil
+		}
+	}
+	value, exists, err := store.Get(scope, name)
+	if err != nil {
+		return "", false, fmt.Errorf("error reading %s %s: %v", scope, name, err)
+	}
+	return value, exists, nil
+}
+
+// CountChanged returns how many of the changes modify something
+func CountChanged(changes []Change) int {
+	count := 0
+	for _, c := range changes {
+		if c.Changed {
+			count++
+		}
+	}
+	return count
+}
+
+// PrintDiff writes a human readable summary of the changes.
+// Lines start with + for additions, ~ for modifications, - for removals,
+// ^ for entries moved to the front of PATH and = for no-ops.
+func PrintDiff(w io.Writer, changes []Change) {
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "No changes.")
+		return
+	}
+
+	for _, c := range changes {
+		fmt.Fprintf(w, "%s %s\n", marker(c), describe(c))
+	}
+	fmt.Fprintf(w, "\n%d to change, %d unchanged.\n", CountChanged(changes), len(changes)-CountChanged(changes))
+}
+
+// FormatDiff returns the output of PrintDiff as a string
+func FormatDiff(changes []Change) string {
+	var sb strings.Builder
+	PrintDiff(&sb, changes)
+	return sb.String()
+}
+
+// marker returns the prefix character of a diff line
+func marker(c Change) string {
+	if !c.Changed {
+		return "="
+	}
+	switch c.Op.Type {
+	case OpSetVar:
+		if c.OldExists {
+			return "~"
+		}
+		return "+"
+	case OpDeleteVar, OpRemovePath:
+		return "-"
+	case OpPrependPath:
+		return "^"
+	}
+	return "+"
+}
+
+// describe returns the body of a diff line
+func describe(c Change) string {
+	op := c.Op
+	switch op.Type {
+	case OpSetVar:
+		if c.Changed && c.OldExists {
+			return fmt.Sprintf("[%s] %s: %s -> %s", op.Scope, op.Name, c.Old, op.Value)
+		}
+		return fmt.Sprintf("[%s] %s = %s", op.Scope, op.Name, op.Value)
+	case OpDeleteVar:
+		if c.Changed {
+			return fmt.Sprintf("[%s] %s (was %s)", op.Scope, op.Name, c.Old)
+		}
+		return fmt.Sprintf("[%s] %s is not set", op.Scope, op.Name)
+	case OpAppendPath:
+		return fmt.Sprintf("[%s] PATH += %s", op.Scope, op.Value)
+	case OpPrependPath:
+		return fmt.Sprintf("[%s] PATH ^= %s", op.Scope, op.Value)
+	case OpRemovePath:
+		return fmt.Sprintf("[%s] PATH -= %s", op.Scope, op.Value)
+	case OpCreateDir:
+		return fmt.Sprintf("mkdir %s", op.Value)
+	}
+	return op.String()
+}
@@ -0,0 +1,114 @@
+package plan
+
+import (
+	"strings"
+	"testing"
+
+	"devpathpro/pkg/registry"
+)
+
+func TestDiff(t *testing.T) {
+	join := registry.JoinPathList
+	tests := []struct {
+		name  string
+		store map[string]string
+		build func(p *ChangePlan)
+		want  []string
+	}{
+		{
+			name:  "set new and existing variables",
+			store: map[string]string{"JAVA_HOME": "/jdk17", "GOPATH": "/go"},
+			build: func(p *ChangePlan) {
+				p.SetVar(registry.ScopeUser, "JAVA_HOME", "/jdk21")
+				p.SetVar(registry.ScopeUser, "GOPATH", "/go")
+				p.SetVar(registry.ScopeUser, "GOBIN", "/go/bin")
+			},
+			want: []string{
+				"~ [user] JAVA_HOME: /jdk17 -> /jdk21",
+				"= [user] GOPATH = /go",
+				"+ [user] GOBIN = /go/bin",
+			},
+		},
+		{
+			name:  "delete",
+			store: map[string]string{"OLD": "x"},
+			build: func(p *ChangePlan) {
+				p.DeleteVar(registry.ScopeUser, "OLD")
+				p.DeleteVar(registry.ScopeUser, "MISSING")
+			},
+			want: []string{"- [user] OLD (was x)", "= [user] MISSING is not set"},
+		},
+		{
+			name:  "later operations see earlier ones",
+			store: map[string]string{registry.PathVariable: join([]string{"/a"})},
+			build: func(p *ChangePlan) {
+				p.AppendPath(registry.ScopeUser, "/b")
+				p.AppendPath(registry.ScopeUser, "/b")
+				p.PrependPath(registry.ScopeUser, "/b")
+				p.RemovePath(registry.ScopeUser, "/a")
+				p.RemovePath(registry.ScopeUser, "/a")
+			},
+			want: []string{
+				"+ [user] PATH += /b",
+				"= [user] PATH += /b",
+				"^ [user] PATH ^= /b",
+				"- [user] PATH -= /a",
+				"= [user] PATH -= /a",
+			},
+		},
+		{
+			name:  "set after delete",
+			store: map[string]string{"A": "1"},
+			build: func(p *ChangePlan) {
+				p.DeleteVar(registry.ScopeUser, "A")
+				p.SetVar(registry.ScopeUser, "a", "1")
+			},
+			want: []string{"- [user] A (was 1)", "+ [user] a = 1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := registry.NewMemoryStore()
+			for name, value := range tt.store {
+				if err := store.Set(registry.ScopeUser, name, value); err != nil {
+					t.Fatal(err)
+				}
+			}
+			p := New()
+			tt.build(p)
+			changes, err := p.Diff(store)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, c := range changes {
+				got = append(got, marker(c)+" "+describe(c))
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("Diff() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+
+			minimal, err := p.Minimize(store)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := len(minimal.Operations), CountChanged(changes); got != want {
+				t.Errorf("Minimize() kept %d operations, want %d", got, want)
+			}
+		})
+	}
+}
+
+func TestPrintDiff(t *testing.T) {
+	if got := FormatDiff(nil); got != "No changes.\n" {
+		t.Errorf("FormatDiff(nil) = %q", got)
+	}
+	changes := []Change{
+		{Op: Operation{Type: OpSetVar, Scope: registry.ScopeUser, Name: "A", Value: "1"}, Changed: true},
+		{Op: Operation{Type: OpCreateDir, Value: "/x"}},
+	}
+	want := "+ [user] A = 1\n= mkdir /x\n\n1 to change, 1 unchanged.\n"
+	if got := FormatDiff(changes); got != want {
+		t.Errorf("FormatDiff() = %q, want %q", got, want)
+	}
+}
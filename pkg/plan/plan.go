@@ -0,0 +1,241 @@
+package plan
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"devpathpro/pkg/registry"
+)
+
+// Version is the format version written to saved plans
+const Version = 1
+
+// OpType identifies the kind of change an operation makes
+type OpType string
+
+const (
+	// OpSetVar sets an environment variable
+	OpSetVar OpType = "set_var"
+	// OpDeleteVar deletes an environment variable if it exists
+	OpDeleteVar OpType = "delete_var"
+	// OpAppendPath appends an entry to PATH unless it is already present
+	OpAppendPath OpType = "append_path"
+	// OpPrependPath moves or inserts an entry at the front of PATH
+	OpPrependPath OpType = "prepend_path"
+	// OpRemovePath removes an entry from PATH
+	OpRemovePath OpType = "remove_path"
+	// OpCreateDir creates a directory
+	OpCreateDir OpType = "create_dir"
+)
+
+// pathVariable is the variable PATH operations work on
+const pathVariable = registry.PathVariable
+
+// Operation is a single change to the environment or the file system.
+// Scope is always a concrete store scope; ScopeBoth is expanded when
+// the operation is added to a plan.
+type Operation struct {
+	Type  OpType         `json:"type"`
+	Scope registry.Scope `json:"scope,omitempty"`
+	// Name is the variable set by OpSetVar or deleted by OpDeleteVar
+	Name string `json:"name,omitempty"`
+	// Value is the variable value, the PATH entry or the directory
+	Value string `json:"value"`
+}
+
+// Variable returns the name of the variable the operation changes,
+// or an empty string for operations that don't touch the environment
+func (op Operation) Variable() string {
+	switch op.Type {
+	case OpSetVar, OpDeleteVar:
+		return op.Name
+	case OpAppendPath, OpPrependPath, OpRemovePath:
+		return pathVariable
+	}
+	return ""
+}
+
+// String describes the operation in one line
+func (op Operation) String() string {
+	switch op.Type {
+	case OpSetVar:
+		return fmt.Sprintf("set %s %s=%s", op.Scope, op.Name, op.Value)
+	case OpDeleteVar:
+		return fmt.Sprintf("delete %s %s", op.Scope, op.Name)
+	case OpAppendPath:
+		return fmt.Sprintf("append %s to %s PATH", op.Value, op.Scope)
+	case OpPrependPath:
+		return fmt.Sprintf("prepend %s to %s PATH", op.Value, op.Scope)
+	case OpRemovePath:
+		return fmt.Sprintf("remove %s from %s PATH", op.Value, op.Scope)
+	case OpCreateDir:
+		return fmt.Sprintf("create directory %s", op.Value)
+	}
+	return string(op.Type)
+}
+
+// validate checks that the operation can be applied
+func (op Operation) validate() error {
+	switch op.Type {
+	case OpSetVar, OpDeleteVar:
+		if op.Name == "" {
+			return fmt.Errorf("%s: missing variable name", op.Type)
+		}
+	case OpAppendPath, OpPrependPath, OpRemovePath:
+		if op.Value == "" {
+			return fmt.Errorf("%s: missing PATH entry", op.Type)
+		}
+	case OpCreateDir:
+		if op.Value == "" {
+			return fmt.Errorf("%s: missing directory", op.Type)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown operation type %q", op.Type)
+	}
+
+	switch op.Scope {
+	case registry.ScopeMachine, registry.ScopeUser, registry.ScopeProcess:
+		return nil
+	}
+	return fmt.Errorf("%s: invalid scope %q", op.Type, op.Scope)
+}
+
+// ChangePlan is an ordered list of operations that can be previewed,
+// saved to a file and applied later
+type ChangePlan struct {
+	Version int       `json:"version"`
+	Created time.Time `json:"created"`
+	// Tools are the tools the plan configures, recorded in backups
+	Tools      []string    `json:"tools,omitempty"`
+	Operations []Operation `json:"operations"`
+}
+
+// New creates an empty plan
+func New() *ChangePlan {
+	return &ChangePlan{
+		Version: Version,
+		Created: time.Now(),
+	}
+}
+
+// Empty reports whether the plan has no operations
+func (p *ChangePlan) Empty() bool {
+	return len(p.Operations) == 0
+}
+
+// RequiresAdmin reports whether applying the plan writes the machine scope
+func (p *ChangePlan) RequiresAdmin() bool {
+	for _, op := range p.Operations {
+		if op.Scope.RequiresAdmin() {
+			return true
+		}
+	}
+	return false
+}
+
+// Scope returns the store scope the plan writes to, ScopeBoth if it writes
+// machine and user variables, or an empty scope if it writes no variables
+func (p *ChangePlan) Scope() registry.Scope {
+	var scope registry.Scope
+	for _, op := range p.Operations {
+		switch {
+		case op.Scope == "" || op.Scope == scope:
+		case scope == "":
+			scope = op.Scope
+		default:
+			return registry.ScopeBoth
+		}
+	}
+	return scope
+}
+
+// SetVar adds an operation setting name to value in scope
+func (p *ChangePlan) SetVar(scope registry.Scope, name, value string) {
+	for _, target := range scope.Targets() {
+		p.Operations = append(p.Operations, Operation{Type: OpSetVar, Scope: target, Name: name, Value: value})
+	}
+}
+
+// DeleteVar adds an operation deleting name from scope
+func (p *ChangePlan) DeleteVar(scope registry.Scope, name string) {
+	for _, target := range scope.Targets() {
+		p.Operations = append(p.Operations, Operation{Type: OpDeleteVar, Scope: target, Name: name})
+	}
+}
+
+// AppendPath adds an operation appending entry to the PATH of scope
+func (p *ChangePlan) AppendPath(scope registry.Scope, entry string) {
+	p.addPathOp(OpAppendPath, scope, entry)
+}
+
+// PrependPath adds an operation putting entry at the front of the PATH of scope
+func (p *ChangePlan) PrependPath(scope registry.Scope, entry string) {
+	p.addPathOp(OpPrependPath, scope, entry)
+}
+
+// RemovePath adds an operation removing entry from the PATH of scope
+func (p *ChangePlan) RemovePath(scope registry.Scope, entry string) {
+	p.addPathOp(OpRemovePath, scope, entry)
+}
+
+// CreateDir adds an operation creating dir and any missing parents
+func (p *ChangePlan) CreateDir(dir string) {
+	p.Operations = append(p.Operations, Operation{Type: OpCreateDir, Value: dir})
+}
+
+// addPathOp adds a PATH operation for every target of scope
+func (p *ChangePlan) addPathOp(opType OpType, scope registry.Scope, entry string) {
+	for _, target := range scope.Targets() {
+		p.Operations = append(p.Operations, Operation{Type: opType, Scope: target, Value: entry})
+	}
+}
+
+// Merge appends the tools and operations of other to the plan
+func (p *ChangePlan) Merge(other *ChangePlan) {
+	p.Tools = append(p.Tools, other.Tools...)
+	p.Operations = append(p.Operations, other.Operations...)
+}
+
+// Validate checks every operation of the plan
+func (p *ChangePlan) Validate() error {
+	if p.Version != Version {
+		return fmt.Errorf("unsupported plan version %d", p.Version)
+	}
+	for i, op := range p.Operations {
+		if err := op.validate(); err != nil {
+			return fmt.Errorf("operation %d: %v", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Save writes the plan to a JSON file
+func (p *ChangePlan) Save(path string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding plan: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("error writing plan: %v", err)
+	}
+	return nil
+}
+
+// Load reads and validates a plan saved with Save
+func Load(path string) (*ChangePlan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading plan: %v", err)
+	}
+	var p ChangePlan
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("error parsing plan: %v", err)
+	}
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid plan %s: %v", path, err)
+	}
+	return &p, nil
+}
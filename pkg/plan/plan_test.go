@@ -0,0 +1,110 @@
+package plan
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"devpathpro/pkg/registry"
+)
+
+func TestScope(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(p *ChangePlan)
+		want  registry.Scope
+	}{
+		{"empty", func(p *ChangePlan) {}, ""},
+		{"directories only", func(p *ChangePlan) { p.CreateDir("/x") }, ""},
+		{"user", func(p *ChangePlan) {
+			p.CreateDir("/x")
+			p.SetVar(registry.ScopeUser, "A", "1")
+			p.AppendPath(registry.ScopeUser, "/bin")
+		}, registry.ScopeUser},
+		{"machine", func(p *ChangePlan) { p.DeleteVar(registry.ScopeMachine, "A") }, registry.ScopeMachine},
+		{"both", func(p *ChangePlan) { p.SetVar(registry.ScopeBoth, "A", "1") }, registry.ScopeBoth},
+		{"mixed", func(p *ChangePlan) {
+			p.SetVar(registry.ScopeUser, "A", "1")
+			p.RemovePath(registry.ScopeMachine, "/bin")
+		}, registry.ScopeBoth},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New()
+			tt.build(p)
+			if got := p.Scope(); got != tt.want {
+				t.Errorf("Scope() = %q, want %q", got, tt.want)
+			}
+			if got, want := p.RequiresAdmin(), tt.want.RequiresAdmin(); got != want {
+				t.Errorf("RequiresAdmin() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestBothExpandsToTargets(t *testing.T) {
+	p := New()
+	p.SetVar(registry.ScopeBoth, "A", "1")
+	p.PrependPath(registry.ScopeBoth, "/bin")
+	want := []Operation{
+		{Type: OpSetVar, Scope: registry.ScopeMachine, Name: "A", Value: "1"},
+		{Type: OpSetVar, Scope: registry.ScopeUser, Name: "A", Value: "1"},
+		{Type: OpPrependPath, Scope: registry.ScopeMachine, Value: "/bin"},
+		{Type: OpPrependPath, Scope: registry.ScopeUser, Value: "/bin"},
+	}
+	if !reflect.DeepEqual(p.Operations, want) {
+		t.Errorf("operations = %+v, want %+v", p.Operations, want)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		op      Operation
+		wantErr bool
+	}{
+		{"set", Operation{Type: OpSetVar, Scope: registry.ScopeUser, Name: "A", Value: "1"}, false},
+		{"set empty value", Operation{Type: OpSetVar, Scope: registry.ScopeUser, Name: "A"}, false},
+		{"set without name", Operation{Type: OpSetVar, Scope: registry.ScopeUser, Value: "1"}, true},
+		{"delete without name", Operation{Type: OpDeleteVar, Scope: registry.ScopeUser}, true},
+		{"append without entry", Operation{Type: OpAppendPath, Scope: registry.ScopeUser}, true},
+		{"both is not a store scope", Operation{Type: OpAppendPath, Scope: registry.ScopeBoth, Value: "/bin"}, true},
+		{"missing scope", Operation{Type: OpRemovePath, Value: "/bin"}, true},
+		{"directory without scope", Operation{Type: OpCreateDir, Value: "/x"}, false},
+		{"directory without path", Operation{Type: OpCreateDir}, true},
+		{"unknown type", Operation{Type: "rename", Scope: registry.ScopeUser}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := New()
+			p.Operations = []Operation{tt.op}
+			if err := p.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, want error: %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	p := New()
+	p.Version = Version + 1
+	if err := p.Validate(); err == nil {
+		t.Error("Validate() accepted an unknown plan version")
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	p := New()
+	p.Tools = []string{"Go"}
+	p.SetVar(registry.ScopeUser, "GOPATH", "/go")
+	p.AppendPath(registry.ScopeUser, "/go/bin")
+	file := filepath.Join(t.TempDir(), "plan.json")
+	if err := p.Save(file); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded.Tools, p.Tools) || !reflect.DeepEqual(loaded.Operations, p.Operations) {
+		t.Errorf("Load() = %+v, want %+v", loaded, p)
+	}
+}
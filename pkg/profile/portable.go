@@ -0,0 +1,409 @@
+package profile
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"devpathpro/pkg/config"
+	"devpathpro/pkg/manifest"
+	"devpathpro/pkg/pathedit"
+	"devpathpro/pkg/plan"
+	"devpathpro/pkg/registry"
+	"devpathpro/pkg/tools"
+	"devpathpro/pkg/version"
+)
+
+// Portable describes a development environment without the paths of the
+// machine it was exported from, so that it can be reproduced on another:
+//
+//	[tools.Java]
+//	version = "21"
+//	options = ["Basic"]
+//	scope = "user"
+//
+//	[variables.user]
+//	M2_REPO = '%USERPROFILE%\.m2\repository'
+//
+//	[path]
+//	user = ['%JAVA_HOME%\bin', '%USERPROFILE%\bin']
+//
+// Paths start with a variable reference, such as %USERPROFILE% or the home
+// variable of a tool, where one matches.
+type Portable struct {
+	// Tools are the tools to configure by program name
+	Tools map[string]PortableTool `toml:"tools"`
+	// Variables are other variables to set by scope and name
+	Variables map[registry.Scope]map[string]string `toml:"variables,omitempty"`
+	// Path are PATH entries by scope, in the order they should appear in
+	Path map[registry.Scope][]string `toml:"path,omitempty"`
+}
+
+// PortableTool describes how one tool of a portable environment is configured
+type PortableTool struct {
+	// Version is a version constraint the installation must satisfy; empty accepts any version
+	Version string `toml:"version,omitempty"`
+	// Options are the option groups to set; empty sets every variable
+	Options []string `toml:"options,omitempty"`
+	// Scope is where the variables are written; empty uses the tool's default scope
+	Scope registry.Scope `toml:"scope,omitempty"`
+}
+
+// targetScopes are the scopes variables and PATH entries are imported into,
+// machine first as PATH is searched
+var targetScopes = []registry.Scope{registry.ScopeMachine, registry.ScopeUser}
+
+// Resolution is what importing a portable environment does on this machine
+type Resolution struct {
+	// Tools are the installations found for the tools, or why none was
+	Tools []manifest.Result
+	// Skipped are the variables and PATH entries left out, with the reason
+	Skipped []string
+	// Plan configures the tools found, sets the variables and orders PATH
+	Plan *plan.ChangePlan
+}
+
+// Export describes how the named tools, or all configured tools, and the
+// named variables are set up in the store, with the PATH entries of scope.
+// Version constraints accept the detected major version. It also returns
+// the tools that are not configured.
+func Export(store registry.EnvStore, programs []config.Program, names, variables []string, scope registry.Scope) (*Portable, []string, error) {
+	captured, missing, err := Capture(store, programs, names)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	lookup := storeLookup(store)
+	p := &Portable{Tools: make(map[string]PortableTool)}
+	refNames := append([]string(nil), pathedit.DefaultUnexpandVars...)
+	var exported []config.Program
+	var homeRefs []string
+	for _, t := range captured {
+		prog, _ := config.FindProgram(programs, t.Tool)
+		p.Tools[prog.Name] = PortableTool{
+			Version: versionConstraint(tools.DetectVersion(prog, t.Path)),
+			Options: t.Options,
+			Scope:   t.Scope,
+		}
+		exported = append(exported, prog)
+		for _, name := range prog.HomeVariables() {
+			refNames = append(refNames, name)
+			homeRefs = append(homeRefs, registry.VarReference(name))
+		}
+	}
+	var refs []registry.VarRef
+	for _, name := range refNames {
+		if value, ok := lookup(name); ok && value != "" {
+			refs = append(refs, registry.VarRef{Name: name, Value: registry.ExpandVars(value, lookup)})
+		}
+	}
+
+	for _, name := range variables {
+		value, varScope, err := findVariable(store, name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if p.Variables == nil {
+			p.Variables = make(map[registry.Scope]map[string]string)
+		}
+		if p.Variables[varScope] == nil {
+			p.Variables[varScope] = make(map[string]string)
+		}
+		p.Variables[varScope][name] = portablePath(value, lookup, refs)
+	}
+
+	for _, target := range scope.Targets() {
+		value, _, err := store.Get(target, registry.PathVariable)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s PATH: %v", target, err)
+		}
+		for _, entry := range registry.SplitPathList(value) {
+			dir := registry.ExpandVars(entry, lookup)
+			if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+				continue
+			}
+			// Importing adds the directories of the tools it finds, so the
+			// entries of a tool are only kept written with its home variable
+			entry = portablePath(entry, lookup, refs)
+			if config.FindOwner(dir, exported, lookup) != "" && !hasAnyPrefix(entry, homeRefs) {
+				continue
+			}
+			if indexOfDir(p.Path[target], dir, lookup) >= 0 {
+				continue
+			}
+			if p.Path == nil {
+				p.Path = make(map[registry.Scope][]string)
+			}
+			p.Path[target] = append(p.Path[target], entry)
+		}
+	}
+	return p, missing, nil
+}
+
+// LoadPortable reads a portable environment and checks it against the known programs
+func LoadPortable(path string, programs []config.Program) (*Portable, error) {
+	var p Portable
+	meta, err := toml.DecodeFile(path, &p)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", path, err)
+	}
+	if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+		return nil, fmt.Errorf("%s: unknown key %s", path, undecoded[0])
+	}
+	if err := p.Validate(programs); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return &p, nil
+}
+
+// Write writes the portable environment as TOML
+func (p *Portable) Write(w io.Writer) error {
+	return toml.NewEncoder(w).Encode(p)
+}
+
+// Validate checks that every tool is known with valid constraint, option
+// groups and scope, and that variables and PATH entries are given for the
+// user or machine scope
+func (p *Portable) Validate(programs []config.Program) error {
+	if len(p.Tools) == 0 && len(p.Variables) == 0 && len(p.Path) == 0 {
+		return fmt.Errorf("nothing to import")
+	}
+	for _, name := range p.ToolNames() {
+		t := p.Tools[name]
+		prog, ok := config.FindProgram(programs, name)
+		if !ok {
+			return fmt.Errorf("tools.%s: unknown tool", name)
+		}
+		if t.Version != "" {
+			if _, err := version.ParseConstraint(t.Version); err != nil {
+				return fmt.Errorf("tools.%s: %v", name, err)
+			}
+		}
+		if _, err := tools.OptionVariables(prog, t.Options); err != nil {
+			return fmt.Errorf("tools.%s: %v", name, err)
+		}
+		if t.Scope != "" {
+			if _, err := registry.ParseScope(string(t.Scope)); err != nil {
+				return fmt.Errorf("tools.%s: %v", name, err)
+			}
+		}
+	}
+	for scope := range p.Variables {
+		if err := checkTargetScope(scope); err != nil {
+			return fmt.Errorf("variables.%s: %v", scope, err)
+		}
+	}
+	for scope := range p.Path {
+		if err := checkTargetScope(scope); err != nil {
+			return fmt.Errorf("path.%s: %v", scope, err)
+		}
+	}
+	return nil
+}
+
+// ToolNames returns the tools in name order
+func (p *Portable) ToolNames() []string {
+	names := make([]string, 0, len(p.Tools))
+	for name := range p.Tools {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return strings.ToLower(names[i]) < strings.ToLower(names[j])
+	})
+	return names
+}
+
+// Resolve finds an installation satisfying every tool on this machine and
+// plans configuring the ones found, setting the variables and adding the
+// PATH entries. Entries already in PATH are moved into the exported order
+// where they are. Variables and entries referring to unknown variables or
+// to missing directories are skipped.
+func (p *Portable) Resolve(store registry.EnvStore, programs []config.Program) (*Resolution, error) {
+	r := &Resolution{Plan: plan.New()}
+	for _, name := range p.ToolNames() {
+		t := p.Tools[name]
+		m := &manifest.Manifest{Tools: map[string]manifest.Requirement{
+			name: {Version: t.Version, Options: t.Options},
+		}}
+		results, err := m.Check(store, programs, t.Scope)
+		if err != nil {
+			return nil, err
+		}
+		r.Tools = append(r.Tools, results...)
+	}
+	r.Plan.Merge(manifest.Plan(r.Tools))
+
+	// Variables the plan sets resolve references before the store does
+	storeVars := storeLookup(store)
+	lookup := func(name string) (string, bool) {
+		for i := len(r.Plan.Operations) - 1; i >= 0; i-- {
+			op := r.Plan.Operations[i]
+			if op.Type == plan.OpSetVar && strings.EqualFold(op.Name, name) {
+				return op.Value, true
+			}
+		}
+		return storeVars(name)
+	}
+	for _, scope := range targetScopes {
+		if len(p.Variables[scope]) == 0 {
+			continue
+		}
+		names := make([]string, 0, len(p.Variables[scope]))
+		for name := range p.Variables[scope] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			value := p.Variables[scope][name]
+			if unknown := unknownVars(value, lookup); len(unknown) > 0 {
+				r.Skipped = append(r.Skipped, fmt.Sprintf("[%s] %s: unknown variable %s", scope, name, strings.Join(unknown, ", ")))
+				continue
+			}
+			r.Plan.SetVar(scope, name, value)
+		}
+	}
+
+	for _, scope := range targetScopes {
+		if len(p.Path[scope]) == 0 {
+			continue
+		}
+		current, _, err := r.Plan.Value(store, scope, registry.PathVariable)
+		if err != nil {
+			return nil, err
+		}
+		entries := registry.SplitPathList(current)
+		var wanted []string
+		for _, entry := range p.Path[scope] {
+			if unknown := unknownVars(entry, lookup); len(unknown) > 0 {
+				r.Skipped = append(r.Skipped, fmt.Sprintf("[%s] PATH %s: unknown variable %s", scope, entry, strings.Join(unknown, ", ")))
+				continue
+			}
+			dir := registry.ExpandVars(entry, lookup)
+			if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+				r.Skipped = append(r.Skipped, fmt.Sprintf("[%s] PATH %s: %s not found", scope, entry, dir))
+				continue
+			}
+			wanted = append(wanted, dir)
+			if indexOfDir(entries, dir, lookup) < 0 {
+				entries = append(entries, entry)
+			}
+		}
+		if ordered := registry.JoinPathList(orderEntries(entries, wanted, lookup)); ordered != current {
+			r.Plan.SetVar(scope, registry.PathVariable, ordered)
+		}
+	}
+
+	minimal, err := r.Plan.Minimize(store)
+	if err != nil {
+		return nil, err
+	}
+	r.Plan = minimal
+	return r, nil
+}
+
+// versionConstraint returns a constraint accepting the major version of a
+// detected version, or its minor version for 0.x and 1.x versions such as Go
+// 1.22, or "" if the version is unknown
+func versionConstraint(detected string) string {
+	v, err := version.Parse(detected)
+	if err != nil {
+		return ""
+	}
+	if v.Major() <= 1 && len(v.Numbers) > 1 {
+		return fmt.Sprintf("%d.%d", v.Numbers[0], v.Numbers[1])
+	}
+	return fmt.Sprintf("%d", v.Major())
+}
+
+// findVariable returns the value of a variable and the scope it is set in,
+// the user scope first
+func findVariable(store registry.EnvStore, name string) (string, registry.Scope, error) {
+	for _, scope := range []registry.Scope{registry.ScopeUser, registry.ScopeMachine} {
+		value, ok, err := store.Get(scope, name)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to read %s %s: %v", scope, name, err)
+		}
+		if ok {
+			return value, scope, nil
+		}
+	}
+	return "", "", fmt.Errorf("variable %s is not set", name)
+}
+
+// portablePath returns value with the longest matching variable value at its
+// start replaced by a reference; values with references are kept as they are
+func portablePath(value string, lookup func(string) (string, bool), refs []registry.VarRef) string {
+	if registry.ExpandVars(value, lookup) != value {
+		return value
+	}
+	return registry.UnexpandVars(value, refs)
+}
+
+// unknownVars returns the variables value refers to that lookup doesn't know
+func unknownVars(value string, lookup func(string) (string, bool)) []string {
+	var unknown []string
+	registry.ExpandVars(value, func(name string) (string, bool) {
+		expanded, ok := lookup(name)
+		if !ok {
+			unknown = append(unknown, name)
+		}
+		return expanded, ok
+	})
+	return unknown
+}
+
+// orderEntries moves the entries naming the wanted directories into the
+// order of wanted, keeping the positions they take up and the other entries
+// where they are
+func orderEntries(entries, wanted []string, lookup func(string) (string, bool)) []string {
+	var slots []int
+	var matched []string
+	rank := make(map[string]int)
+	for i, entry := range entries {
+		if n := indexOfDir(wanted, registry.ExpandVars(entry, lookup), lookup); n >= 0 {
+			slots = append(slots, i)
+			matched = append(matched, entry)
+			rank[entry] = n
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return rank[matched[i]] < rank[matched[j]]
+	})
+	ordered := append([]string(nil), entries...)
+	for i, slot := range slots {
+		ordered[slot] = matched[i]
+	}
+	return ordered
+}
+
+// indexOfDir returns the index of the first entry naming dir once expanded, or -1
+func indexOfDir(entries []string, dir string, lookup func(string) (string, bool)) int {
+	for i, entry := range entries {
+		if registry.SamePath(registry.ExpandVars(entry, lookup), dir) {
+			return i
+		}
+	}
+	return -1
+}
+
+// hasAnyPrefix reports whether value starts with one of the prefixes
+func hasAnyPrefix(value string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(value, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTargetScope accepts the user and machine scopes
+func checkTargetScope(scope registry.Scope) error {
+	if scope != registry.ScopeUser && scope != registry.ScopeMachine {
+		return fmt.Errorf("invalid scope %q: expected user or machine", scope)
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+// Package profile records the tools configured in the environment as named
+// profiles and switches the environment between them.
+package profile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"devpathpro/pkg/config"
+	"devpathpro/pkg/plan"
+	"devpathpro/pkg/registry"
+	"devpathpro/pkg/tools"
+)
+
+// Capture records how the named tools, or every known tool if names is
+// empty, are configured in the store: the installation on PATH, preferring
+// the one its home variable points at, the scope of that PATH and the option
+// groups whose variables are all set. It also returns the tools that are not configured.
+func Capture(store registry.EnvStore, programs []config.Program, names []string) ([]config.ProfileTool, []string, error) {
+	selected := programs
+	if len(names) > 0 {
+		selected = nil
+		for _, name := range names {
+			prog, ok := config.FindProgram(programs, name)
+			if !ok {
+				return nil, nil, fmt.Errorf("unknown tool %q", name)
+			}
+			selected = append(selected, prog)
+		}
+	}
+
+	lookup := storeLookup(store)
+	var captured []config.ProfileTool
+	var missing []string
+	for _, prog := range selected {
+		if prog.ExecutableName == "" {
+			continue
+		}
+		tool, ok, err := capture(store, prog, lookup)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			missing = append(missing, prog.Name)
+			continue
+		}
+		captured = append(captured, tool)
+	}
+	return captured, missing, nil
+}
+
+// capture finds the configured installation of a program: the one on PATH
+// under its home variable, else the first on PATH, machine PATH first as
+// Windows searches it
+func capture(store registry.EnvStore, prog config.Program, lookup func(string) (string, bool)) (config.ProfileTool, bool, error) {
+	var homes []string
+	for _, name := range prog.HomeVariables() {
+		if home, ok := lookup(name); ok && home != "" {
+			homes = append(homes, registry.ExpandVars(home, lookup))
+		}
+	}
+
+	var found *config.ProfileTool
+	for _, scope := range []registry.Scope{registry.ScopeMachine, registry.ScopeUser} {
+		value, _, err := store.Get(scope, registry.PathVariable)
+		if err != nil {
+			return config.ProfileTool{}, false, fmt.Errorf("failed to read %s PATH: %v", scope, err)
+		}
+		for _, entry := range registry.SplitPathList(value) {
+			dir := registry.ExpandVars(entry, lookup)
+			executable := filepath.Join(dir, prog.Executable())
+			if info, err := os.Stat(executable); err != nil || info.IsDir() {
+				continue
+			}
+			underHome := false
+			for _, home := range homes {
+				underHome = underHome || registry.IsUnderPath(dir, home)
+			}
+			if found != nil && !underHome {
+				continue
+			}
+			options, err := configuredOptions(store, scope, prog)
+			if err != nil {
+				return config.ProfileTool{}, false, err
+			}
+			found = &config.ProfileTool{Tool: prog.Name, Path: executable, Options: options, Scope: scope}
+			if underHome {
+				return *found, true, nil
+			}
+		}
+	}
+	if found == nil {
+		return config.ProfileTool{}, false, nil
+	}
+	return *found, true, nil
+}
+
+// configuredOptions returns the option groups of a program whose variables
+// are all set in scope, or nil if that is every group
+func configuredOptions(store registry.EnvStore, scope registry.Scope, prog config.Program) ([]string, error) {
+	groups := tools.GetConfigOptions(prog)
+	var options []string
+	for _, group := range groups {
+		set := true
+		for _, name := range group.Variables {
+			_, ok, err := store.Get(scope, name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s %s: %v", scope, name, err)
+			}
+			if !ok {
+				set = false
+				break
+			}
+		}
+		if set {
+			options = append(options, group.Name)
+		}
+	}
+	if len(options) == len(groups) {
+		return nil, nil
+	}
+	return options, nil
+}
+
+// BuildPlan returns the changes configuring every tool of a profile would
+// make. A non-empty scope overrides the scopes of the profile.
+func BuildPlan(programs []config.Program, p config.Profile, scope registry.Scope) (*plan.ChangePlan, error) {
+	combined := plan.New()
+	for _, t := range p.Tools {
+		_, toolPlan, err := buildToolPlan(programs, t, scope)
+		if err != nil {
+			return nil, err
+		}
+		combined.Merge(toolPlan)
+	}
+	return combined, nil
+}
+
+// SwitchPlan returns the smallest set of changes that makes the store match
+// a profile. Besides configuring its tools, PATH entries under the home
+// directories the profile replaces, such as the bin directory of another
+// JDK when JAVA_HOME changes, are removed so the profile's installations
+// are found, and so are the variables of option groups the profile leaves
+// out. Entries written with variable references follow the variables and
+// are kept. Tools the profile doesn't list are left alone.
+func SwitchPlan(store registry.EnvStore, programs []config.Program, p config.Profile) (*plan.ChangePlan, error) {
+	lookup := storeLookup(store)
+	combined := plan.New()
+	for _, t := range p.Tools {
+		prog, toolPlan, err := buildToolPlan(programs, t, "")
+		if err != nil {
+			return nil, err
+		}
+
+		var keep []string
+		planned := make(map[string]string)
+		for _, op := range toolPlan.Operations {
+			switch op.Type {
+			case plan.OpAppendPath, plan.OpPrependPath:
+				keep = append(keep, op.Value)
+			case plan.OpSetVar:
+				planned[op.Name] = op.Value
+			}
+		}
+		// The installations being replaced: home variables the profile changes
+		var homes []string
+		for _, name := range prog.HomeVariables() {
+			value, ok := planned[name]
+			if !ok {
+				continue
+			}
+			if home, ok := lookup(name); ok && home != "" {
+				if home = registry.ExpandVars(home, lookup); !registry.SamePath(home, value) {
+					homes = append(homes, home)
+				}
+			}
+		}
+
+		toolScope := tools.ResolveScope(prog, t.Scope)
+		for _, group := range tools.GetConfigOptions(prog) {
+			for _, name := range group.Variables {
+				if _, ok := planned[name]; !ok {
+					combined.DeleteVar(toolScope, name)
+				}
+			}
+		}
+		for _, scope := range toolScope.Targets() {
+			value, _, err := store.Get(scope, registry.PathVariable)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s PATH: %v", scope, err)
+			}
+			for _, entry := range registry.SplitPathList(value) {
+				if registry.ExpandVars(entry, lookup) != entry || registry.ContainsPath(keep, entry) {
+					continue
+				}
+				for _, home := range homes {
+					if registry.IsUnderPath(entry, home) {
+						combined.RemovePath(scope, entry)
+						break
+					}
+				}
+			}
+		}
+		combined.Merge(toolPlan)
+	}
+	return combined.Minimize(store)
+}
+
+// buildToolPlan returns the program of a profile tool and the plan configuring
+// its installation, in scope or else the scope the profile gives
+func buildToolPlan(programs []config.Program, t config.ProfileTool, scope registry.Scope) (config.Program, *plan.ChangePlan, error) {
+	prog, ok := config.FindProgram(programs, t.Tool)
+	if !ok {
+		return config.Program{}, nil, fmt.Errorf("unknown tool %q", t.Tool)
+	}
+	if info, err := os.Stat(t.Path); err != nil || info.IsDir() {
+		return config.Program{}, nil, fmt.Errorf("%s installation %s not found", prog.Name, t.Path)
+	}
+	vars, err := tools.OptionVariables(prog, t.Options)
+	if err != nil {
+		return config.Program{}, nil, err
+	}
+	if scope == "" {
+		scope = tools.ResolveScope(prog, t.Scope)
+	}
+	return prog, tools.BuildPlan(scope, prog, t.Path, vars), nil
+}
+
+// storeLookup reads variables from the user scope, then the machine scope,
+// then the process environment
+func storeLookup(store registry.EnvStore) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		for _, scope := range []registry.Scope{registry.ScopeUser, registry.ScopeMachine} {
+			if value, ok, err := store.Get(scope, name); err == nil && ok {
+				return value, true
+			}
+		}
+		return os.LookupEnv(name)
+	}
+}
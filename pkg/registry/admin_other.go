@@ -0,0 +1,44 @@
+//go:build !windows
+
+package registry
+
+import (
+	"os"
+	"path/filepath"
+
+	"devpathpro/pkg/shell"
+)
+
+// machineStateFile records the machine variables outside Windows
+const machineStateFile = "/etc/devpathpro/environment.json"
+
+// IsAdmin reports whether the program runs as root
+func IsAdmin() bool {
+	return os.Geteuid() == 0
+}
+
+// NotifyEnvironmentChange is a no-op outside Windows; shells pick up
+// changed startup files when they start
+func NotifyEnvironmentChange() {}
+
+// NewDefaultStore returns the store used when no backend is chosen explicitly.
+// Outside Windows it writes variables into shell startup files: the user's
+// ~/.profile, ~/.bashrc, ~/.zshrc, fish and environment.d configuration,
+// and /etc/profile.d for the machine scope.
+func NewDefaultStore() EnvStore {
+	home, _ := os.UserHomeDir()
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		configDir = filepath.Join(home, ".config")
+	}
+	return NewProfileStore(
+		ProfileScope{
+			State:    filepath.Join(configDir, "DevPathPro", "environment.json"),
+			Profiles: shell.UserProfiles(home),
+		},
+		ProfileScope{
+			State:    machineStateFile,
+			Profiles: shell.SystemProfiles(),
+		},
+	)
+}
@@ -0,0 +1,61 @@
+//go:build windows
+
+package registry
+
+import (
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// IsAdmin checks if the program has administrator privileges by verifying membership
+// in the Windows Administrators group using Windows API
+func IsAdmin() bool {
+	var sid *windows.SID
+	// Get SID for Windows Administrators group
+	err := windows.AllocateAndInitializeSid(
+		&windows.SECURITY_NT_AUTHORITY,
+		2,
+		windows.SECURITY_BUILTIN_DOMAIN_RID,
+		windows.DOMAIN_ALIAS_RID_ADMINS,
+		0, 0, 0, 0, 0, 0,
+		&sid)
+	if err != nil {
+		return false
+	}
+	defer windows.FreeSid(sid)
+
+	// Check if current process is a member of Administrators group
+	token := windows.Token(0)
+	member, err := token.IsMember(sid)
+	if err != nil {
+		return false
+	}
+	return member
+}
+
+// NotifyEnvironmentChange broadcasts a message to all windows to notify them about
+// environment variables changes using Windows API
+func NotifyEnvironmentChange() {
+	dll, err := syscall.LoadDLL("user32.dll")
+	if err != nil {
+		return
+	}
+
+	proc, err := dll.FindProc("SendMessageTimeoutW")
+	if err != nil {
+		return
+	}
+
+	msgPtr, _ := syscall.UTF16PtrFromString("Environment")
+	proc.Call(
+		uintptr(0xFFFF), // HWND_BROADCAST
+		uintptr(0x001A), // WM_SETTINGCHANGE
+		0,
+		uintptr(unsafe.Pointer(msgPtr)),
+		uintptr(0x2), // SMTO_ABORTIFHUNG
+		uintptr(5000),
+		0,
+	)
+}
@@ -0,0 +1,36 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNotFound is returned when a variable or registry key does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrAccessDenied is returned when the caller may not read or write a scope,
+	// typically when writing machine variables without administrator privileges
+	ErrAccessDenied = errors.New("access denied")
+	// ErrUnsupportedType is returned for registry values that are not strings
+	ErrUnsupportedType = errors.New("unsupported value type")
+)
+
+// Error describes a failed store operation on a single variable
+type Error struct {
+	Op    string // get, set, delete, list
+	Scope Scope
+	Name  string
+	Err   error
+}
+
+func (e *Error) Error() string {
+	if e.Name == "" {
+		return fmt.Sprintf("%s %s environment: %v", e.Op, e.Scope, e.Err)
+	}
+	return fmt.Sprintf("%s %s environment variable %s: %v", e.Op, e.Scope, e.Name, e.Err)
+}
+
+// Unwrap returns the underlying error so callers can use errors.Is
+func (e *Error) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,95 @@
+package registry
+
+import (
+	"os"
+	"strings"
+)
+
+// VarRef is a variable whose value can stand for a path prefix, e.g.
+// USERPROFILE for C:\Users\me
+type VarRef struct {
+	Name  string
+	Value string
+}
+
+// VarReference returns a reference to a variable in the platform's syntax:
+// %NAME% on Windows, $NAME elsewhere
+func VarReference(name string) string {
+	if percentVars {
+		return "%" + name + "%"
+	}
+	return "$" + name
+}
+
+// ExpandVars replaces the variable references in value using lookup: %NAME% on
+// Windows, the way REG_EXPAND_SZ values are expanded, and $NAME or ${NAME}
+// elsewhere, the way shells do. Unknown references are kept as they are.
+func ExpandVars(value string, lookup func(string) (string, bool)) string {
+	if !percentVars {
+		return os.Expand(value, func(name string) string {
+			if expanded, ok := lookup(name); ok {
+				return expanded
+			}
+			return "${" + name + "}"
+		})
+	}
+	if !strings.Contains(value, "%") {
+		return value
+	}
+
+	var sb strings.Builder
+	rest := value
+	for {
+		start := strings.Index(rest, "%")
+		if start < 0 {
+			sb.WriteString(rest)
+			return sb.String()
+		}
+		end := strings.Index(rest[start+1:], "%")
+		if end < 0 {
+			sb.WriteString(rest)
+			return sb.String()
+		}
+		end += start + 1
+
+		sb.WriteString(rest[:start])
+		name := rest[start+1 : end]
+		if expanded, ok := lookup(name); ok && name != "" {
+			sb.WriteString(expanded)
+			rest = rest[end+1:]
+			continue
+		}
+		// Keep the unknown reference; its closing % may open the next one
+		sb.WriteString(rest[start:end])
+		rest = rest[end:]
+	}
+}
+
+// UnexpandVars replaces the longest variable value that is a prefix of path
+// with a reference to the variable, see VarReference. Values without a
+// directory part, like C:, are ignored. It returns path unchanged if no
+// value matches.
+func UnexpandVars(path string, vars []VarRef) string {
+	best := -1
+	bestLen := 0
+	for i, v := range vars {
+		value := strings.TrimRight(v.Value, `\/`)
+		if v.Name == "" || strings.ContainsAny(value, "%$"+pathListSeparator) || !strings.ContainsAny(value, `\/`) {
+			continue
+		}
+		if len(value) <= bestLen || len(path) < len(value) {
+			continue
+		}
+		if normalizePath(path[:len(value)]) != normalizePath(value) {
+			continue
+		}
+		if len(path) > len(value) && path[len(value)] != '\\' && path[len(value)] != '/' {
+			continue
+		}
+		best, bestLen = i, len(value)
+	}
+	if best < 0 {
+		return path
+	}
+	return VarReference(vars[best].Name) + path[bestLen:]
+}
@@ -0,0 +1,144 @@
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// FileStore is an EnvStore backed by a JSON file that maps each scope
+// to its variables. Every write rewrites the whole file, so the file
+// always holds a complete snapshot of what would have been configured.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileStore creates a store backed by the given JSON file.
+// The file is created on the first write if it does not exist.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Path returns the location of the backing file
+func (f *FileStore) Path() string {
+	return f.path
+}
+
+// Get returns the value of a variable in the given scope
+func (f *FileStore) Get(scope Scope, name string) (string, bool, error) {
+	if err := checkScope(scope); err != nil {
+		return "", false, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	values, err := f.load()
+	if err != nil {
+		return "", false, err
+	}
+	key, ok := lookupName(values[scope], name)
+	if !ok {
+		return "", false, nil
+	}
+	return values[scope][key], true, nil
+}
+
+// Set creates or overwrites a variable and saves the file
+func (f *FileStore) Set(scope Scope, name, value string) error {
+	if err := checkScope(scope); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	values, err := f.load()
+	if err != nil {
+		return err
+	}
+	if values[scope] == nil {
+		values[scope] = make(map[string]string)
+	}
+	if key, ok := lookupName(values[scope], name); ok {
+		name = key
+	}
+	values[scope][name] = value
+	return f.save(values)
+}
+
+// Delete removes a variable and saves the file
+func (f *FileStore) Delete(scope Scope, name string) error {
+	if err := checkScope(scope); err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	values, err := f.load()
+	if err != nil {
+		return err
+	}
+	key, ok := lookupName(values[scope], name)
+	if !ok {
+		return nil
+	}
+	delete(values[scope], key)
+	return f.save(values)
+}
+
+// List returns all variables in the given scope
+func (f *FileStore) List(scope Scope) (map[string]string, error) {
+	if err := checkScope(scope); err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	values, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]string, len(values[scope]))
+	for key, value := range values[scope] {
+		result[key] = value
+	}
+	return result, nil
+}
+
+// load reads the backing file; a missing file is an empty store
+func (f *FileStore) load() (map[Scope]map[string]string, error) {
+	values := make(map[Scope]map[string]string)
+	data, err := os.ReadFile(f.path)
+	if os.IsNotExist(err) {
+		return values, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read environment file: %v", err)
+	}
+	if len(data) == 0 {
+		return values, nil
+	}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse environment file %s: %v", f.path, err)
+	}
+	return values, nil
+}
+
+// save writes all scopes back to the backing file
+func (f *FileStore) save(values map[Scope]map[string]string) error {
+	if dir := filepath.Dir(f.path); dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create directory for environment file: %v", err)
+		}
+	}
+	data, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode environment file: %v", err)
+	}
+	if err := os.WriteFile(f.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write environment file: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package registry
+
+import "sync"
+
+// MemoryStore is an EnvStore that keeps all variables in memory.
+// It is intended for tests and dry runs.
+type MemoryStore struct {
+	mu     sync.Mutex
+	values map[Scope]map[string]string
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		values: make(map[Scope]map[string]string),
+	}
+}
+
+// Get returns the value of a variable in the given scope
+func (m *MemoryStore) Get(scope Scope, name string) (string, bool, error) {
+	if err := checkScope(scope); err != nil {
+		return "", false, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	vars := m.values[scope]
+	key, ok := lookupName(vars, name)
+	if !ok {
+		return "", false, nil
+	}
+	return vars[key], true, nil
+}
+
+// Set creates or overwrites a variable, keeping the casing of an existing name
+func (m *MemoryStore) Set(scope Scope, name, value string) error {
+	if err := checkScope(scope); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	vars := m.values[scope]
+	if vars == nil {
+		vars = make(map[string]string)
+		m.values[scope] = vars
+	}
+	if key, ok := lookupName(vars, name); ok {
+		name = key
+	}
+	vars[name] = value
+	return nil
+}
+
+// Delete removes a variable from the given scope
+func (m *MemoryStore) Delete(scope Scope, name string) error {
+	if err := checkScope(scope); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if key, ok := lookupName(m.values[scope], name); ok {
+		delete(m.values[scope], key)
+	}
+	return nil
+}
+
+// List returns a copy of all variables in the given scope
+func (m *MemoryStore) List(scope Scope) (map[string]string, error) {
+	if err := checkScope(scope); err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make(map[string]string, len(m.values[scope]))
+	for key, value := range m.values[scope] {
+		result[key] = value
+	}
+	return result, nil
+}
@@ -0,0 +1,106 @@
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// pathListSeparator separates entries of PATH-like variables
+const pathListSeparator = string(os.PathListSeparator)
+
+// SplitPathList splits a PATH-like value into its entries, dropping empty ones
+func SplitPathList(value string) []string {
+	var entries []string
+	for _, entry := range strings.Split(value, pathListSeparator) {
+		if strings.TrimSpace(entry) != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// JoinPathList joins PATH entries back into a single value
+func JoinPathList(entries []string) string {
+	return strings.Join(entries, pathListSeparator)
+}
+
+// ContainsPath reports whether entry is already in the list,
+// comparing paths the way the platform resolves them
+func ContainsPath(entries []string, entry string) bool {
+	normalized := normalizePath(entry)
+	for _, e := range entries {
+		if normalizePath(e) == normalized {
+			return true
+		}
+	}
+	return false
+}
+
+// AppendPathEntry appends entry to a PATH-like value unless it is already present.
+// It returns the new value and whether it changed.
+func AppendPathEntry(value, entry string) (string, bool) {
+	entries := SplitPathList(value)
+	if ContainsPath(entries, entry) {
+		return value, false
+	}
+	return JoinPathList(append(entries, entry)), true
+}
+
+// PrependPathEntry puts entry at the front of a PATH-like value, moving it
+// there if it is already present. It returns the new value and whether it changed.
+func PrependPathEntry(value, entry string) (string, bool) {
+	entries := SplitPathList(value)
+	if len(entries) > 0 && normalizePath(entries[0]) == normalizePath(entry) {
+		return value, false
+	}
+	updated := []string{entry}
+	for _, e := range entries {
+		if normalizePath(e) != normalizePath(entry) {
+			updated = append(updated, e)
+		}
+	}
+	return JoinPathList(updated), true
+}
+
+// RemovePathEntry removes every occurrence of entry from a PATH-like value.
+// It returns the new value and whether it changed.
+func RemovePathEntry(value, entry string) (string, bool) {
+	entries := SplitPathList(value)
+	var updated []string
+	for _, e := range entries {
+		if normalizePath(e) != normalizePath(entry) {
+			updated = append(updated, e)
+		}
+	}
+	if len(updated) == len(entries) {
+		return value, false
+	}
+	return JoinPathList(updated), true
+}
+
+// SamePath reports whether two PATH entries refer to the same directory
+func SamePath(a, b string) bool {
+	return normalizePath(a) == normalizePath(b)
+}
+
+// IsUnderPath reports whether path is dir or a directory inside it,
+// comparing paths the same way as SamePath
+func IsUnderPath(path, dir string) bool {
+	path, dir = normalizePath(path), normalizePath(dir)
+	return dir != "" && (path == dir || strings.HasPrefix(path, dir+"/"))
+}
+
+// normalizePath normalizes a path for comparison: on Windows backslashes become
+// forward slashes, trailing slashes are removed and, where the file system
+// ignores case, the path is lowercased
+func normalizePath(path string) string {
+	if filepath.Separator == '\\' {
+		path = strings.ReplaceAll(path, "\\", "/")
+	}
+	path = strings.TrimRight(path, "/")
+	if caseInsensitivePaths {
+		path = strings.ToLower(path)
+	}
+	return path
+}
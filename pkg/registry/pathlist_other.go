@@ -0,0 +1,15 @@
+//go:build !windows
+
+package registry
+
+import "runtime"
+
+// PathVariable is the name of the PATH variable as the store keeps it
+const PathVariable = "PATH"
+
+// caseInsensitivePaths is set where paths that differ only in case are the same;
+// macOS file systems ignore case by default
+const caseInsensitivePaths = runtime.GOOS == "darwin"
+
+// percentVars is set where variables reference others as %NAME%, not $NAME
+const percentVars = false
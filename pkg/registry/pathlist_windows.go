@@ -0,0 +1,12 @@
+//go:build windows
+
+package registry
+
+// PathVariable is the name of the PATH variable as the store keeps it
+const PathVariable = "Path"
+
+// caseInsensitivePaths is set where paths that differ only in case are the same
+const caseInsensitivePaths = true
+
+// percentVars is set where variables reference others as %NAME%, not $NAME
+const percentVars = true
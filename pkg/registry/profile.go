@@ -0,0 +1,123 @@
+package registry
+
+import (
+	"fmt"
+	"strings"
+
+	"devpathpro/pkg/shell"
+)
+
+// ProfileScope is where a ProfileStore keeps the variables of one scope
+type ProfileScope struct {
+	// State is the JSON file recording the variables
+	State string
+	// Profiles are the shell startup files the variables are written into
+	Profiles []shell.Profile
+}
+
+// ProfileStore is the EnvStore used outside Windows, where there is no
+// registry. The user and machine variables DevPathPro manages are recorded in
+// a JSON file per scope and, after every change, written as a managed block
+// into the shell startup files of that scope. PATH holds only the entries
+// DevPathPro adds; the block puts them in front of the inherited PATH in the
+// stored order, so moving an entry up takes effect.
+// The process scope is the environment of the running process.
+type ProfileStore struct {
+	scopes map[Scope]ProfileScope
+	state  map[Scope]*FileStore
+}
+
+// NewProfileStore creates a store for the given user and machine locations
+func NewProfileStore(user, machine ProfileScope) *ProfileStore {
+	return &ProfileStore{
+		scopes: map[Scope]ProfileScope{ScopeUser: user, ScopeMachine: machine},
+		state: map[Scope]*FileStore{
+			ScopeUser:    NewFileStore(user.State),
+			ScopeMachine: NewFileStore(machine.State),
+		},
+	}
+}
+
+// Get returns the value of a variable in the given scope
+func (p *ProfileStore) Get(scope Scope, name string) (string, bool, error) {
+	if scope == ScopeProcess {
+		return processGet(name)
+	}
+	if err := checkScope(scope); err != nil {
+		return "", false, err
+	}
+	return p.state[scope].Get(scope, name)
+}
+
+// Set records a variable and rewrites the startup files of its scope
+func (p *ProfileStore) Set(scope Scope, name, value string) error {
+	if scope == ScopeProcess {
+		return processSet(name, value)
+	}
+	if err := checkScope(scope); err != nil {
+		return err
+	}
+	if err := p.state[scope].Set(scope, name, value); err != nil {
+		return err
+	}
+	return p.sync(scope)
+}
+
+// Delete removes a variable and rewrites the startup files of its scope
+func (p *ProfileStore) Delete(scope Scope, name string) error {
+	if scope == ScopeProcess {
+		return processDelete(name)
+	}
+	if err := checkScope(scope); err != nil {
+		return err
+	}
+	if err := p.state[scope].Delete(scope, name); err != nil {
+		return err
+	}
+	return p.sync(scope)
+}
+
+// List returns all variables of a scope
+func (p *ProfileStore) List(scope Scope) (map[string]string, error) {
+	if scope == ScopeProcess {
+		return processList(), nil
+	}
+	if err := checkScope(scope); err != nil {
+		return nil, err
+	}
+	return p.state[scope].List(scope)
+}
+
+// sync writes the variables of a scope into its startup files, or removes
+// the managed block once there are none left
+func (p *ProfileStore) sync(scope Scope) error {
+	vars, err := p.List(scope)
+	if err != nil {
+		return err
+	}
+	env := shell.Env{Vars: make(map[string]string)}
+	for name, value := range vars {
+		if strings.EqualFold(name, PathVariable) {
+			env.Path = SplitPathList(value)
+		} else {
+			env.Vars[name] = value
+		}
+	}
+
+	for _, profile := range p.scopes[scope].Profiles {
+		if env.Empty() {
+			if _, err := shell.RemoveBlock(profile); err != nil {
+				return err
+			}
+			continue
+		}
+		block, err := shell.Render(profile.Kind, env)
+		if err != nil {
+			return err
+		}
+		if _, err := shell.WriteBlock(profile.Path, block); err != nil {
+			return fmt.Errorf("failed to update %s profile: %v", scope, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+//go:build windows
+
+package registry
+
+import (
+	"errors"
+	"syscall"
+
+	winreg "golang.org/x/sys/windows/registry"
+)
+
+const (
+	envKey     = `SYSTEM\CurrentControlSet\Control\Session Manager\Environment`
+	userEnvKey = `Environment`
+)
+
+// RegStore is an EnvStore that persists machine and user variables in the
+// Windows registry through the registry API. Process variables go to the
+// process environment.
+type RegStore struct{}
+
+// NewRegStore creates a registry-backed store
+func NewRegStore() *RegStore {
+	return &RegStore{}
+}
+
+// NewDefaultStore returns the store used when no backend is chosen explicitly
+func NewDefaultStore() EnvStore {
+	return NewRegStore()
+}
+
+// Get returns the raw, unexpanded value of a variable in the given scope
+func (r *RegStore) Get(scope Scope, name string) (string, bool, error) {
+	if scope == ScopeProcess {
+		return processGet(name)
+	}
+	key, err := openKey(scope, winreg.QUERY_VALUE)
+	if err != nil {
+		return "", false, &Error{Op: "get", Scope: scope, Name: name, Err: err}
+	}
+	defer key.Close()
+
+	value, _, exists, err := readString(key, name)
+	if err != nil {
+		return "", false, &Error{Op: "get", Scope: scope, Name: name, Err: err}
+	}
+	return value, exists, nil
+}
+
+// Set writes a variable to the given scope, preserving the type of an existing value
+func (r *RegStore) Set(scope Scope, name, value string) error {
+	if scope == ScopeProcess {
+		return processSet(name, value)
+	}
+	key, err := openKey(scope, winreg.QUERY_VALUE|winreg.SET_VALUE)
+	if err != nil {
+		return &Error{Op: "set", Scope: scope, Name: name, Err: err}
+	}
+	defer key.Close()
+
+	_, existing, exists, err := readString(key, name)
+	if err != nil && !errors.Is(err, ErrUnsupportedType) {
+		return &Error{Op: "set", Scope: scope, Name: name, Err: err}
+	}
+
+	if chooseValueType(name, value, existing, exists) == TypeExpandString {
+		err = key.SetExpandStringValue(name, value)
+	} else {
+		err = key.SetStringValue(name, value)
+	}
+	if err != nil {
+		return &Error{Op: "set", Scope: scope, Name: name, Err: translateError(err)}
+	}
+	return nil
+}
+
+// Delete removes a variable from the given scope
+func (r *RegStore) Delete(scope Scope, name string) error {
+	if scope == ScopeProcess {
+		return processDelete(name)
+	}
+	key, err := openKey(scope, winreg.SET_VALUE)
+	if err != nil {
+		return &Error{Op: "delete", Scope: scope, Name: name, Err: err}
+	}
+	defer key.Close()
+
+	if err := key.DeleteValue(name); err != nil {
+		if err = translateError(err); errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return &Error{Op: "delete", Scope: scope, Name: name, Err: err}
+	}
+	return nil
+}
+
+// List returns all string variables of the given scope
+func (r *RegStore) List(scope Scope) (map[string]string, error) {
+	if scope == ScopeProcess {
+		return processList(), nil
+	}
+	key, err := openKey(scope, winreg.QUERY_VALUE)
+	if err != nil {
+		return nil, &Error{Op: "list", Scope: scope, Err: err}
+	}
+	defer key.Close()
+
+	names, err := key.ReadValueNames(0)
+	if err != nil {
+		return nil, &Error{Op: "list", Scope: scope, Err: translateError(err)}
+	}
+
+	vars := make(map[string]string, len(names))
+	for _, name := range names {
+		value, _, exists, err := readString(key, name)
+		if errors.Is(err, ErrUnsupportedType) {
+			continue
+		}
+		if err != nil {
+			return nil, &Error{Op: "list", Scope: scope, Name: name, Err: err}
+		}
+		if exists {
+			vars[name] = value
+		}
+	}
+	return vars, nil
+}
+
+// openKey opens the environment key of a persistent scope
+func openKey(scope Scope, access uint32) (winreg.Key, error) {
+	var (
+		root winreg.Key
+		path string
+	)
+	switch scope {
+	case ScopeMachine:
+		root, path = winreg.LOCAL_MACHINE, envKey
+	case ScopeUser:
+		root, path = winreg.CURRENT_USER, userEnvKey
+	default:
+		return 0, checkScope(scope)
+	}
+
+	key, err := winreg.OpenKey(root, path, access)
+	if err != nil {
+		return 0, translateError(err)
+	}
+	return key, nil
+}
+
+// readString reads a string value together with its type
+func readString(key winreg.Key, name string) (string, ValueType, bool, error) {
+	value, valtype, err := key.GetStringValue(name)
+	if err != nil {
+		err = translateError(err)
+		if errors.Is(err, ErrNotFound) {
+			return "", 0, false, nil
+		}
+		return "", 0, false, err
+	}
+	return value, ValueType(valtype), true, nil
+}
+
+// translateError maps registry API errors to the package's typed errors
+func translateError(err error) error {
+	switch {
+	case errors.Is(err, winreg.ErrNotExist):
+		return ErrNotFound
+	case errors.Is(err, syscall.ERROR_ACCESS_DENIED):
+		return ErrAccessDenied
+	case errors.Is(err, winreg.ErrUnexpectedType):
+		return ErrUnsupportedType
+	}
+	return err
+}
@@ -0,0 +1,147 @@
+package registry
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Scope identifies where an environment variable lives
+type Scope string
+
+const (
+	// ScopeMachine is the system-wide environment (HKLM on Windows)
+	ScopeMachine Scope = "machine"
+	// ScopeUser is the current user's environment (HKCU on Windows)
+	ScopeUser Scope = "user"
+	// ScopeProcess is the environment of the running DevPathPro process
+	ScopeProcess Scope = "process"
+	// ScopeBoth writes to both the machine and the user environment.
+	// It is a write target only; stores never accept it directly.
+	ScopeBoth Scope = "both"
+)
+
+// ParseScope converts a command line value (user, machine or both) to a Scope
+func ParseScope(value string) (Scope, error) {
+	switch scope := Scope(strings.ToLower(strings.TrimSpace(value))); scope {
+	case ScopeUser, ScopeMachine, ScopeBoth:
+		return scope, nil
+	}
+	return "", fmt.Errorf("invalid scope %q: expected user, machine or both", value)
+}
+
+// Targets returns the store scopes a write to this scope touches
+func (s Scope) Targets() []Scope {
+	if s == ScopeBoth {
+		return []Scope{ScopeMachine, ScopeUser}
+	}
+	return []Scope{s}
+}
+
+// RequiresAdmin reports whether writing to this scope needs administrator privileges
+func (s Scope) RequiresAdmin() bool {
+	return s == ScopeMachine || s == ScopeBoth
+}
+
+// EnvStore reads and writes environment variables for a given scope.
+// The Windows registry is one backend; the in-memory and JSON file
+// backends make the configuration pipeline usable off Windows.
+type EnvStore interface {
+	// Get returns the value of a variable and whether it exists
+	Get(scope Scope, name string) (string, bool, error)
+	// Set creates or overwrites a variable
+	Set(scope Scope, name, value string) error
+	// Delete removes a variable; deleting a missing variable is not an error
+	Delete(scope Scope, name string) error
+	// List returns all variables of a scope
+	List(scope Scope) (map[string]string, error)
+}
+
+// OpenStore returns the backend named by a -store value: "registry" (or
+// empty) for the platform's default store, "memory" for a store that
+// forgets everything on exit and "file:PATH" for a JSON file store
+func OpenStore(spec string) (EnvStore, error) {
+	switch kind, path, _ := strings.Cut(strings.TrimSpace(spec), ":"); strings.ToLower(kind) {
+	case "", "registry", "default":
+		if path == "" {
+			return NewDefaultStore(), nil
+		}
+	case "memory":
+		if path == "" {
+			return NewMemoryStore(), nil
+		}
+	case "file":
+		if path != "" {
+			return NewFileStore(path), nil
+		}
+	}
+	return nil, fmt.Errorf("invalid store %q: expected registry, memory or file:PATH", spec)
+}
+
+// Syncer is implemented by stores that copy their variables somewhere else
+// after they change, such as into shell startup files. Transactions call Sync
+// once after they commit or roll back instead of the store doing it on
+// every Set or Delete.
+type Syncer interface {
+	Sync()
+}
+
+// checkScope returns an error for scopes no backend knows about
+func checkScope(scope Scope) error {
+	switch scope {
+	case ScopeMachine, ScopeUser, ScopeProcess:
+		return nil
+	}
+	return fmt.Errorf("unknown scope %q", scope)
+}
+
+// lookupName finds the key matching name case-insensitively,
+// the same way Windows resolves environment variable names
+func lookupName(vars map[string]string, name string) (string, bool) {
+	if _, ok := vars[name]; ok {
+		return name, true
+	}
+	for key := range vars {
+		if strings.EqualFold(key, name) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// processGet reads a variable from the current process environment
+func processGet(name string) (string, bool, error) {
+	value, ok := os.LookupEnv(name)
+	return value, ok, nil
+}
+
+// processSet writes a variable to the current process environment
+func processSet(name, value string) error {
+	if err := os.Setenv(name, value); err != nil {
+		return fmt.Errorf("error setting process environment variable: %v", err)
+	}
+	return nil
+}
+
+// processDelete removes a variable from the current process environment
+func processDelete(name string) error {
+	if err := os.Unsetenv(name); err != nil {
+		return fmt.Errorf("error removing process environment variable: %v", err)
+	}
+	return nil
+}
+
+// processList returns the current process environment as a map
+func processList() map[string]string {
+	vars := make(map[string]string)
+	for _, env := range os.Environ() {
+		// Skip the hidden per-drive variables like "=C:=C:\"
+		if strings.HasPrefix(env, "=") {
+			continue
+		}
+		if i := strings.Index(env, "="); i > 0 {
+			vars[env[:i]] = env[i+1:]
+		}
+	}
+	return vars
+}
@@ -0,0 +1,44 @@
+package registry
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenStore(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "env.json")
+	tests := []struct {
+		spec    string
+		check   func(EnvStore) bool
+		wantErr bool
+	}{
+		{spec: "", check: func(s EnvStore) bool { return s != nil }},
+		{spec: "registry", check: func(s EnvStore) bool { return s != nil }},
+		{spec: "memory", check: func(s EnvStore) bool { _, ok := s.(*MemoryStore); return ok }},
+		{spec: "Memory", check: func(s EnvStore) bool { _, ok := s.(*MemoryStore); return ok }},
+		{spec: "file:" + file, check: func(s EnvStore) bool {
+			f, ok := s.(*FileStore)
+			return ok && f.Path() == file
+		}},
+		{spec: "file:", wantErr: true},
+		{spec: "memory:x", wantErr: true},
+		{spec: "registry:x", wantErr: true},
+		{spec: "etcd", wantErr: true},
+	}
+	for _, tt := range tests {
+		store, err := OpenStore(tt.spec)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("OpenStore(%q) succeeded, want an error", tt.spec)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("OpenStore(%q): %v", tt.spec, err)
+			continue
+		}
+		if !tt.check(store) {
+			t.Errorf("OpenStore(%q) returned %T", tt.spec, store)
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package registry
+
+import "strings"
+
+// ValueType is the registry type of an environment variable.
+// The values match the Windows REG_* constants.
+type ValueType uint32
+
+const (
+	// TypeString is REG_SZ, a plain string
+	TypeString ValueType = 1
+	// TypeExpandString is REG_EXPAND_SZ, a string with %VAR% references
+	TypeExpandString ValueType = 2
+)
+
+func (t ValueType) String() string {
+	switch t {
+	case TypeString:
+		return "REG_SZ"
+	case TypeExpandString:
+		return "REG_EXPAND_SZ"
+	}
+	return "REG_UNKNOWN"
+}
+
+// chooseValueType decides which type a value is written with.
+// Existing values keep their type so REG_SZ variables are not silently
+// turned into REG_EXPAND_SZ; a plain string is only promoted when the
+// new value needs %VAR% expansion. New values are expandable if they
+// reference other variables or are PATH, as Windows itself does.
+func chooseValueType(name, value string, existing ValueType, exists bool) ValueType {
+	needsExpand := strings.Contains(value, "%")
+	if exists && (existing == TypeString || existing == TypeExpandString) {
+		if existing == TypeString && needsExpand {
+			return TypeExpandString
+		}
+		return existing
+	}
+	if needsExpand || strings.EqualFold(name, "Path") {
+		return TypeExpandString
+	}
+	return TypeString
+}
@@ -0,0 +1,35 @@
+package registry
+
+import "testing"
+
+func TestChooseValueType(t *testing.T) {
+	tests := []struct {
+		name     string
+		variable string
+		value    string
+		existing ValueType
+		exists   bool
+		want     ValueType
+	}{
+		{"new plain value", "JAVA_HOME", `C:\jdk`, 0, false, TypeString},
+		{"new value with reference", "GOBIN", `%GOPATH%\bin`, 0, false, TypeExpandString},
+		{"new Path", "Path", `C:\bin`, 0, false, TypeExpandString},
+		{"new PATH in other case", "PATH", `C:\bin`, 0, false, TypeExpandString},
+		{"REG_SZ stays REG_SZ", "JAVA_HOME", `C:\jdk`, TypeString, true, TypeString},
+		{"REG_SZ Path stays REG_SZ", "Path", `C:\bin`, TypeString, true, TypeString},
+		{"REG_SZ promoted on reference", "CLASSPATH", `%JAVA_HOME%\lib`, TypeString, true, TypeExpandString},
+		{"REG_EXPAND_SZ stays without reference", "Path", `C:\bin`, TypeExpandString, true, TypeExpandString},
+		{"REG_EXPAND_SZ stays with reference", "Path", `%GOBIN%`, TypeExpandString, true, TypeExpandString},
+		{"unknown existing type, plain", "JAVA_HOME", `C:\jdk`, 7, true, TypeString},
+		{"unknown existing type, reference", "GOBIN", `%GOPATH%\bin`, 7, true, TypeExpandString},
+		{"unknown existing type, Path", "Path", `C:\bin`, 7, true, TypeExpandString},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := chooseValueType(tt.variable, tt.value, tt.existing, tt.exists)
+			if got != tt.want {
+				t.Errorf("chooseValueType(%q, %q, %v, %v) = %v, want %v", tt.variable, tt.value, tt.existing, tt.exists, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package report
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"devpathpro/pkg/config"
+)
+
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name      string      `xml:"name,attr"`
+	Tests     int         `xml:"tests,attr"`
+	Failures  int         `xml:"failures,attr"`
+	Errors    int         `xml:"errors,attr"`
+	Hostname  string      `xml:"hostname,attr,omitempty"`
+	Timestamp string      `xml:"timestamp,attr"`
+	Cases     []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string         `xml:"name,attr"`
+	ClassName string         `xml:"classname,attr"`
+	Failures  []junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes verify results as JUnit XML: one test case per check,
+// with one failure per issue the check found
+func WriteJUnit(w io.Writer, issues []config.ConfigurationIssue) error {
+	host, _ := os.Hostname()
+	suite := junitSuite{
+		Name:      "devpathpro.verify",
+		Hostname:  host,
+		Timestamp: time.Now().UTC().Format("2006-01-02T15:04:05"),
+	}
+
+	for _, rule := range rulesFor(issues) {
+		tc := junitCase{Name: rule.ID() + " " + rule.Title(), ClassName: "devpathpro.verify." + rule.ID()}
+		for _, issue := range issues {
+			if issue.CheckID != rule.ID() {
+				continue
+			}
+			tc.Failures = append(tc.Failures, junitFailure{
+				Message: issue.Description,
+				Type:    issue.Severity,
+				Text:    fmt.Sprintf("%s\nValue: %s\nSolution: %s", issue.Description, issue.Value, issue.Solution),
+			})
+		}
+		suite.Tests++
+		if len(tc.Failures) > 0 {
+			suite.Failures++
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	doc := junitSuites{Name: ToolName, Tests: suite.Tests, Failures: suite.Failures, Suites: []junitSuite{suite}}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,260 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"devpathpro/pkg/backup"
+	"devpathpro/pkg/config"
+	"devpathpro/pkg/pathedit"
+	"devpathpro/pkg/registry"
+)
+
+// SchemaVersion is the version of the report schemas. It changes whenever a
+// field is renamed, removed or changes meaning; new fields keep the version.
+const SchemaVersion = 1
+
+// ToolName and RepositoryURL identify DevPathPro in report headers, SARIF
+// drivers and JUnit suites
+const (
+	ToolName      = "DevPathPro"
+	RepositoryURL = "https://github.com/AlestackOverglow/devpathpro"
+)
+
+// Format is how a command prints its results
+type Format string
+
+const (
+	// FormatTable is the human-readable output
+	FormatTable Format = "table"
+	FormatJSON  Format = "json"
+	FormatYAML  Format = "yaml"
+)
+
+// ParseFormat converts a command line value (json, yaml or table) to a Format
+func ParseFormat(value string) (Format, error) {
+	switch format := Format(strings.ToLower(strings.TrimSpace(value))); format {
+	case "":
+		return FormatTable, nil
+	case FormatTable, FormatJSON, FormatYAML:
+		return format, nil
+	}
+	return "", fmt.Errorf("invalid output format %q: expected json, yaml or table", value)
+}
+
+// Header identifies a report and the machine it was made on
+type Header struct {
+	SchemaVersion int `json:"schemaVersion" yaml:"schemaVersion"`
+	// Kind is the command that made the report: scan, verify, checks, env, catalog, backups, path, shell, project or profiles
+	Kind      string    `json:"kind" yaml:"kind"`
+	Host      string    `json:"host" yaml:"host"`
+	Generated time.Time `json:"generated" yaml:"generated"`
+	// Source is where the tool that made the report comes from, RepositoryURL
+	Source string `json:"source" yaml:"source"`
+}
+
+// NewHeader returns the header of a report of the given kind made now
+func NewHeader(kind string) Header {
+	host, _ := os.Hostname()
+	return Header{SchemaVersion: SchemaVersion, Kind: kind, Host: host, Generated: time.Now().UTC(), Source: RepositoryURL}
+}
+
+// Installation is one installation of a tool found by scan
+type Installation struct {
+	Path    string `json:"path" yaml:"path"`
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+}
+
+// Tool is the scan result of one tool
+type Tool struct {
+	Name          string         `json:"name" yaml:"name"`
+	Category      string         `json:"category" yaml:"category"`
+	Found         bool           `json:"found" yaml:"found"`
+	Installations []Installation `json:"installations" yaml:"installations"`
+	// ChosenPath is the installation the selection policy picks
+	ChosenPath string `json:"chosenPath,omitempty" yaml:"chosenPath,omitempty"`
+	// Error explains why no installation could be chosen
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// ScanReport lists the tools found on the machine
+type ScanReport struct {
+	Header `yaml:",inline"`
+	Tools  []Tool `json:"tools" yaml:"tools"`
+}
+
+// VerifyReport lists the configuration issues found
+type VerifyReport struct {
+	Header `yaml:",inline"`
+	Issues []config.ConfigurationIssue `json:"issues" yaml:"issues"`
+}
+
+// CheckEntry is a verify check with its current settings
+type CheckEntry struct {
+	ID       string `json:"id" yaml:"id"`
+	Title    string `json:"title" yaml:"title"`
+	Category string `json:"category" yaml:"category"`
+	// Severity is the default severity, or the one the settings force
+	Severity string `json:"severity" yaml:"severity"`
+	Enabled  bool   `json:"enabled" yaml:"enabled"`
+	// CanFix is set if the check can fix some of its issues
+	CanFix bool `json:"canFix" yaml:"canFix"`
+}
+
+// ChecksReport lists the verify checks
+type ChecksReport struct {
+	Header `yaml:",inline"`
+	Checks []CheckEntry `json:"checks" yaml:"checks"`
+}
+
+// Variable is an environment variable in one scope
+type Variable struct {
+	Scope registry.Scope `json:"scope" yaml:"scope"`
+	Name  string         `json:"name" yaml:"name"`
+	Value string         `json:"value" yaml:"value"`
+}
+
+// EnvReport lists environment variables
+type EnvReport struct {
+	Header    `yaml:",inline"`
+	Variables []Variable `json:"variables" yaml:"variables"`
+	// Missing are requested variables that are not set in any listed scope
+	Missing []string `json:"missing,omitempty" yaml:"missing,omitempty"`
+}
+
+// CatalogEntry is a known tool
+type CatalogEntry struct {
+	Name       string         `json:"name" yaml:"name"`
+	Category   string         `json:"category" yaml:"category"`
+	Executable string         `json:"executable" yaml:"executable"`
+	Scope      registry.Scope `json:"scope" yaml:"scope"`
+	Options    []string       `json:"options,omitempty" yaml:"options,omitempty"`
+}
+
+// CatalogReport lists the known tools
+type CatalogReport struct {
+	Header   `yaml:",inline"`
+	Programs []CatalogEntry `json:"programs" yaml:"programs"`
+}
+
+// BackupReport lists the stored backups, oldest first
+type BackupReport struct {
+	Header  `yaml:",inline"`
+	Backups []backup.Info `json:"backups" yaml:"backups"`
+}
+
+// PathReport lists annotated PATH entries
+type PathReport struct {
+	Header  `yaml:",inline"`
+	Entries []pathedit.Entry `json:"entries" yaml:"entries"`
+}
+
+// ShellProfile is a shell startup file kept in sync with the environment
+type ShellProfile struct {
+	Path      string `json:"path" yaml:"path"`
+	Shell     string `json:"shell" yaml:"shell"`
+	PathStyle string `json:"pathStyle,omitempty" yaml:"pathStyle,omitempty"`
+	// State is "in sync", "missing" or "drifted"
+	State string `json:"state" yaml:"state"`
+}
+
+// ShellReport lists the shell startup files with a managed block
+type ShellReport struct {
+	Header   `yaml:",inline"`
+	Profiles []ShellProfile `json:"profiles" yaml:"profiles"`
+}
+
+// Requirement is the check result of one tool of a project manifest
+type Requirement struct {
+	Tool       string `json:"tool" yaml:"tool"`
+	Constraint string `json:"constraint,omitempty" yaml:"constraint,omitempty"`
+	// Status is ok, unconfigured, missing or wrong_version
+	Status  string `json:"status" yaml:"status"`
+	Path    string `json:"path,omitempty" yaml:"path,omitempty"`
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+	Message string `json:"message" yaml:"message"`
+	Changes int    `json:"changes" yaml:"changes"`
+}
+
+// ProjectReport is the result of checking a project manifest
+type ProjectReport struct {
+	Header       `yaml:",inline"`
+	Manifest     string        `json:"manifest" yaml:"manifest"`
+	Met          bool          `json:"met" yaml:"met"`
+	Requirements []Requirement `json:"requirements" yaml:"requirements"`
+}
+
+// ProfileEntry is a saved profile and how the environment compares with it
+type ProfileEntry struct {
+	config.Profile `yaml:",inline"`
+	// Active is set if switching to the profile would change nothing
+	Active bool `json:"active" yaml:"active"`
+	// Changes is the number of changes switching to the profile makes
+	Changes int `json:"changes" yaml:"changes"`
+	// Error explains why the profile cannot be switched to, such as a removed installation
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// ProfileReport lists the saved profiles
+type ProfileReport struct {
+	Header   `yaml:",inline"`
+	Profiles []ProfileEntry `json:"profiles" yaml:"profiles"`
+}
+
+// Write encodes a report as JSON or YAML
+func Write(w io.Writer, format Format, report interface{}) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(report)
+	case FormatYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(report); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+	return fmt.Errorf("%s output is not structured", format)
+}
+
+// IssueWriter returns the writer of a verify report file format: junit or sarif
+func IssueWriter(kind string) (func(io.Writer, []config.ConfigurationIssue) error, error) {
+	switch strings.ToLower(kind) {
+	case "junit":
+		return WriteJUnit, nil
+	case "sarif":
+		return WriteSARIF, nil
+	}
+	return nil, fmt.Errorf("invalid report format %q: expected junit or sarif", kind)
+}
+
+// WriteIssueFile writes verify results to path in the given file format
+func WriteIssueFile(kind, path string, issues []config.ConfigurationIssue) error {
+	write, err := IssueWriter(kind)
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("error creating report directory: %v", err)
+		}
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("error creating report: %v", err)
+	}
+	if err := write(f, issues); err != nil {
+		f.Close()
+		return fmt.Errorf("error writing %s report: %v", kind, err)
+	}
+	return f.Close()
+}
@@ -0,0 +1,28 @@
+package report
+
+import "devpathpro/pkg/config"
+
+// rulesFor returns the checks that produced a verify report: the enabled
+// checks plus any other check an issue comes from
+func rulesFor(issues []config.ConfigurationIssue) []config.Check {
+	rules := config.EnabledChecks()
+	for _, issue := range issues {
+		if indexOf(rules, issue.CheckID) >= 0 {
+			continue
+		}
+		if c, ok := config.FindCheck(issue.CheckID); ok {
+			rules = append(rules, c)
+		}
+	}
+	return rules
+}
+
+// indexOf returns the index of the check with the given ID, or -1
+func indexOf(rules []config.Check, id string) int {
+	for i, rule := range rules {
+		if rule.ID() == id {
+			return i
+		}
+	}
+	return -1
+}
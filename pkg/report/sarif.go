@@ -0,0 +1,122 @@
+package report
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+
+	"devpathpro/pkg/config"
+)
+
+const (
+	sarifVersion = "2.1.0"
+	sarifSchema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           int               `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             sarifMessage      `json:"message"`
+	Locations           []sarifLocation   `json:"locations,omitempty"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+// sarifLevel maps an issue severity to a SARIF level
+func sarifLevel(severity string) string {
+	switch severity {
+	case "HIGH":
+		return "error"
+	case "MEDIUM":
+		return "warning"
+	}
+	return "note"
+}
+
+// WriteSARIF writes verify results as a SARIF 2.1.0 log with one rule per check
+func WriteSARIF(w io.Writer, issues []config.ConfigurationIssue) error {
+	rules := rulesFor(issues)
+	driver := sarifDriver{Name: ToolName, InformationURI: RepositoryURL}
+	for _, rule := range rules {
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   rule.ID(),
+			ShortDescription:     sarifMessage{rule.Title()},
+			DefaultConfiguration: sarifConfiguration{sarifLevel(rule.DefaultSeverity())},
+		})
+	}
+
+	run := sarifRun{Tool: sarifTool{driver}, Results: []sarifResult{}}
+	for _, issue := range issues {
+		result := sarifResult{
+			RuleID:    issue.CheckID,
+			RuleIndex: indexOf(rules, issue.CheckID),
+			Level:     sarifLevel(issue.Severity),
+			Message:   sarifMessage{issue.Description + ". " + issue.Solution + "."},
+			// The fingerprint identifies the same issue across runs
+			PartialFingerprints: map[string]string{"issue/v1": fingerprint(issue.CheckID, issue.Data["problem"], issue.Value)},
+		}
+		if issue.Value != "" {
+			result.Locations = []sarifLocation{{LogicalLocations: []sarifLogicalLocation{{Name: issue.Value, Kind: "variable"}}}}
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: []sarifRun{run}})
+}
+
+// fingerprint hashes the parts that identify an issue
+func fingerprint(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))[:32]
+}
@@ -0,0 +1,168 @@
+package shell
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ActiveVariable names the activated environment while an activation script is in effect
+const ActiveVariable = "DEVPATHPRO_ACTIVE"
+
+// ParseActivationKind converts the name of an interactive shell to the Kind
+// of activation script it runs: bash, zsh and sh share KindSh
+func ParseActivationKind(s string) (Kind, error) {
+	switch strings.ToLower(s) {
+	case "bash", "zsh", "sh":
+		return KindSh, nil
+	case "fish":
+		return KindFish, nil
+	case "powershell", "pwsh":
+		return KindPowerShell, nil
+	case "cmd":
+		return KindCmd, nil
+	}
+	return "", fmt.Errorf("invalid shell %q: expected powershell, cmd, bash, zsh or fish", s)
+}
+
+// Activate returns a script that sets env in the shell running it, with the
+// PATH entries put in front of PATH so they win over the installed tools,
+// and defines a deactivate command that restores the previous values. name
+// is recorded in DEVPATHPRO_ACTIVE. An environment that is already active
+// is deactivated first; cmd refuses instead since a batch file cannot run
+// the deactivate macro.
+func Activate(kind Kind, name string, env Env) (string, error) {
+	vars := make(map[string]string, len(env.Vars)+1)
+	for n, v := range env.Vars {
+		vars[n] = v
+	}
+	vars[ActiveVariable] = name
+	names := make([]string, 0, len(vars))
+	for n := range vars {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	// The variables deactivate restores; DEVPATHPRO_ACTIVE is only ever unset
+	var changed []string
+	for _, n := range names {
+		if n != ActiveVariable {
+			changed = append(changed, n)
+		}
+	}
+	if len(env.Path) > 0 {
+		changed = append(changed, "PATH")
+	}
+
+	var sb strings.Builder
+	switch kind {
+	case KindSh:
+		fmt.Fprintf(&sb, "# DevPathPro activation of %s; run \"deactivate\" to restore the previous environment\n", name)
+		fmt.Fprintf(&sb, "if [ -n \"${%s+x}\" ]; then case \"$(type deactivate 2>/dev/null)\" in *function*) deactivate ;; esac; fi\n", ActiveVariable)
+		for _, n := range changed {
+			fmt.Fprintf(&sb, "unset %s; if [ -n \"${%s+x}\" ]; then %s=\"$%s\"; fi\n", oldName(n), n, oldName(n), n)
+		}
+		for _, n := range names {
+			fmt.Fprintf(&sb, "export %s=%s\n", n, shQuote(vars[n]))
+		}
+		if len(env.Path) > 0 {
+			fmt.Fprintf(&sb, "export PATH=%s\"${PATH:+:$PATH}\"\n", joinQuoted(env.Path, ":", shQuote))
+		}
+		sb.WriteString("deactivate() {\n")
+		for _, n := range changed {
+			fmt.Fprintf(&sb, "    if [ -n \"${%s+x}\" ]; then export %s=\"$%s\"; unset %s; else unset %s; fi\n", oldName(n), n, oldName(n), oldName(n), n)
+		}
+		fmt.Fprintf(&sb, "    unset %s\n    unset -f deactivate\n}\n", ActiveVariable)
+	case KindFish:
+		fmt.Fprintf(&sb, "# DevPathPro activation of %s; run \"deactivate\" to restore the previous environment\n", name)
+		fmt.Fprintf(&sb, "if set -q %s; and functions -q deactivate; deactivate; end\n", ActiveVariable)
+		for _, n := range changed {
+			fmt.Fprintf(&sb, "set -e %s; if set -q %s; set -g %s $%s; end\n", oldName(n), n, oldName(n), n)
+		}
+		for _, n := range names {
+			fmt.Fprintf(&sb, "set -gx %s %s\n", n, fishQuote(vars[n]))
+		}
+		if len(env.Path) > 0 {
+			fmt.Fprintf(&sb, "set -gx PATH %s $PATH\n", joinQuoted(env.Path, " ", fishQuote))
+		}
+		sb.WriteString("function deactivate\n")
+		for _, n := range changed {
+			fmt.Fprintf(&sb, "    if set -q %s; set -gx %s $%s; set -e %s; else; set -e %s; end\n", oldName(n), n, oldName(n), oldName(n), n)
+		}
+		fmt.Fprintf(&sb, "    set -e %s\n    functions -e deactivate\nend\n", ActiveVariable)
+	case KindPowerShell:
+		fmt.Fprintf(&sb, "# DevPathPro activation of %s; run \"deactivate\" to restore the previous environment\n", name)
+		fmt.Fprintf(&sb, "if ($env:%s -and (Test-Path Function:deactivate)) { deactivate }\n", ActiveVariable)
+		sb.WriteString("$global:_DevPathProOld = @{}\n")
+		fmt.Fprintf(&sb, "foreach ($name in %s) { $global:_DevPathProOld[$name] = [Environment]::GetEnvironmentVariable($name) }\n",
+			joinQuoted(changed, ", ", psQuote))
+		for _, n := range names {
+			fmt.Fprintf(&sb, "$env:%s = %s\n", n, psQuote(vars[n]))
+		}
+		if len(env.Path) > 0 {
+			fmt.Fprintf(&sb, "$env:PATH = %s + [IO.Path]::PathSeparator + $env:PATH\n",
+				joinQuoted(env.Path, " + [IO.Path]::PathSeparator + ", psQuote))
+		}
+		sb.WriteString("function global:deactivate {\n")
+		sb.WriteString("    foreach ($name in $global:_DevPathProOld.Keys) { [Environment]::SetEnvironmentVariable($name, $global:_DevPathProOld[$name]) }\n")
+		fmt.Fprintf(&sb, "    Remove-Item Env:%s -ErrorAction SilentlyContinue\n", ActiveVariable)
+		sb.WriteString("    Remove-Variable _DevPathProOld -Scope Global\n")
+		sb.WriteString("    Remove-Item Function:deactivate\n}\n")
+	case KindCmd:
+		sb.WriteString("@echo off\n")
+		fmt.Fprintf(&sb, "rem DevPathPro activation of %s; run \"deactivate\" to restore the previous environment\n", cmdEscape(name))
+		fmt.Fprintf(&sb, "if defined %s (\n", ActiveVariable)
+		fmt.Fprintf(&sb, "    echo DevPathPro environment %%%s%% is already active, run deactivate first 1>&2\n", ActiveVariable)
+		sb.WriteString("    exit /b 1\n)\n")
+		for _, n := range changed {
+			fmt.Fprintf(&sb, "set \"%s=%%%s%%\"\n", oldName(n), n)
+		}
+		for _, n := range names {
+			fmt.Fprintf(&sb, "set \"%s=%s\"\n", n, cmdEscape(vars[n]))
+		}
+		if len(env.Path) > 0 {
+			fmt.Fprintf(&sb, "set \"PATH=%s;%%PATH%%\"\n", joinQuoted(env.Path, ";", cmdEscape))
+		}
+		var restore []string
+		for _, n := range changed {
+			restore = append(restore,
+				fmt.Sprintf("(if defined %s (set \"%s=%%%%%s%%%%\") else (set \"%s=\"))", oldName(n), n, oldName(n), n),
+				fmt.Sprintf("set \"%s=\"", oldName(n)))
+		}
+		restore = append(restore, fmt.Sprintf("set \"%s=\"", ActiveVariable), "doskey deactivate=")
+		fmt.Fprintf(&sb, "doskey deactivate=%s\n", strings.Join(restore, " $T "))
+	default:
+		return "", fmt.Errorf("unknown shell %q", kind)
+	}
+	return sb.String(), nil
+}
+
+// oldName is the variable an activation script keeps the previous value of name in
+func oldName(name string) string {
+	return "_DEVPATHPRO_OLD_" + name
+}
+
+// joinQuoted quotes every value and joins them with sep
+func joinQuoted(values []string, sep string, quote func(string) string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = quote(v)
+	}
+	return strings.Join(quoted, sep)
+}
+
+// shQuote puts a value in POSIX single quotes, where nothing is expanded
+func shQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+// fishQuote puts a value in fish single quotes
+func fishQuote(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(value) + "'"
+}
+
+// cmdEscape doubles the characters a batch file would otherwise expand:
+// % for variables and $ for the doskey macro
+func cmdEscape(value string) string {
+	return strings.NewReplacer("%", "%%", "$", "$$").Replace(value)
+}
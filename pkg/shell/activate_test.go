@@ -0,0 +1,68 @@
+package shell
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseActivationKind(t *testing.T) {
+	tests := map[string]Kind{"bash": KindSh, "zsh": KindSh, "sh": KindSh, "fish": KindFish, "pwsh": KindPowerShell, "PowerShell": KindPowerShell, "cmd": KindCmd}
+	for name, want := range tests {
+		if got, err := ParseActivationKind(name); err != nil || got != want {
+			t.Errorf("ParseActivationKind(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseActivationKind("environment.d"); err == nil {
+		t.Error("ParseActivationKind accepted environment.d")
+	}
+}
+
+func TestActivate(t *testing.T) {
+	env := Env{
+		Vars: map[string]string{"JAVA_HOME": "/opt/jdk's"},
+		Path: []string{"/opt/jdk/bin", "/go/bin"},
+	}
+	tests := []struct {
+		kind Kind
+		want []string
+	}{
+		{KindSh, []string{
+			`export DEVPATHPRO_ACTIVE='team'`,
+			`export JAVA_HOME='/opt/jdk'\''s'`,
+			`export PATH='/opt/jdk/bin':'/go/bin'"${PATH:+:$PATH}"`,
+			"deactivate() {",
+		}},
+		{KindFish, []string{
+			`set -gx JAVA_HOME '/opt/jdk\'s'`,
+			`set -gx PATH '/opt/jdk/bin' '/go/bin' $PATH`,
+			"function deactivate",
+		}},
+		{KindPowerShell, []string{
+			`$env:JAVA_HOME = '/opt/jdk''s'`,
+			`$env:PATH = '/opt/jdk/bin' + [IO.Path]::PathSeparator + '/go/bin' + [IO.Path]::PathSeparator + $env:PATH`,
+			"function global:deactivate {",
+		}},
+		{KindCmd, []string{
+			`set "JAVA_HOME=/opt/jdk's"`,
+			`set "PATH=/opt/jdk/bin;/go/bin;%PATH%"`,
+			"doskey deactivate=",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.kind), func(t *testing.T) {
+			got, err := Activate(tt.kind, "team", env)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("script does not contain %s:\n%s", want, got)
+				}
+			}
+		})
+	}
+
+	if _, err := Activate(KindEnvironmentD, "team", env); err == nil {
+		t.Error("Activate accepted environment.d")
+	}
+}
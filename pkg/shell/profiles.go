@@ -0,0 +1,75 @@
+package shell
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Shells are the shells whose startup files WindowsProfiles knows
+var Shells = []string{"powershell", "bash", "zsh", "fish"}
+
+// UserProfiles returns the startup files of the user's shells. ~/.profile is
+// always included; the others only when the shell is set up, i.e. ~/.bashrc
+// or ~/.zshrc exists, or the fish or environment.d config directory does.
+func UserProfiles(home string) []Profile {
+	profiles := []Profile{{Path: filepath.Join(home, ".profile"), Kind: KindSh}}
+	for _, name := range []string{".bashrc", ".zshrc"} {
+		if exists(filepath.Join(home, name)) {
+			profiles = append(profiles, Profile{Path: filepath.Join(home, name), Kind: KindSh})
+		}
+	}
+	if dir := filepath.Join(home, ".config", "fish"); exists(dir) {
+		profiles = append(profiles, Profile{Path: filepath.Join(dir, "conf.d", "devpathpro.fish"), Kind: KindFish, Owned: true})
+	}
+	if dir := filepath.Join(home, ".config", "environment.d"); exists(dir) {
+		profiles = append(profiles, Profile{Path: filepath.Join(dir, "devpathpro.conf"), Kind: KindEnvironmentD, Owned: true})
+	}
+	return profiles
+}
+
+// SystemProfiles returns the startup files read by every user's shells
+func SystemProfiles() []Profile {
+	profiles := []Profile{{Path: "/etc/profile.d/devpathpro.sh", Kind: KindSh, Owned: true}}
+	if exists("/etc/fish") {
+		profiles = append(profiles, Profile{Path: "/etc/fish/conf.d/devpathpro.fish", Kind: KindFish, Owned: true})
+	}
+	if exists("/etc/environment.d") {
+		profiles = append(profiles, Profile{Path: "/etc/environment.d/devpathpro.conf", Kind: KindEnvironmentD, Owned: true})
+	}
+	return profiles
+}
+
+// WindowsProfiles returns the startup files of the given shells on Windows:
+// the PowerShell profile in Documents, and ~/.bashrc, ~/.zshrc and the fish
+// configuration of Git Bash, which see PATH entries as /c/... paths.
+// Windows PowerShell's profile is always included, PowerShell 7's only if
+// its Documents\PowerShell folder exists.
+func WindowsProfiles(home string, shells []string) ([]Profile, error) {
+	var profiles []Profile
+	for _, name := range shells {
+		switch strings.ToLower(name) {
+		case "powershell":
+			if dir := filepath.Join(home, "Documents", "PowerShell"); exists(dir) {
+				profiles = append(profiles, Profile{Path: filepath.Join(dir, "Microsoft.PowerShell_profile.ps1"), Kind: KindPowerShell})
+			}
+			profiles = append(profiles, Profile{Path: filepath.Join(home, "Documents", "WindowsPowerShell", "Microsoft.PowerShell_profile.ps1"), Kind: KindPowerShell})
+		case "bash":
+			profiles = append(profiles, Profile{Path: filepath.Join(home, ".bashrc"), Kind: KindSh, Style: StyleMSYS})
+		case "zsh":
+			profiles = append(profiles, Profile{Path: filepath.Join(home, ".zshrc"), Kind: KindSh, Style: StyleMSYS})
+		case "fish":
+			profiles = append(profiles, Profile{Path: filepath.Join(home, ".config", "fish", "conf.d", "devpathpro.fish"), Kind: KindFish, Style: StyleMSYS, Owned: true})
+		default:
+			return nil, fmt.Errorf("unknown shell %q: expected %s", name, strings.Join(Shells, ", "))
+		}
+	}
+	return profiles, nil
+}
+
+// exists reports whether a file or directory exists
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,307 @@
+// Package shell writes environment variables into shell startup files as a
+// clearly delimited block that DevPathPro owns and regenerates.
+package shell
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Markers delimiting the managed block in a startup file
+const (
+	BeginMarker = "# >>> devpathpro >>>"
+	EndMarker   = "# <<< devpathpro <<<"
+)
+
+// Kind is the syntax a startup file is written in
+type Kind string
+
+const (
+	// KindSh is POSIX sh, read by sh, bash and zsh
+	KindSh Kind = "sh"
+	// KindFish is the fish shell
+	KindFish Kind = "fish"
+	// KindEnvironmentD is a systemd environment.d file, read at login by the session manager
+	KindEnvironmentD Kind = "environment.d"
+	// KindPowerShell is Windows PowerShell and PowerShell 7
+	KindPowerShell Kind = "powershell"
+	// KindCmd is the Windows command prompt; it only runs activation scripts
+	KindCmd Kind = "cmd"
+)
+
+// ParseKind converts a shell name to a Kind
+func ParseKind(s string) (Kind, error) {
+	switch kind := Kind(strings.ToLower(s)); kind {
+	case KindSh, KindFish, KindEnvironmentD, KindPowerShell:
+		return kind, nil
+	}
+	return "", fmt.Errorf("invalid shell %q: expected sh, fish, environment.d or powershell", s)
+}
+
+// PathStyle is how a shell running on Windows writes Windows paths
+type PathStyle string
+
+const (
+	// StyleNative keeps paths as they are
+	StyleNative PathStyle = ""
+	// StyleMSYS writes C:\Tools as /c/Tools, for Git Bash and MSYS2
+	StyleMSYS PathStyle = "msys"
+	// StyleWSL writes C:\Tools as /mnt/c/Tools, for shells inside WSL
+	StyleWSL PathStyle = "wsl"
+)
+
+// ParsePathStyle converts a style name to a PathStyle
+func ParsePathStyle(s string) (PathStyle, error) {
+	switch style := PathStyle(strings.ToLower(s)); style {
+	case StyleNative, StyleMSYS, StyleWSL:
+		return style, nil
+	}
+	return "", fmt.Errorf("invalid path style %q: expected msys or wsl", s)
+}
+
+// Profile is a startup file that gets the managed block
+type Profile struct {
+	Path string `json:"path"`
+	Kind Kind   `json:"shell"`
+	// Style converts the Windows paths in the block for Unix-like shells on Windows
+	Style PathStyle `json:"pathStyle,omitempty"`
+	// Owned is set for files that only hold the managed block, like fish
+	// conf.d snippets; they are deleted when the block is removed
+	Owned bool `json:"owned,omitempty"`
+}
+
+// Render returns the managed block for the profile, its paths converted to its style
+func (p Profile) Render(env Env) (string, error) {
+	return Render(p.Kind, env.Convert(p.Style))
+}
+
+// Convert rewrites Windows paths for a style: PATH entries always, and
+// under WSL also variables holding a Windows path
+func (e Env) Convert(style PathStyle) Env {
+	if style == StyleNative {
+		return e
+	}
+	converted := Env{Vars: make(map[string]string, len(e.Vars))}
+	for name, value := range e.Vars {
+		if style == StyleWSL {
+			value = ConvertPath(style, value)
+		}
+		converted.Vars[name] = value
+	}
+	for _, entry := range e.Path {
+		converted.Path = append(converted.Path, ConvertPath(style, entry))
+	}
+	return converted
+}
+
+// ConvertPath writes an absolute Windows path like C:\Tools\bin in the given
+// style. Other values are returned unchanged.
+func ConvertPath(style PathStyle, path string) string {
+	if style == StyleNative || len(path) < 3 || path[1] != ':' || (path[2] != '\\' && path[2] != '/') {
+		return path
+	}
+	drive := strings.ToLower(path[:1])
+	if drive < "a" || drive > "z" {
+		return path
+	}
+	rest := strings.TrimRight(strings.ReplaceAll(path[2:], `\`, "/"), "/")
+	if style == StyleWSL {
+		return "/mnt/" + drive + rest
+	}
+	return "/" + drive + rest
+}
+
+// Env is what a block sets
+type Env struct {
+	// Vars are exported with their values; $NAME references are expanded by the shell
+	Vars map[string]string
+	// Path are entries put in front of the inherited PATH in this order, so
+	// the configured tools win over the ones installed with the system
+	Path []string
+}
+
+// Empty reports whether the block would set nothing
+func (e Env) Empty() bool {
+	return len(e.Vars) == 0 && len(e.Path) == 0
+}
+
+// Render returns the managed block for a kind of startup file, markers included.
+// Variables are sorted by name so the block only changes when the values do.
+func Render(kind Kind, env Env) (string, error) {
+	names := make([]string, 0, len(env.Vars))
+	for name := range env.Vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString(BeginMarker + "\n")
+	sb.WriteString("# Managed by DevPathPro; changes inside this block are overwritten.\n")
+	for _, name := range names {
+		value := env.Vars[name]
+		switch kind {
+		case KindSh:
+			fmt.Fprintf(&sb, "export %s=%s\n", name, quote(value))
+		case KindFish:
+			fmt.Fprintf(&sb, "set -gx %s %s\n", name, quote(value))
+		case KindEnvironmentD:
+			fmt.Fprintf(&sb, "%s=%s\n", name, value)
+		case KindPowerShell:
+			fmt.Fprintf(&sb, "$env:%s = %s\n", name, psQuote(value))
+		default:
+			return "", fmt.Errorf("unknown shell %q", kind)
+		}
+	}
+	if len(env.Path) > 0 {
+		switch kind {
+		case KindSh:
+			fmt.Fprintf(&sb, "export PATH=\"%s${PATH:+:$PATH}\"\n", joinQuoted(env.Path, ":", escape))
+		case KindFish:
+			fmt.Fprintf(&sb, "set -gx PATH %s $PATH\n", joinQuoted(env.Path, " ", quote))
+		case KindEnvironmentD:
+			fmt.Fprintf(&sb, "PATH=%s${PATH:+:$PATH}\n", strings.Join(env.Path, ":"))
+		case KindPowerShell:
+			fmt.Fprintf(&sb, "$env:PATH = %s + [IO.Path]::PathSeparator + $env:PATH\n",
+				joinQuoted(env.Path, " + [IO.Path]::PathSeparator + ", psQuote))
+		default:
+			return "", fmt.Errorf("unknown shell %q", kind)
+		}
+	}
+	sb.WriteString(EndMarker + "\n")
+	return sb.String(), nil
+}
+
+// quote puts a value in double quotes, keeping $NAME references expandable
+func quote(value string) string {
+	return `"` + escape(value) + `"`
+}
+
+// escape escapes the characters that are special inside double quotes, except $
+func escape(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "`", "\\`").Replace(value)
+}
+
+// psQuote puts a value in PowerShell single quotes, where nothing is expanded
+func psQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+// ReadBlock returns the managed block of a file, markers included, and
+// whether the file has one. A missing file has no block.
+func ReadBlock(path string) (string, bool, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("error reading %s: %v", path, err)
+	}
+	start, end, ok := findBlock(string(data))
+	if !ok {
+		return "", false, nil
+	}
+	return string(data)[start:end], true, nil
+}
+
+// WriteBlock puts block into a file, replacing the managed block it already
+// has or appending one. The file and its directory are created if needed.
+// Nothing is written if the file already holds the same block; the result
+// reports whether the file changed.
+func WriteBlock(path, block string) (bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("error reading %s: %v", path, err)
+	}
+	content := string(data)
+
+	var updated string
+	if start, end, ok := findBlock(content); ok {
+		if content[start:end] == block {
+			return false, nil
+		}
+		updated = content[:start] + block + content[end:]
+	} else {
+		updated = content
+		if updated != "" && !strings.HasSuffix(updated, "\n") {
+			updated += "\n"
+		}
+		if updated != "" {
+			updated += "\n"
+		}
+		updated += block
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return false, fmt.Errorf("error creating %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(updated), fileMode(path)); err != nil {
+		return false, fmt.Errorf("error writing %s: %v", path, err)
+	}
+	return true, nil
+}
+
+// RemoveBlock removes the managed block from a file. Owned files are deleted
+// once they hold nothing else. The result reports whether the file changed.
+func RemoveBlock(p Profile) (bool, error) {
+	data, err := os.ReadFile(p.Path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("error reading %s: %v", p.Path, err)
+	}
+	content := string(data)
+	start, end, ok := findBlock(content)
+	if !ok {
+		return false, nil
+	}
+
+	// Drop the blank line WriteBlock put before the block
+	before := strings.TrimSuffix(content[:start], "\n")
+	updated := before + content[end:]
+	if before != "" && !strings.HasSuffix(updated, "\n") {
+		updated += "\n"
+	}
+	if p.Owned && strings.TrimSpace(updated) == "" {
+		if err := os.Remove(p.Path); err != nil {
+			return false, fmt.Errorf("error removing %s: %v", p.Path, err)
+		}
+		return true, nil
+	}
+	if err := os.WriteFile(p.Path, []byte(updated), fileMode(p.Path)); err != nil {
+		return false, fmt.Errorf("error writing %s: %v", p.Path, err)
+	}
+	return true, nil
+}
+
+// findBlock returns the byte range of the managed block, including the end
+// marker line and its newline
+func findBlock(content string) (int, int, bool) {
+	start := strings.Index(content, BeginMarker)
+	if start < 0 || (start > 0 && content[start-1] != '\n') {
+		return 0, 0, false
+	}
+	rel := strings.Index(content[start:], EndMarker)
+	if rel < 0 {
+		return 0, 0, false
+	}
+	end := start + rel + len(EndMarker)
+	if end < len(content) && content[end] == '\r' {
+		end++
+	}
+	if end < len(content) && content[end] == '\n' {
+		end++
+	}
+	return start, end, true
+}
+
+// fileMode keeps the permissions of an existing file
+func fileMode(path string) os.FileMode {
+	if info, err := os.Stat(path); err == nil {
+		return info.Mode().Perm()
+	}
+	return 0644
+}
@@ -0,0 +1,205 @@
+package shell
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestConvertPath(t *testing.T) {
+	tests := []struct {
+		style PathStyle
+		path  string
+		want  string
+	}{
+		{StyleNative, `C:\Tools\bin`, `C:\Tools\bin`},
+		{StyleMSYS, `C:\Tools\bin`, "/c/Tools/bin"},
+		{StyleMSYS, `D:/Go/bin/`, "/d/Go/bin"},
+		{StyleWSL, `C:\Program Files\Java`, "/mnt/c/Program Files/Java"},
+		{StyleWSL, `C:\`, "/mnt/c"},
+		{StyleMSYS, "/usr/bin", "/usr/bin"},
+		{StyleMSYS, "C:", "C:"},
+		{StyleMSYS, `1:\x`, `1:\x`},
+		{StyleWSL, "utf-8", "utf-8"},
+	}
+	for _, tt := range tests {
+		if got := ConvertPath(tt.style, tt.path); got != tt.want {
+			t.Errorf("ConvertPath(%q, %q) = %q, want %q", tt.style, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestEnvConvert(t *testing.T) {
+	env := Env{Vars: map[string]string{"JAVA_HOME": `C:\jdk`}, Path: []string{`C:\jdk\bin`}}
+	tests := []struct {
+		style    PathStyle
+		javaHome string
+		path     string
+	}{
+		{StyleNative, `C:\jdk`, `C:\jdk\bin`},
+		{StyleMSYS, `C:\jdk`, "/c/jdk/bin"},
+		{StyleWSL, "/mnt/c/jdk", "/mnt/c/jdk/bin"},
+	}
+	for _, tt := range tests {
+		got := env.Convert(tt.style)
+		if got.Vars["JAVA_HOME"] != tt.javaHome || len(got.Path) != 1 || got.Path[0] != tt.path {
+			t.Errorf("Convert(%q) = %+v, want JAVA_HOME=%s and PATH %s", tt.style, got, tt.javaHome, tt.path)
+		}
+	}
+}
+
+func TestParseKind(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Kind
+		wantErr bool
+	}{
+		{name: "sh", want: KindSh},
+		{name: "Fish", want: KindFish},
+		{name: "environment.d", want: KindEnvironmentD},
+		{name: "powershell", want: KindPowerShell},
+		{name: "cmd", wantErr: true},
+		{name: "bash", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseKind(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseKind(%q) = %q, %v; want %q, error: %v", tt.name, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	env := Env{
+		Vars: map[string]string{"JAVA_HOME": "/opt/jdk", "GOPATH": `$HOME/go "x"`},
+		Path: []string{"/opt/jdk/bin", "/go/bin"},
+	}
+	tests := []struct {
+		kind Kind
+		want []string
+	}{
+		{KindSh, []string{
+			`export GOPATH="$HOME/go \"x\""`,
+			`export JAVA_HOME="/opt/jdk"`,
+			`export PATH="/opt/jdk/bin:/go/bin${PATH:+:$PATH}"`,
+		}},
+		{KindFish, []string{
+			`set -gx GOPATH "$HOME/go \"x\""`,
+			`set -gx JAVA_HOME "/opt/jdk"`,
+			`set -gx PATH "/opt/jdk/bin" "/go/bin" $PATH`,
+		}},
+		{KindEnvironmentD, []string{
+			`GOPATH=$HOME/go "x"`,
+			`JAVA_HOME=/opt/jdk`,
+			`PATH=/opt/jdk/bin:/go/bin${PATH:+:$PATH}`,
+		}},
+		{KindPowerShell, []string{
+			`$env:GOPATH = '$HOME/go "x"'`,
+			`$env:JAVA_HOME = '/opt/jdk'`,
+			`$env:PATH = '/opt/jdk/bin' + [IO.Path]::PathSeparator + '/go/bin' + [IO.Path]::PathSeparator + $env:PATH`,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.kind), func(t *testing.T) {
+			got, err := Render(tt.kind, env)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+			if len(lines) != len(tt.want)+3 || lines[0] != BeginMarker || lines[len(lines)-1] != EndMarker {
+				t.Fatalf("Render(%q) =\n%s", tt.kind, got)
+			}
+			for i, want := range tt.want {
+				if lines[i+2] != want {
+					t.Errorf("line %d = %s\nwant       %s", i+3, lines[i+2], want)
+				}
+			}
+		})
+	}
+
+	if _, err := Render("tcsh", env); err == nil {
+		t.Error("Render accepted an unknown shell")
+	}
+}
+
+func TestWriteAndRemoveBlock(t *testing.T) {
+	block := func(value string) string {
+		b, err := Render(KindSh, Env{Vars: map[string]string{"GOPATH": value}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	tests := []struct {
+		name    string
+		initial string
+		owned   bool
+		// afterWrite is the content once the block is written, with BLOCK for the block
+		afterWrite string
+		// afterRemove is the content once the block is removed; "-" if the file is deleted
+		afterRemove string
+	}{
+		{"new file", "", false, "BLOCK", ""},
+		{"new owned file", "", true, "BLOCK", "-"},
+		{"existing content", "alias ll='ls -l'\n", false, "alias ll='ls -l'\n\nBLOCK", "alias ll='ls -l'\n"},
+		{"no final newline", "alias ll='ls -l'", false, "alias ll='ls -l'\n\nBLOCK", "alias ll='ls -l'\n"},
+		{"owned with other content", "set -x A 1\n", true, "set -x A 1\n\nBLOCK", "set -x A 1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "conf", "profile")
+			if tt.initial != "" {
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(tt.initial), 0600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			first := block("/go")
+			if changed, err := WriteBlock(path, first); err != nil || !changed {
+				t.Fatalf("WriteBlock() = %v, %v", changed, err)
+			}
+			if changed, err := WriteBlock(path, first); err != nil || changed {
+				t.Fatalf("WriteBlock() of the same block = %v, %v", changed, err)
+			}
+			second := block("/work/go")
+			if changed, err := WriteBlock(path, second); err != nil || !changed {
+				t.Fatalf("WriteBlock() of a new block = %v, %v", changed, err)
+			}
+			data, _ := os.ReadFile(path)
+			if want := strings.Replace(tt.afterWrite, "BLOCK", second, 1); string(data) != want {
+				t.Errorf("content after WriteBlock =\n%q\nwant\n%q", data, want)
+			}
+			if got, ok, err := ReadBlock(path); err != nil || !ok || got != second {
+				t.Errorf("ReadBlock() = %q, %v, %v", got, ok, err)
+			}
+			// Windows only reports whether a file is read-only
+			if tt.initial != "" && runtime.GOOS != "windows" {
+				if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+					t.Errorf("WriteBlock() did not keep the file mode: %v %v", info.Mode(), err)
+				}
+			}
+
+			if changed, err := RemoveBlock(Profile{Path: path, Owned: tt.owned}); err != nil || !changed {
+				t.Fatalf("RemoveBlock() = %v, %v", changed, err)
+			}
+			data, err := os.ReadFile(path)
+			if tt.afterRemove == "-" {
+				if !os.IsNotExist(err) {
+					t.Errorf("owned file was not deleted: %v", err)
+				}
+				return
+			}
+			if string(data) != tt.afterRemove {
+				t.Errorf("content after RemoveBlock = %q, want %q", data, tt.afterRemove)
+			}
+			if changed, err := RemoveBlock(Profile{Path: path, Owned: tt.owned}); err != nil || changed {
+				t.Errorf("second RemoveBlock() = %v, %v", changed, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package tools
+
+// systemDirs are the virtual file systems skipped by the deep search
+var systemDirs = []string{"/proc", "/sys", "/dev", "/run"}
+
+// GetAllDrives returns the roots the deep search walks. There are no drive
+// letters, so the configured deep search drives do not apply and the whole
+// file system is searched from its root.
+func GetAllDrives() []string {
+	return []string{"/"}
+}
+
+// driveRoot returns the directory a drive is searched from
+func driveRoot(drive string) string {
+	return drive
+}
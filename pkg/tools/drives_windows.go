@@ -0,0 +1,38 @@
+//go:build windows
+
+package tools
+
+import (
+	"os"
+	"strings"
+)
+
+// systemDirs are skipped by the deep search, matched by directory name
+var systemDirs = []string{"$Recycle.Bin", "$RECYCLE.BIN", "System Volume Information"}
+
+// GetAllDrives returns a list of available drives, limited to
+// the configured deep search drives if there are any
+func GetAllDrives() []string {
+	letters := "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	if len(searchOptions.Drives) > 0 {
+		letters = ""
+		for _, drive := range searchOptions.Drives {
+			letters += strings.ToUpper(strings.TrimSuffix(drive, ":"))
+		}
+	}
+
+	var drives []string
+	for _, drive := range letters {
+		drivePath := string(drive) + ":\\"
+		_, err := os.Stat(drivePath)
+		if err == nil {
+			drives = append(drives, string(drive))
+		}
+	}
+	return drives
+}
+
+// driveRoot returns the directory a drive letter is searched from
+func driveRoot(drive string) string {
+	return drive + ":\\"
+}
@@ -11,19 +11,47 @@ import (
 	"sync"
 
 	"devpathpro/pkg/config"
+	"devpathpro/pkg/registry"
+	"devpathpro/pkg/utils"
 )
 
+// SearchOptions tunes how programs are searched for
+type SearchOptions struct {
+	// ExcludedDirs are skipped while walking, matched by directory name or full path
+	ExcludedDirs []string
+	// Drives limits deep search to these drive letters; empty searches all drives
+	Drives []string
+}
+
+// searchOptions are the options used by FindProgram and the deep search
+var searchOptions SearchOptions
+
+// Configure sets the options used when searching for programs
+func Configure(opts SearchOptions) {
+	searchOptions = opts
+}
+
+// isExcluded reports whether a directory was excluded from searching
+func isExcluded(dir string) bool {
+	for _, excluded := range searchOptions.ExcludedDirs {
+		if strings.EqualFold(filepath.Base(dir), excluded) ||
+			strings.EqualFold(filepath.Clean(dir), filepath.Clean(excluded)) {
+			return true
+		}
+	}
+	return false
+}
+
 // FindProgram searches for a program in the system
 func FindProgram(prog config.Program) []string {
 	var results []string
 	var errors []error
 	var mutex sync.Mutex
 
+	executable := prog.Executable()
+
 	// First check common paths
-	for _, basePath := range prog.CommonPaths {
-		// Expand environment variables in path
-		basePath = os.ExpandEnv(basePath)
-		
+	for _, basePath := range commonPaths(prog) {
 		// Check path existence
 		if _, err := os.Stat(basePath); os.IsNotExist(err) {
 			continue
@@ -44,10 +72,13 @@ func FindProgram(prog config.Program) []string {
 					return filepath.SkipDir
 				}
 				
-				if !info.IsDir() && strings.EqualFold(filepath.Base(filePath), prog.ExecutableName) {
+				if info.IsDir() && isExcluded(filePath) {
+					return filepath.SkipDir
+				}
+				if !info.IsDir() && strings.EqualFold(filepath.Base(filePath), executable) {
 					mutex.Lock()
 					results = append(results, filePath)
-					fmt.Printf("Found file: %s\n", filePath)
+					utils.Debugf("found %s", filePath)
 					mutex.Unlock()
 				}
 				return nil
@@ -65,44 +96,52 @@ func FindProgram(prog config.Program) []string {
 		}
 	}
 
-	// Try using 'where' command
-	cmd := exec.Command("where", prog.ExecutableName)
-	output, err := cmd.Output()
-
-	if err == nil {
-		paths := strings.Split(strings.TrimSpace(string(output)), "\n")
-		for _, path := range paths {
-			path = strings.TrimSpace(path)
-			if path == "" {
-				continue
-			}
-			found := false
-			for _, existingPath := range results {
-				if strings.EqualFold(existingPath, path) {
-					found = true
-					break
-				}
-			}
-			if !found {
-				results = append(results, path)
+	// Then look the executable up through PATH
+	for _, path := range searchPath(executable) {
+		found := false
+		for _, existingPath := range results {
+			if strings.EqualFold(existingPath, path) {
+				found = true
+				break
 			}
 		}
+		if !found {
+			results = append(results, path)
+		}
 	}
 
 	return results
 }
 
-// GetAllDrives returns a list of available drives
-func GetAllDrives() []string {
-	var drives []string
-	for _, drive := range "ABCDEFGHIJKLMNOPQRSTUVWXYZ" {
-		drivePath := string(drive) + ":\\"
-		_, err := os.Stat(drivePath)
-		if err == nil {
-			drives = append(drives, string(drive))
+// commonPaths returns the existing common paths of a program with
+// environment variables expanded and wildcard patterns resolved
+func commonPaths(prog config.Program) []string {
+	var paths []string
+	for _, path := range prog.CommonPaths {
+		path = os.ExpandEnv(path)
+		if !strings.Contains(path, "*") {
+			paths = append(paths, path)
+			continue
+		}
+		if matches, err := filepath.Glob(path); err == nil {
+			paths = append(paths, matches...)
 		}
 	}
-	return drives
+	return paths
+}
+
+// searchPath returns every executable copy of executable in PATH order
+func searchPath(executable string) []string {
+	var paths []string
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		if dir == "" {
+			continue
+		}
+		if path, err := exec.LookPath(filepath.Join(dir, executable)); err == nil {
+			paths = append(paths, path)
+		}
+	}
+	return paths
 }
 
 // SearchInDrive searches for a program in a specific drive
@@ -110,12 +149,11 @@ func SearchInDrive(drive, executableName string, resultChan chan<- string) {
 	fmt.Printf("Searching on drive %s...\n", drive)
 	
 	// List of directories to skip
-	skipDirs := []string{
-		"Windows\\Temp", "Temp", "tmp", "cache", "Cache",
-		"$Recycle.Bin", "$RECYCLE.BIN", "System Volume Information",
-	}
+	skipDirs := append([]string{
+		"Temp", "tmp", "cache", "Cache",
+	}, systemDirs...)
 
-	root := drive + ":\\"
+	root := driveRoot(drive)
 	
 	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -128,10 +166,13 @@ func SearchInDrive(drive, executableName string, resultChan chan<- string) {
 		if info.IsDir() {
 			baseName := filepath.Base(path)
 			for _, skip := range skipDirs {
-				if strings.EqualFold(baseName, skip) {
+				if strings.EqualFold(baseName, skip) || path == skip {
 					return filepath.SkipDir
 				}
 			}
+			if isExcluded(path) {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
@@ -142,8 +183,10 @@ func SearchInDrive(drive, executableName string, resultChan chan<- string) {
 	})
 }
 
-// SelectPath asks user to choose a path when multiple installations are found
-func SelectPath(paths []string, programName string) (string, error) {
+// SelectPath chooses a path when multiple installations are found: by the program's
+// selection policy if it has one or stdin is not a terminal, otherwise by asking
+// the user. versions holds the version of each installation.
+func SelectPath(prog config.Program, paths []string, versions map[string]string) (string, error) {
 	if len(paths) == 0 {
 		return "", fmt.Errorf("no paths provided")
 	}
@@ -152,9 +195,18 @@ func SelectPath(paths []string, programName string) (string, error) {
 		return paths[0], nil
 	}
 
-	fmt.Printf("\nMultiple installations of %s found:\n", programName)
+	if prog.Selection != nil || !utils.IsTerminal(os.Stdin) {
+		selection, err := ChooseInstallation(prog, paths, versions)
+		if err != nil {
+			return "", err
+		}
+		fmt.Println(selection.Explain())
+		return selection.Path, nil
+	}
+
+	fmt.Printf("\nMultiple installations of %s found:\n", prog.Name)
 	for i, path := range paths {
-		fmt.Printf("[%d] %s\n", i+1, path)
+		fmt.Printf("[%d] %s\n", i+1, DescribePath(path, versions[path]))
 	}
 	fmt.Print("\nSelect path to use (enter number): ")
 
@@ -171,22 +223,25 @@ func SelectPath(paths []string, programName string) (string, error) {
 }
 
 // ConfigureSelectedPath configures the program with the selected path
-func ConfigureSelectedPath(prog config.Program, selectedPath string) error {
+// in the process scope of the store
+func ConfigureSelectedPath(store registry.EnvStore, prog config.Program, selectedPath string) error {
 	// Set environment variables if specified
 	if prog.EnvVar != "" {
-		if err := os.Setenv(prog.EnvVar, filepath.Dir(selectedPath)); err != nil {
+		if err := store.Set(registry.ScopeProcess, prog.EnvVar, filepath.Dir(selectedPath)); err != nil {
 			return fmt.Errorf("failed to set %s: %v", prog.EnvVar, err)
 		}
 	}
 
 	// Add to PATH if needed
-	pathVar := os.Getenv("PATH")
-	if !strings.Contains(pathVar, filepath.Dir(selectedPath)) {
-		newPath := pathVar + ";" + filepath.Dir(selectedPath)
-		if err := os.Setenv("PATH", newPath); err != nil {
+	pathVar, _, err := store.Get(registry.ScopeProcess, registry.PathVariable)
+	if err != nil {
+		return fmt.Errorf("failed to read PATH: %v", err)
+	}
+	if newPath, changed := registry.AppendPathEntry(pathVar, filepath.Dir(selectedPath)); changed {
+		if err := store.Set(registry.ScopeProcess, registry.PathVariable, newPath); err != nil {
 			return fmt.Errorf("failed to update PATH: %v", err)
 		}
 	}
 
 	return nil
-} 
\ No newline at end of file
+}
@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+
+	"devpathpro/pkg/backup"
+	"devpathpro/pkg/config"
+	"devpathpro/pkg/registry"
+)
+
+var update = flag.Bool("update", false, "rewrite the plan snapshots in testdata")
+
+// snapshotDir holds the expected environment of every built-in program.
+// Recipes differ between Windows and the other platforms, so each has its own.
+func snapshotDir() string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join("testdata", "plans", "windows")
+	}
+	return filepath.Join("testdata", "plans", "unix")
+}
+
+// snapshotName turns a program name such as ".NET Core" into a file name
+func snapshotName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+		return '-'
+	}, name)
+	return strings.Trim(name, "-") + ".golden"
+}
+
+// snapshotEnv points every variable the recipes read at directories under
+// root, so the snapshots do not depend on the machine running the tests
+func snapshotEnv(t *testing.T, root string) {
+	t.Helper()
+	for _, name := range []string{"HOME", "USERPROFILE"} {
+		t.Setenv(name, filepath.Join(root, "home"))
+	}
+	for _, name := range []string{"APPDATA", "XDG_CONFIG_HOME"} {
+		t.Setenv(name, filepath.Join(root, "config"))
+	}
+	t.Setenv("LOCALAPPDATA", filepath.Join(root, "local"))
+	for _, name := range []string{"TEMP", "TMP", "TMPDIR"} {
+		t.Setenv(name, filepath.Join(root, "tmp"))
+	}
+	t.Setenv("CLASSPATH", "")
+	backup.Configure(backup.Options{Dir: filepath.Join(root, "backups")})
+	t.Cleanup(func() { backup.Configure(backup.Options{}) })
+}
+
+// dumpStore lists the variables of every scope as "scope NAME=value" lines,
+// with root replaced by $ROOT
+func dumpStore(t *testing.T, store registry.EnvStore, root string) string {
+	t.Helper()
+	var lines []string
+	for _, scope := range []registry.Scope{registry.ScopeMachine, registry.ScopeUser} {
+		vars, err := store.List(scope)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, value := range vars {
+			lines = append(lines, string(scope)+" "+name+"="+strings.ReplaceAll(value, root, "$ROOT"))
+		}
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// TestBuildPlanSnapshots applies the plan of every built-in program to a
+// memory store and compares the result with its snapshot.
+// Run "go test ./pkg/tools -update" to record changed recipes.
+func TestBuildPlanSnapshots(t *testing.T) {
+	dir := snapshotDir()
+	if _, err := os.Stat(dir); err != nil && !*update {
+		t.Skipf("no snapshots recorded for %s; run with -update to record them", runtime.GOOS)
+	}
+	if *update {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, prog := range config.GetDefaultPrograms() {
+		prog := prog
+		t.Run(prog.Name, func(t *testing.T) {
+			root := t.TempDir()
+			snapshotEnv(t, root)
+			executable := filepath.Join(root, "tools", "bin", prog.Executable())
+
+			store := registry.NewMemoryStore()
+			if err := BuildPlan(ResolveScope(prog, ""), prog, executable, nil).Apply(store); err != nil {
+				t.Fatal(err)
+			}
+			got := dumpStore(t, store, root)
+
+			file := filepath.Join(dir, snapshotName(prog.Name))
+			if *update {
+				if err := os.WriteFile(file, []byte(got), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatalf("no snapshot for %s: %v", prog.Name, err)
+			}
+			if got != string(want) {
+				t.Errorf("environment of %s changed:\n--- got\n%s--- want (%s)\n%s", prog.Name, got, file, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package tools
+
+import (
+	"sort"
+
+	"devpathpro/pkg/config"
+	"devpathpro/pkg/plan"
+	"devpathpro/pkg/registry"
+	"devpathpro/pkg/utils"
+)
+
+// recipeOptions returns the option groups of a recipe with the variables that
+// apply on this platform. Every group is kept, so manifests and profiles
+// written on another platform still name known groups.
+func recipeOptions(recipe *config.Recipe) []ConfigOption {
+	options := make([]ConfigOption, len(recipe.Options))
+	for i, opt := range recipe.Options {
+		var vars []string
+		for _, name := range opt.Variables {
+			if recipe.Applies(name) {
+				vars = append(vars, name)
+			}
+		}
+		options[i] = ConfigOption{
+			Name:        opt.Name,
+			Description: opt.Description,
+			Variables:   vars,
+		}
+	}
+	return options
+}
+
+// applyRecipe adds the changes of a recipe for a program installed at path to the plan.
+// If selectedVars is not empty, only those variables are set; variables that do not
+// apply on this platform never are. Templates are validated
+// when the catalog is loaded, so one failing here is logged and skipped.
+func applyRecipe(p *plan.ChangePlan, scope registry.Scope, recipe *config.Recipe, path string, selectedVars []string) {
+	data, err := recipe.NewTemplateData(path)
+	if err != nil {
+		utils.Warnf("%v", err)
+		return
+	}
+	expand := func(field, text string) (string, bool) {
+		value, err := config.ExpandTemplate(text, data)
+		if err != nil {
+			utils.Warnf("skipping %s: %v", field, err)
+			return "", false
+		}
+		return value, true
+	}
+
+	if len(selectedVars) == 0 {
+		for name := range recipe.Variables {
+			selectedVars = append(selectedVars, name)
+		}
+		sort.Strings(selectedVars)
+	}
+	for _, name := range selectedVars {
+		text, exists := recipe.Variables[name]
+		if !exists || !recipe.Applies(name) {
+			continue
+		}
+		if value, ok := expand(name, text); ok {
+			p.SetVar(scope, name, value)
+		}
+	}
+
+	for _, dir := range recipe.CreateDirs {
+		if value, ok := expand("createDirs entry", dir); ok && value != "" {
+			p.CreateDir(value)
+		}
+	}
+	for _, dir := range recipe.Path {
+		if value, ok := expand("path entry", dir); ok && value != "" {
+			p.AppendPath(scope, value)
+		}
+	}
+}
@@ -0,0 +1,199 @@
+package tools
+
+import (
+	"debug/pe"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"devpathpro/pkg/config"
+	"devpathpro/pkg/version"
+)
+
+// Selection is the installation chosen by a selection policy, with the reasons
+type Selection struct {
+	Path    string
+	Version string
+	// Reasons explain why Path was chosen
+	Reasons []string
+	// Skipped explains why other installations were not
+	Skipped []string
+}
+
+// Explain returns the choice and its reasons as text
+func (s Selection) Explain() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Chose %s", DescribePath(s.Path, s.Version))
+	if len(s.Reasons) > 0 {
+		fmt.Fprintf(&b, ": %s", strings.Join(s.Reasons, "; "))
+	}
+	for _, skipped := range s.Skipped {
+		fmt.Fprintf(&b, "\n  skipped %s", skipped)
+	}
+	return b.String()
+}
+
+// candidate is an installation being ranked
+type candidate struct {
+	path    string
+	version string
+	parsed  *version.Version
+	order   int
+}
+
+// ChooseInstallation picks one of the installations of a program found at paths
+// according to its selection policy. Without a policy the first installation
+// found is chosen. versions holds the known version of each path.
+func ChooseInstallation(prog config.Program, paths []string, versions map[string]string) (Selection, error) {
+	if len(paths) == 0 {
+		return Selection{}, fmt.Errorf("no installations of %s found", prog.Name)
+	}
+	policy := prog.Selection
+	if policy == nil {
+		policy = &config.SelectionPolicy{}
+	}
+
+	var selection Selection
+	var candidates []candidate
+	var constraint *version.Constraint
+	if policy.Constraint != "" {
+		var err error
+		if constraint, err = version.ParseConstraint(policy.Constraint); err != nil {
+			return Selection{}, err
+		}
+	}
+	for i, path := range paths {
+		c := candidate{path: path, version: versions[path], order: i}
+		if v, err := version.Parse(c.version); err == nil {
+			c.parsed = &v
+		}
+		if constraint != nil {
+			if c.parsed == nil {
+				selection.Skipped = append(selection.Skipped, fmt.Sprintf("%s: version unknown, cannot check %s", path, constraint))
+				continue
+			}
+			if !constraint.Check(*c.parsed) {
+				selection.Skipped = append(selection.Skipped, fmt.Sprintf("%s: %s does not satisfy %s", path, c.version, constraint))
+				continue
+			}
+		}
+		candidates = append(candidates, c)
+	}
+	if len(candidates) == 0 {
+		return selection, fmt.Errorf("no installation of %s satisfies %s:\n  %s", prog.Name, constraint, strings.Join(selection.Skipped, "\n  "))
+	}
+
+	var lts *version.Constraint
+	if prog.Version != nil && prog.Version.LTS != "" {
+		lts, _ = version.ParseConstraint(prog.Version.LTS)
+	}
+
+	// Each preference ranks candidates before the next one is considered;
+	// candidates that tie on all of them stay in the order they were found
+	var less []func(a, b candidate) (bool, bool)
+	if policy.Root != "" {
+		less = append(less, preferTrue(func(c candidate) bool { return underRoot(c.path, policy.Root) }))
+	}
+	for _, prefer := range policy.Prefer {
+		switch prefer {
+		case config.PreferLTS:
+			less = append(less, preferTrue(func(c candidate) bool { return lts != nil && c.parsed != nil && lts.Check(*c.parsed) }))
+		case config.Prefer64Bit:
+			less = append(less, preferTrue(func(c candidate) bool { return is64Bit(c.path) }))
+		case config.PreferNewest:
+			less = append(less, func(a, b candidate) (bool, bool) {
+				if a.parsed == nil || b.parsed == nil {
+					// Known versions rank before unknown ones
+					return a.parsed != nil, (a.parsed == nil) != (b.parsed == nil)
+				}
+				cmp := a.parsed.Compare(*b.parsed)
+				return cmp > 0, cmp != 0
+			})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		for _, rule := range less {
+			if isLess, decided := rule(candidates[i], candidates[j]); decided {
+				return isLess
+			}
+		}
+		return false
+	})
+
+	chosen := candidates[0]
+	selection.Path = chosen.path
+	selection.Version = chosen.version
+	if constraint != nil {
+		selection.Reasons = append(selection.Reasons, fmt.Sprintf("satisfies %s", constraint))
+	}
+	if policy.Root != "" && underRoot(chosen.path, policy.Root) {
+		selection.Reasons = append(selection.Reasons, fmt.Sprintf("under %s", policy.Root))
+	}
+	for _, prefer := range policy.Prefer {
+		switch {
+		case prefer == config.PreferLTS && lts != nil && chosen.parsed != nil && lts.Check(*chosen.parsed):
+			selection.Reasons = append(selection.Reasons, "LTS release")
+		case prefer == config.Prefer64Bit && is64Bit(chosen.path):
+			selection.Reasons = append(selection.Reasons, "64-bit")
+		case prefer == config.PreferNewest && len(candidates) > 1 && isNewest(chosen, candidates):
+			selection.Reasons = append(selection.Reasons, "newest version")
+		}
+	}
+	if len(candidates) == 1 && len(paths) > 1 {
+		selection.Reasons = append(selection.Reasons, "only matching installation")
+	}
+	if len(selection.Reasons) == 0 {
+		selection.Reasons = append(selection.Reasons, "first installation found")
+	}
+	if len(less) > 0 {
+		for _, c := range candidates[1:] {
+			selection.Skipped = append(selection.Skipped, fmt.Sprintf("%s: ranked lower", DescribePath(c.path, c.version)))
+		}
+	}
+	return selection, nil
+}
+
+// isNewest reports whether no candidate has a higher version than c
+func isNewest(c candidate, candidates []candidate) bool {
+	if c.parsed == nil {
+		return false
+	}
+	for _, other := range candidates {
+		if other.parsed != nil && other.parsed.Compare(*c.parsed) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// preferTrue ranks candidates for which prefer holds before the others
+func preferTrue(prefer func(candidate) bool) func(a, b candidate) (bool, bool) {
+	return func(a, b candidate) (bool, bool) {
+		pa, pb := prefer(a), prefer(b)
+		return pa && !pb, pa != pb
+	}
+}
+
+// underRoot reports whether path is inside root
+func underRoot(path, root string) bool {
+	rel, err := filepath.Rel(strings.ToLower(filepath.Clean(root)), strings.ToLower(filepath.Clean(path)))
+	if err != nil {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// is64Bit reports whether an executable is a 64-bit PE file. Scripts such as
+// mvn.cmd are judged by their location outside "Program Files (x86)".
+func is64Bit(path string) bool {
+	if f, err := pe.Open(path); err == nil {
+		defer f.Close()
+		switch f.FileHeader.Machine {
+		case pe.IMAGE_FILE_MACHINE_AMD64, pe.IMAGE_FILE_MACHINE_ARM64:
+			return true
+		}
+		return false
+	}
+	return !strings.Contains(strings.ToLower(path), "(x86)")
+}
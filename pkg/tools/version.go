@@ -0,0 +1,153 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"devpathpro/pkg/config"
+	"devpathpro/pkg/utils"
+)
+
+// probeTimeout limits how long a version command may run
+const probeTimeout = 5 * time.Second
+
+// versionCache holds detected versions by executable path, since probing
+// runs a process and the same installation is listed in several places
+var versionCache sync.Map
+
+// DetectVersion returns the version of a program installed at path, or an
+// empty string if it cannot be determined. Results are cached per path.
+func DetectVersion(prog config.Program, path string) string {
+	if cached, ok := versionCache.Load(strings.ToLower(path)); ok {
+		return cached.(string)
+	}
+
+	version, err := probeVersion(prog, path)
+	if err != nil {
+		utils.Debugf("version of %s at %s: %v", prog.Name, path, err)
+	}
+	versionCache.Store(strings.ToLower(path), version)
+	return version
+}
+
+// DetectVersions returns the versions of several installations of a program by path,
+// probing them in parallel. Paths whose version is unknown are left out.
+func DetectVersions(prog config.Program, paths []string) map[string]string {
+	versions := make(map[string]string)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, path := range paths {
+		wg.Add(1)
+		go func(path string) {
+			defer wg.Done()
+			if version := DetectVersion(prog, path); version != "" {
+				mu.Lock()
+				versions[path] = version
+				mu.Unlock()
+			}
+		}(path)
+	}
+	wg.Wait()
+	return versions
+}
+
+// DescribePath returns path followed by its version, if known
+func DescribePath(path, version string) string {
+	if version == "" {
+		return path
+	}
+	return fmt.Sprintf("%s (%s)", path, version)
+}
+
+// probeVersion tries the program's version command, then its version files,
+// then the version resource of the executable
+func probeVersion(prog config.Program, path string) (string, error) {
+	var errs []string
+	if probe := prog.Version; probe != nil {
+		if len(probe.Args) > 0 {
+			version, err := commandVersion(path, probe.Args, probe.Pattern)
+			if err == nil {
+				return version, nil
+			}
+			errs = append(errs, err.Error())
+		}
+
+		data, err := prog.Recipe.NewTemplateData(path)
+		if err != nil {
+			return "", err
+		}
+		for _, f := range probe.Files {
+			version, err := fileVersion(f, data)
+			if err == nil {
+				return version, nil
+			}
+			errs = append(errs, err.Error())
+		}
+	}
+
+	version, err := executableVersion(path)
+	if err == nil {
+		return version, nil
+	}
+	errs = append(errs, err.Error())
+	return "", fmt.Errorf("%s", strings.Join(errs, "; "))
+}
+
+// commandVersion runs the executable with args and matches pattern against its output.
+// Many tools print their version to stderr, so both streams are read.
+func commandVersion(path string, args []string, pattern string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, path, args...).CombinedOutput()
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("%s %s timed out", path, strings.Join(args, " "))
+	}
+	if version := matchVersion(pattern, string(output)); version != "" {
+		return version, nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s %s: %v", path, strings.Join(args, " "), err)
+	}
+	return "", fmt.Errorf("no version in output of %s %s", path, strings.Join(args, " "))
+}
+
+// fileVersion reads a version file of an installation
+func fileVersion(f config.VersionFile, data config.TemplateData) (string, error) {
+	path, err := config.ExpandTemplate(f.Path, data)
+	if err != nil {
+		return "", err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	if version := matchVersion(f.Pattern, string(content)); version != "" {
+		return version, nil
+	}
+	return "", fmt.Errorf("no version in %s", path)
+}
+
+// matchVersion returns the first non-empty capture group of pattern in text
+func matchVersion(pattern, text string) string {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return ""
+	}
+	match := re.FindStringSubmatch(text)
+	if match == nil {
+		return ""
+	}
+	for _, group := range match[1:] {
+		if group != "" {
+			return group
+		}
+	}
+	return ""
+}
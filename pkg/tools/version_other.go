@@ -0,0 +1,10 @@
+//go:build !windows
+
+package tools
+
+import "fmt"
+
+// executableVersion is only available for Windows PE files
+func executableVersion(path string) (string, error) {
+	return "", fmt.Errorf("no version resource support on this platform")
+}
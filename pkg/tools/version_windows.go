@@ -0,0 +1,34 @@
+//go:build windows
+
+package tools
+
+import (
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// executableVersion reads the file version from the version resource of a PE file
+func executableVersion(path string) (string, error) {
+	size, err := windows.GetFileVersionInfoSize(path, nil)
+	if err != nil {
+		return "", fmt.Errorf("no version resource in %s: %v", path, err)
+	}
+	info := make([]byte, size)
+	if err := windows.GetFileVersionInfo(path, 0, size, unsafe.Pointer(&info[0])); err != nil {
+		return "", fmt.Errorf("failed to read version resource of %s: %v", path, err)
+	}
+
+	var fixed *windows.VS_FIXEDFILEINFO
+	var length uint32
+	if err := windows.VerQueryValue(unsafe.Pointer(&info[0]), `\`, unsafe.Pointer(&fixed), &length); err != nil {
+		return "", fmt.Errorf("failed to read version resource of %s: %v", path, err)
+	}
+	if fixed == nil || length == 0 {
+		return "", fmt.Errorf("no version resource in %s", path)
+	}
+	return fmt.Sprintf("%d.%d.%d.%d",
+		fixed.FileVersionMS>>16, fixed.FileVersionMS&0xffff,
+		fixed.FileVersionLS>>16, fixed.FileVersionLS&0xffff), nil
+}
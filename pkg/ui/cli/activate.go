@@ -0,0 +1,144 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"devpathpro/pkg/config"
+	"devpathpro/pkg/manifest"
+	"devpathpro/pkg/plan"
+	"devpathpro/pkg/profile"
+	"devpathpro/pkg/registry"
+	"devpathpro/pkg/shell"
+)
+
+const activateUsage = "usage: devpathpro activate [-shell powershell|cmd|bash|zsh|fish] [-path-style msys|wsl] [-o FILE] [profile|manifest]"
+
+// runActivate prints a script that configures the current terminal for the
+// tools of a saved profile or a project manifest, without touching the user
+// or machine variables
+func runActivate(cfg *config.Configuration, store registry.EnvStore, args []string) int {
+	defaultShell := "bash"
+	if runtime.GOOS == "windows" {
+		defaultShell = "powershell"
+	}
+	fs := flag.NewFlagSet("activate", flag.ContinueOnError)
+	shellFlag := fs.String("shell", defaultShell, "Shell the script is written for: powershell, cmd, bash, zsh or fish")
+	styleFlag := fs.String("path-style", "", "How bash, zsh and fish on Windows write paths: msys or wsl (default: msys)")
+	output := fs.String("o", "", "Write the script to this file instead of standard output")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	if len(positional) > 1 {
+		fmt.Fprintln(os.Stderr, activateUsage)
+		return exitUsage
+	}
+	kind, err := shell.ParseActivationKind(*shellFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	style, err := shell.ParsePathStyle(*styleFlag)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	if style == shell.StyleNative && runtime.GOOS == "windows" && (kind == shell.KindSh || kind == shell.KindFish) {
+		style = shell.StyleMSYS
+	}
+
+	name, plans, code := activationPlans(cfg, store, positional)
+	if code == exitError {
+		return code
+	}
+	script, err := shell.Activate(kind, name, activationEnv(plans).Convert(style))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+	if *output == "" {
+		fmt.Print(script)
+		return code
+	}
+	if err := os.WriteFile(*output, []byte(script), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "error writing %s: %v\n", *output, err)
+		return exitError
+	}
+	fmt.Fprintf(os.Stderr, "✅ Wrote %s\n", *output)
+	return code
+}
+
+// activationPlans returns the name of what is activated and the plans of its
+// tools in the process scope: the saved profile named by args, else the
+// manifest file args gives or the one found from the current directory. A
+// missing tool of a manifest is reported and gives exitUnmet; exitError
+// means nothing can be activated.
+func activationPlans(cfg *config.Configuration, store registry.EnvStore, args []string) (string, []*plan.ChangePlan, int) {
+	if len(args) == 1 {
+		if p, ok := config.FindProfile(cfg.Profiles(), args[0]); ok {
+			if _, err := os.Stat(args[0]); err != nil {
+				profilePlan, err := profile.BuildPlan(cfg.Programs, p, registry.ScopeProcess)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+					return "", nil, exitError
+				}
+				return p.Name, []*plan.ChangePlan{profilePlan}, exitOK
+			}
+		}
+	}
+
+	var path string
+	var err error
+	if len(args) == 1 {
+		path = args[0]
+	} else if path, err = manifest.Find("."); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return "", nil, exitError
+	}
+	m, err := manifest.Load(path, cfg.Programs)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return "", nil, exitError
+	}
+	results, err := m.Check(store, cfg.Programs, registry.ScopeProcess)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return "", nil, exitError
+	}
+
+	code := exitOK
+	var plans []*plan.ChangePlan
+	for _, result := range results {
+		if !result.Met() {
+			fmt.Fprintf(os.Stderr, "⚠️ %s: %s\n", result.Tool, result.Message)
+			code = exitUnmet
+			continue
+		}
+		plans = append(plans, result.Plan)
+	}
+	return filepath.Base(filepath.Dir(m.Path)), plans, code
+}
+
+// activationEnv collects what the plans would write into an environment for
+// an activation script: the variables and the PATH entries in plan order.
+// Directories are not created since nothing global is changed.
+func activationEnv(plans []*plan.ChangePlan) shell.Env {
+	env := shell.Env{Vars: make(map[string]string)}
+	for _, p := range plans {
+		for _, op := range p.Operations {
+			switch op.Type {
+			case plan.OpSetVar:
+				env.Vars[op.Name] = op.Value
+			case plan.OpAppendPath, plan.OpPrependPath:
+				if !registry.ContainsPath(env.Path, op.Value) {
+					env.Path = append(env.Path, op.Value)
+				}
+			}
+		}
+	}
+	return env
+}
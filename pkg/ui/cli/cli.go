@@ -9,16 +9,24 @@ import (
 	"strings"
 
 	"devpathpro/pkg/config"
+	"devpathpro/pkg/plan"
+	"devpathpro/pkg/registry"
 	"devpathpro/pkg/tools"
 )
 
 type CLI struct {
 	config *config.Configuration
+	store  registry.EnvStore
+	scope  registry.Scope
 }
 
-func NewCLI(cfg *config.Configuration) *CLI {
+// NewCLI creates the interactive CLI. An empty scope configures
+// every tool in its default scope.
+func NewCLI(cfg *config.Configuration, store registry.EnvStore, scope registry.Scope) *CLI {
 	return &CLI{
 		config: cfg,
+		store:  store,
+		scope:  scope,
 	}
 }
 
@@ -35,7 +43,11 @@ func (c *CLI) Run() {
 		fmt.Print("\nSelect an option: ")
 
 		reader := bufio.NewReader(os.Stdin)
-		input, _ := reader.ReadString('\n')
+		input, err := reader.ReadString('\n')
+		if err != nil && input == "" {
+			// stdin was closed
+			return
+		}
 		choice, err := strconv.Atoi(strings.TrimSpace(input))
 		if err != nil {
 			fmt.Println("Invalid input. Please enter a number.")
@@ -70,34 +82,43 @@ func (c *CLI) searchAndConfigureTools() {
 			continue
 		}
 
+		versions := tools.DetectVersions(prog, paths)
 		fmt.Printf("✅ %s found in:\n", prog.Name)
 		for i, path := range paths {
-			fmt.Printf("  %d. %s\n", i+1, path)
+			fmt.Printf("  %d. %s\n", i+1, tools.DescribePath(path, versions[path]))
 		}
 
 		// Выбор пути установки
-		selectedPath, err := tools.SelectPath(paths, prog.Name)
+		selectedPath, err := tools.SelectPath(prog, paths, versions)
 		if err != nil {
 			fmt.Printf("Error selecting path: %v\n", err)
 			continue
 		}
 
 		// Получение доступных опций конфигурации
+		var selectedVars []string
 		options := tools.GetConfigOptions(prog)
 		if len(options) > 0 {
+			defaults := c.config.Settings.DefaultOptionsFor(prog.Name)
 			fmt.Printf("\nConfiguration options for %s:\n", prog.Name)
 			fmt.Println("0. All (recommended)")
 			for i, opt := range options {
 				fmt.Printf("%d. %s - %s\n", i+1, opt.Name, opt.Description)
 			}
-			
+			if len(defaults) > 0 {
+				fmt.Printf("Press Enter for your defaults: %s\n", strings.Join(defaults, ", "))
+			}
+
 			fmt.Print("\nSelect options (comma-separated numbers, e.g., 1,3): ")
 			reader := bufio.NewReader(os.Stdin)
 			input, _ := reader.ReadString('\n')
 			input = strings.TrimSpace(input)
 
-			var selectedVars []string
-			if input != "" && input != "0" {
+			if input == "" && len(defaults) > 0 {
+				if selectedVars, err = tools.OptionVariables(prog, defaults); err != nil {
+					fmt.Printf("Ignoring default options: %v\n", err)
+				}
+			} else if input != "" && input != "0" {
 				numbers := strings.Split(input, ",")
 				for _, num := range numbers {
 					if idx, err := strconv.Atoi(strings.TrimSpace(num)); err == nil {
@@ -107,18 +128,35 @@ func (c *CLI) searchAndConfigureTools() {
 					}
 				}
 			}
+		}
 
-			if err := tools.ConfigureSelectedPath(prog, selectedPath); err != nil {
-				fmt.Printf("❌ Error configuring %s: %v\n", prog.Name, err)
-			} else {
-				fmt.Printf("✅ %s configured successfully\n", prog.Name)
-			}
+		// Machine scope writes need elevation, user scope writes do not
+		scope := tools.ResolveScope(prog, c.scope)
+		if scope.RequiresAdmin() && !registry.IsAdmin() {
+			fmt.Printf("❌ %s uses the %s scope, which requires administrator privileges (try -scope user)\n", prog.Name, scope)
+			continue
+		}
+
+		// Preview the changes before writing anything
+		p := tools.BuildPlan(scope, prog, selectedPath, selectedVars)
+		changes, err := p.Diff(c.store)
+		if err != nil {
+			fmt.Printf("❌ Error computing changes for %s: %v\n", prog.Name, err)
+			continue
+		}
+		fmt.Printf("\nChanges for %s:\n", prog.Name)
+		plan.PrintDiff(os.Stdout, changes)
+		if plan.CountChanged(changes) == 0 || !confirm("Apply these changes? (y/n): ") {
+			continue
+		}
+
+		if _, err := p.CreateBackup(c.store, "configure"); err != nil {
+			fmt.Printf("Warning: Failed to create backup: %v\n", err)
+		}
+		if err := p.Apply(c.store); err != nil {
+			fmt.Printf("❌ Error configuring %s: %v\n", prog.Name, err)
 		} else {
-			if err := tools.ConfigureSelectedPath(prog, selectedPath); err != nil {
-				fmt.Printf("❌ Error configuring %s: %v\n", prog.Name, err)
-			} else {
-				fmt.Printf("✅ %s configured successfully\n", prog.Name)
-			}
+			fmt.Printf("✅ %s configured successfully (%s scope)\n", prog.Name, scope)
 		}
 	}
 }
@@ -127,50 +165,21 @@ func (c *CLI) verifyConfiguration() {
 	fmt.Println("\nVerifying configuration...")
 	issues := config.VerifyConfigurations()
 	
+	printIssues(issues)
 	if len(issues) == 0 {
-		fmt.Println("✅ All checks passed successfully!")
 		return
 	}
 
-	// Группируем проблемы по типу
-	issuesByType := make(map[string][]config.ConfigurationIssue)
-	for _, issue := range issues {
-		issuesByType[issue.Type] = append(issuesByType[issue.Type], issue)
-	}
-
-	fmt.Printf("\nFound %d issues:\n", len(issues))
-
-	// Отображаем проблемы по группам
-	if pathIssues, ok := issuesByType["PATH"]; ok {
-		fmt.Println("\n🔍 PATH Variable Issues:")
-		for _, issue := range pathIssues {
-			fmt.Printf("  • %s\n", issue.Description)
-			fmt.Printf("    Solution: %s\n", issue.Solution)
-		}
-	}
-
-	if envIssues, ok := issuesByType["ENV"]; ok {
-		fmt.Println("\n🔧 Environment Variable Issues:")
-		for _, issue := range envIssues {
-			fmt.Printf("  • %s\n", issue.Description)
-			fmt.Printf("    Solution: %s\n", issue.Solution)
-		}
-	}
-
-	if programIssues, ok := issuesByType["PROGRAM"]; ok {
-		fmt.Println("\n📦 Program Issues:")
-		for _, issue := range programIssues {
-			fmt.Printf("  • %s\n", issue.Description)
-			fmt.Printf("    Solution: %s\n", issue.Solution)
-		}
+	if err := printFixPlan(c.store, issues); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return
 	}
-
 	fmt.Print("\nWould you like to fix these issues? (y/n): ")
 	reader := bufio.NewReader(os.Stdin)
 	input, _ := reader.ReadString('\n')
 	if strings.TrimSpace(strings.ToLower(input)) == "y" {
 		fmt.Println("\nAttempting to fix issues...")
-		if err := config.FixConfigurationIssues(issues); err != nil {
+		if err := config.FixConfigurationIssues(c.store, issues); err != nil {
 			fmt.Printf("❌ Error fixing issues: %v\n", err)
 			fmt.Println("Some issues may require manual intervention.")
 		} else {
@@ -0,0 +1,434 @@
+package cli
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"devpathpro/pkg/backup"
+	"devpathpro/pkg/config"
+	"devpathpro/pkg/plan"
+	"devpathpro/pkg/registry"
+	"devpathpro/pkg/report"
+	"devpathpro/pkg/tools"
+	"devpathpro/pkg/utils"
+)
+
+// Exit codes returned by RunCommand
+const (
+	exitOK    = 0
+	exitError = 1
+	exitUsage = 2
+	// exitUnmet means a check failed: a tool is missing, a project
+	// requirement is not met or configuration issues were found
+	exitUnmet = 3
+	// exitAborted means changes were not confirmed
+	exitAborted = 4
+)
+
+// RunCommand runs a non-interactive subcommand and returns the process exit code.
+// scope is the value of the global -scope flag; an empty scope uses each
+// tool's default scope.
+func RunCommand(cfg *config.Configuration, store registry.EnvStore, scope registry.Scope, args []string) int {
+	if len(args) == 0 {
+		printUsage()
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "scan":
+		return runScan(cfg, args[1:])
+	case "configure":
+		return runConfigure(cfg, store, scope, args[1:])
+	case "verify":
+		return runVerify(args[1:])
+	case "fix":
+		return runFix(store, args[1:])
+	case "checks":
+		return runChecks(args[1:])
+	case "env":
+		return runEnv(store, args[1:])
+	case "catalog":
+		return runCatalog(cfg, args[1:])
+	case "path":
+		return runPath(cfg, store, scope, args[1:])
+	case "shell":
+		return runShell(cfg, store, args[1:])
+	case "plan":
+		return runPlan(cfg, store, scope, args[1:])
+	case "apply":
+		return runApply(store, args[1:])
+	case "backup":
+		return runBackup(store, args[1:])
+	case "project":
+		return runProject(cfg, store, scope, args[1:])
+	case "activate":
+		return runActivate(cfg, store, args[1:])
+	case "profile":
+		return runProfile(cfg, store, args[1:])
+	case "export":
+		return runExport(cfg, store, args[1:])
+	case "import":
+		return runImport(cfg, store, args[1:])
+	case "help":
+		printUsage()
+		return exitOK
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command %q\n", args[0])
+	printUsage()
+	return exitUsage
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "Usage:")
+	fmt.Fprintln(os.Stderr, "  devpathpro [-cli] [-scope user|machine|both]")
+	fmt.Fprintln(os.Stderr, "  devpathpro scan [-output table|json|yaml] [tool...]")
+	fmt.Fprintln(os.Stderr, "  devpathpro configure <tool> [-path P] [-options A,B] [-scope S] [-dry-run] [-yes]")
+	fmt.Fprintln(os.Stderr, "  devpathpro verify [-check IDS] [-output table|json|yaml] [-report junit|sarif=PATH]...")
+	fmt.Fprintln(os.Stderr, "  devpathpro fix [-check IDS] [-yes]")
+	fmt.Fprintln(os.Stderr, "  devpathpro checks list [-output table|json|yaml]")
+	fmt.Fprintln(os.Stderr, "  devpathpro env [-scope user|machine|process] [-output table|json|yaml] [NAME...]")
+	fmt.Fprintln(os.Stderr, "  devpathpro catalog list [-output table|json|yaml]")
+	fmt.Fprintln(os.Stderr, "  devpathpro path list [-scope user|machine|both|process] [-output table|json|yaml]")
+	fmt.Fprintln(os.Stderr, "  devpathpro path up|down|pin|remove|dedupe|expand|unexpand ... [-scope S] [-dry-run] [-yes]")
+	fmt.Fprintln(os.Stderr, "  devpathpro shell status [-output table|json|yaml]")
+	fmt.Fprintln(os.Stderr, "  devpathpro shell sync|remove [-dry-run]")
+	fmt.Fprintln(os.Stderr, "  devpathpro plan [-scope S] [-options A,B] [-o plan.json] <tool> [path]")
+	fmt.Fprintln(os.Stderr, "  devpathpro apply [-yes] <plan.json>")
+	fmt.Fprintln(os.Stderr, "  devpathpro backup create|prune")
+	fmt.Fprintln(os.Stderr, "  devpathpro backup list [-output table|json|yaml]")
+	fmt.Fprintln(os.Stderr, "  devpathpro backup diff <backup> <backup|current>")
+	fmt.Fprintln(os.Stderr, "  devpathpro backup restore [-scope S] [-var NAME] [-path-only] [-preview] [-yes] <backup>")
+	fmt.Fprintln(os.Stderr, "  devpathpro project verify|configure [-f .devpathpro.toml] [-scope S] [-output table|json|yaml] [-yes]")
+	fmt.Fprintln(os.Stderr, "  devpathpro activate [-shell powershell|cmd|bash|zsh|fish] [-path-style msys|wsl] [-o FILE] [profile|manifest]")
+	fmt.Fprintln(os.Stderr, "  devpathpro profile save <name> [tool...]")
+	fmt.Fprintln(os.Stderr, "  devpathpro profile list|show [name] [-output table|json|yaml]")
+	fmt.Fprintln(os.Stderr, "  devpathpro profile switch <name> [-dry-run] [-yes]")
+	fmt.Fprintln(os.Stderr, "  devpathpro profile delete <name>")
+	fmt.Fprintln(os.Stderr, "  devpathpro export [-o FILE] [-scope user|machine|both] [-var NAME,...] [tool...]")
+	fmt.Fprintln(os.Stderr, "  devpathpro import [-dry-run] [-yes] <file>")
+	fmt.Fprintln(os.Stderr, "")
+	fmt.Fprintln(os.Stderr, "Flags may also be written with two dashes and after the arguments, e.g. configure Java --yes.")
+	fmt.Fprintln(os.Stderr, "Exit codes: 0 success, 1 error, 2 usage error, 3 check failed, 4 not confirmed.")
+}
+
+// runPlan prints the changes configuring a tool would make and optionally saves them
+func runPlan(cfg *config.Configuration, store registry.EnvStore, scope registry.Scope, args []string) int {
+	fs := flag.NewFlagSet("plan", flag.ContinueOnError)
+	scopeFlag := fs.String("scope", string(scope), "Where to write variables: user, machine or both")
+	optionsFlag := fs.String("options", "", "Comma-separated configuration options (default: from settings, else all)")
+	output := fs.String("o", "", "Save the plan to this file")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	if len(positional) < 1 || len(positional) > 2 {
+		fmt.Fprintln(os.Stderr, "usage: devpathpro plan [-scope S] [-options A,B] [-o plan.json] <tool> [path]")
+		return exitUsage
+	}
+
+	if *scopeFlag != "" {
+		if scope, err = registry.ParseScope(*scopeFlag); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitUsage
+		}
+	}
+
+	prog, ok := config.FindProgram(cfg.Programs, positional[0])
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown tool %q\n", positional[0])
+		return exitUsage
+	}
+
+	optionNames := splitList(*optionsFlag)
+	if len(optionNames) == 0 {
+		optionNames = cfg.Settings.DefaultOptionsFor(prog.Name)
+	}
+	selectedVars, err := tools.OptionVariables(prog, optionNames)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	var path string
+	if len(positional) == 2 {
+		path = positional[1]
+	}
+	if path == "" {
+		paths := tools.FindProgram(prog)
+		if len(paths) == 0 {
+			fmt.Fprintf(os.Stderr, "%s not found in standard locations; pass the executable path explicitly\n", prog.Name)
+			return exitError
+		}
+		selection, err := tools.ChooseInstallation(prog, paths, tools.DetectVersions(prog, paths))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitError
+		}
+		fmt.Println(selection.Explain())
+		path = selection.Path
+	}
+	fmt.Printf("Planning %s from %s\n\n", prog.Name, tools.DescribePath(path, tools.DetectVersion(prog, path)))
+
+	p := tools.BuildPlan(tools.ResolveScope(prog, scope), prog, path, selectedVars)
+	changes, err := p.Diff(store)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error computing changes: %v\n", err)
+		return exitError
+	}
+	plan.PrintDiff(os.Stdout, changes)
+
+	if *output != "" {
+		if err := p.Save(*output); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitError
+		}
+		fmt.Printf("\nPlan saved to %s. Run \"devpathpro apply %s\" to apply it.\n", *output, *output)
+	}
+	return exitOK
+}
+
+// runApply applies a plan saved by runPlan
+func runApply(store registry.EnvStore, args []string) int {
+	fs := flag.NewFlagSet("apply", flag.ContinueOnError)
+	yes := fs.Bool("yes", false, "Apply without asking for confirmation")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	if len(positional) != 1 {
+		fmt.Fprintln(os.Stderr, "usage: devpathpro apply [-yes] <plan.json>")
+		return exitUsage
+	}
+
+	p, err := plan.Load(positional[0])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+
+	changes, err := p.Diff(store)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error computing changes: %v\n", err)
+		return exitError
+	}
+	plan.PrintDiff(os.Stdout, changes)
+	if plan.CountChanged(changes) == 0 {
+		return exitOK
+	}
+
+	if p.RequiresAdmin() && !registry.IsAdmin() {
+		fmt.Fprintln(os.Stderr, "\nThis plan changes machine variables, which requires administrator privileges")
+		return exitError
+	}
+
+	if !approve(*yes, "\nApply these changes? (y/n): ") {
+		return exitAborted
+	}
+
+	if _, err := p.CreateBackup(store, "apply"); err != nil {
+		fmt.Printf("Warning: Failed to create backup: %v\n", err)
+	}
+	if err := p.Apply(store); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+	fmt.Println("✅ Changes applied successfully")
+	return exitOK
+}
+
+// runBackup creates, lists and restores backups
+func runBackup(store registry.EnvStore, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: devpathpro backup create|list|diff|restore|prune")
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "create":
+		info, err := backup.CreateBackup(store, backup.Metadata{Action: "manual"})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return exitError
+		}
+		fmt.Printf("✅ Backup %s created successfully\n", info.ID)
+		return exitOK
+	case "list":
+		fs := flag.NewFlagSet("backup list", flag.ContinueOnError)
+		output := outputFlag(fs)
+		if _, err := parseFlags(fs, args[1:]); err != nil {
+			return exitUsage
+		}
+		format, err := report.ParseFormat(*output)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitUsage
+		}
+		backups, err := backup.ListBackups()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitError
+		}
+		if format != report.FormatTable {
+			list := report.BackupReport{Header: report.NewHeader("backups"), Backups: backups}
+			if list.Backups == nil {
+				list.Backups = []backup.Info{}
+			}
+			return writeReport(format, list)
+		}
+		for _, info := range backups {
+			fmt.Println(info)
+		}
+		return exitOK
+	case "diff":
+		if len(args) != 3 {
+			fmt.Fprintln(os.Stderr, "usage: devpathpro backup diff <backup> <backup|current>")
+			return exitUsage
+		}
+		changes, err := backup.Compare(store, args[1], args[2])
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitError
+		}
+		fmt.Print(backup.FormatChanges(changes))
+		return exitOK
+	case "restore":
+		return runRestore(store, args[1:])
+	case "prune":
+		removed, err := backup.Prune()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitError
+		}
+		fmt.Printf("Removed %d backup(s)\n", len(removed))
+		return exitOK
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown backup command %q\n", args[0])
+	return exitUsage
+}
+
+// runRestore previews and restores a backup
+func runRestore(store registry.EnvStore, args []string) int {
+	fs := flag.NewFlagSet("backup restore", flag.ContinueOnError)
+	scopeFlag := fs.String("scope", "", "Restore only the machine or the user scope")
+	variable := fs.String("var", "", "Restore only this variable")
+	pathOnly := fs.Bool("path-only", false, "Restore only PATH")
+	preview := fs.Bool("preview", false, "Show the changes without restoring")
+	yes := fs.Bool("yes", false, "Restore without asking for confirmation")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	if len(positional) != 1 {
+		fmt.Fprintln(os.Stderr, "usage: devpathpro backup restore [-scope S] [-var NAME] [-path-only] [-preview] [-yes] <backup>")
+		return exitUsage
+	}
+
+	opts := backup.RestoreOptions{
+		Scope:    registry.Scope(strings.ToLower(*scopeFlag)),
+		Variable: *variable,
+		PathOnly: *pathOnly,
+	}
+	changes, err := backup.PreviewRestore(store, positional[0], opts)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+	fmt.Print(backup.FormatChanges(changes))
+	if *preview || len(changes) == 0 {
+		return exitOK
+	}
+
+	for _, c := range changes {
+		if c.Scope.RequiresAdmin() && !registry.IsAdmin() {
+			fmt.Fprintln(os.Stderr, "\nRestoring machine variables requires administrator privileges (try -scope user)")
+			return exitError
+		}
+	}
+
+	if !approve(*yes, "\nRestore these changes? (y/n): ") {
+		return exitAborted
+	}
+	if err := backup.RestoreBackup(store, positional[0], opts); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+	fmt.Println("✅ Backup restored successfully")
+	return exitOK
+}
+
+// splitList splits a comma-separated flag value, dropping empty items
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// outputFlag adds the -output flag of the commands that report results
+func outputFlag(fs *flag.FlagSet) *string {
+	return fs.String("output", string(report.FormatTable), "Output format: table, json or yaml")
+}
+
+// writeReport prints a report as JSON or YAML and returns the exit code
+func writeReport(format report.Format, r interface{}) int {
+	if err := report.Write(os.Stdout, format, r); err != nil {
+		fmt.Fprintf(os.Stderr, "error writing %s output: %v\n", format, err)
+		return exitError
+	}
+	return exitOK
+}
+
+// parseFlags parses flags that may be mixed with positional arguments, as in
+// "configure Java -yes", and returns the positional arguments. Everything
+// after "--" is positional.
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		if consumed := len(args) - len(rest); consumed > 0 && args[consumed-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// confirm asks a yes/no question on stdin
+func confirm(question string) bool {
+	fmt.Print(question)
+	reader := bufio.NewReader(os.Stdin)
+	input, _ := reader.ReadString('\n')
+	return strings.TrimSpace(strings.ToLower(input)) == "y"
+}
+
+// approve confirms a change: yes approves it without asking, otherwise the
+// question is asked on the terminal. Without a terminal nothing is asked and
+// the change is refused, so scripts never hang waiting for input.
+func approve(yes bool, question string) bool {
+	if yes {
+		return true
+	}
+	if !utils.IsTerminal(os.Stdin) {
+		fmt.Fprintln(os.Stderr, "\nNot running in a terminal; pass -yes to confirm the changes")
+		return false
+	}
+	if !confirm(question) {
+		fmt.Println("Aborted.")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,365 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"devpathpro/pkg/config"
+	"devpathpro/pkg/plan"
+	"devpathpro/pkg/registry"
+	"devpathpro/pkg/report"
+	"devpathpro/pkg/tools"
+)
+
+// issueGroups are the headings verify prints issues under, by issue type
+var issueGroups = []struct {
+	issueType string
+	heading   string
+}{
+	{"PATH", "🔍 PATH Variable Issues:"},
+	{"ENV", "🔧 Environment Variable Issues:"},
+	{"PROGRAM", "📦 Program Issues:"},
+	{"PERMISSION", "🔒 Permission Issues:"},
+	{"SECURITY", "🛡️ Security Issues:"},
+	{"SHELL", "🐚 Shell Profile Issues:"},
+}
+
+// runConfigure finds a tool, shows the changes configuring it makes and applies them
+func runConfigure(cfg *config.Configuration, store registry.EnvStore, scope registry.Scope, args []string) int {
+	fs := flag.NewFlagSet("configure", flag.ContinueOnError)
+	scopeFlag := fs.String("scope", string(scope), "Where to write variables: user, machine or both")
+	pathFlag := fs.String("path", "", "Executable to configure instead of searching for one")
+	optionsFlag := fs.String("options", "", "Comma-separated configuration options (default: from settings, else all)")
+	dryRun := fs.Bool("dry-run", false, "Show the changes without applying them")
+	yes := fs.Bool("yes", false, "Apply without asking for confirmation")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	if len(positional) != 1 {
+		fmt.Fprintln(os.Stderr, "usage: devpathpro configure <tool> [-path P] [-options A,B] [-scope S] [-dry-run] [-yes]")
+		return exitUsage
+	}
+
+	if *scopeFlag != "" {
+		if scope, err = registry.ParseScope(*scopeFlag); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitUsage
+		}
+	}
+
+	prog, ok := config.FindProgram(cfg.Programs, positional[0])
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown tool %q\n", positional[0])
+		return exitUsage
+	}
+
+	optionNames := splitList(*optionsFlag)
+	if len(optionNames) == 0 {
+		optionNames = cfg.Settings.DefaultOptionsFor(prog.Name)
+	}
+	selectedVars, err := tools.OptionVariables(prog, optionNames)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	path := *pathFlag
+	if path != "" {
+		if _, err := os.Stat(path); err != nil {
+			fmt.Fprintf(os.Stderr, "%s: %v\n", prog.Name, err)
+			return exitError
+		}
+	} else {
+		paths := tools.FindProgram(prog)
+		if len(paths) == 0 {
+			fmt.Fprintf(os.Stderr, "❌ %s not found in standard locations; pass -path\n", prog.Name)
+			return exitUnmet
+		}
+		selection, err := tools.ChooseInstallation(prog, paths, tools.DetectVersions(prog, paths))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return exitUnmet
+		}
+		fmt.Println(selection.Explain())
+		path = selection.Path
+	}
+
+	scope = tools.ResolveScope(prog, scope)
+	fmt.Printf("Configuring %s from %s (%s scope)\n\n", prog.Name, tools.DescribePath(path, tools.DetectVersion(prog, path)), scope)
+
+	p := tools.BuildPlan(scope, prog, path, selectedVars)
+	changes, err := p.Diff(store)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error computing changes: %v\n", err)
+		return exitError
+	}
+	plan.PrintDiff(os.Stdout, changes)
+	if *dryRun || plan.CountChanged(changes) == 0 {
+		return exitOK
+	}
+
+	if scope.RequiresAdmin() && !registry.IsAdmin() {
+		fmt.Fprintf(os.Stderr, "\n%s uses the %s scope, which requires administrator privileges (try -scope user)\n", prog.Name, scope)
+		return exitError
+	}
+	if !approve(*yes, "\nApply these changes? (y/n): ") {
+		return exitAborted
+	}
+
+	if _, err := p.CreateBackup(store, "configure"); err != nil {
+		fmt.Printf("Warning: Failed to create backup: %v\n", err)
+	}
+	if err := p.Apply(store); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+	fmt.Printf("✅ %s configured successfully (%s scope)\n", prog.Name, scope)
+	return exitOK
+}
+
+// runVerify checks the environment and exits with exitUnmet if it finds issues
+func runVerify(args []string) int {
+	fs := flag.NewFlagSet("verify", flag.ContinueOnError)
+	output := outputFlag(fs)
+	var reports reportFlag
+	fs.Var(&reports, "report", "Also write the results to a file, e.g. junit=verify.xml or sarif=verify.sarif (repeatable)")
+	checkFlag := fs.String("check", "", "Comma-separated IDs of the checks to report (default: all enabled checks)")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	format, err := report.ParseFormat(*output)
+	if err != nil || len(positional) != 0 {
+		fmt.Fprintln(os.Stderr, "usage: devpathpro verify [-check IDS] [-output table|json|yaml] [-report junit|sarif=PATH]...")
+		return exitUsage
+	}
+
+	issues, err := verifyChecks(splitList(*checkFlag))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	for _, r := range reports {
+		if err := report.WriteIssueFile(r.kind, r.path, issues); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitError
+		}
+	}
+	if format == report.FormatTable {
+		printIssues(issues)
+	} else {
+		verify := report.VerifyReport{Header: report.NewHeader("verify"), Issues: issues}
+		if verify.Issues == nil {
+			verify.Issues = []config.ConfigurationIssue{}
+		}
+		if code := writeReport(format, verify); code != exitOK {
+			return code
+		}
+	}
+	if len(issues) > 0 {
+		return exitUnmet
+	}
+	return exitOK
+}
+
+// runFix checks the environment and fixes the issues it finds
+func runFix(store registry.EnvStore, args []string) int {
+	fs := flag.NewFlagSet("fix", flag.ContinueOnError)
+	yes := fs.Bool("yes", false, "Fix without asking for confirmation")
+	checkFlag := fs.String("check", "", "Comma-separated IDs of the checks to fix (default: all enabled checks)")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	if len(positional) != 0 {
+		fmt.Fprintln(os.Stderr, "usage: devpathpro fix [-check IDS] [-yes]")
+		return exitUsage
+	}
+
+	issues, err := verifyChecks(splitList(*checkFlag))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	printIssues(issues)
+	fixable := 0
+	for _, issue := range issues {
+		if issue.Fixable {
+			fixable++
+		}
+	}
+	if fixable == 0 {
+		if len(issues) > 0 {
+			fmt.Println("\nNone of these issues can be fixed automatically.")
+			return exitUnmet
+		}
+		return exitOK
+	}
+	if err := printFixPlan(store, issues); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+	if !approve(*yes, fmt.Sprintf("\nWould you like to fix %d of these issues? (y/n): ", fixable)) {
+		return exitAborted
+	}
+
+	if err := config.FixConfigurationIssues(store, issues); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error fixing issues: %v\n", err)
+		fmt.Fprintln(os.Stderr, "Some issues may require manual intervention.")
+		return exitError
+	}
+	fmt.Println("✅ Issues fixed successfully!")
+	return exitOK
+}
+
+// printFixPlan shows the variable changes fixing the issues would make
+func printFixPlan(store registry.EnvStore, issues []config.ConfigurationIssue) error {
+	p, err := config.FixPlan(store, issues)
+	if err != nil {
+		return fmt.Errorf("error planning fixes: %v", err)
+	}
+	if p.Empty() {
+		return nil
+	}
+	changes, err := p.Diff(store)
+	if err != nil {
+		return fmt.Errorf("error computing changes: %v", err)
+	}
+	fmt.Println("\nThe fixes change these variables:")
+	plan.PrintDiff(os.Stdout, changes)
+	return nil
+}
+
+// verifyChecks runs the enabled checks and keeps the issues of the given
+// checks; no IDs keep all issues
+func verifyChecks(ids []string) ([]config.ConfigurationIssue, error) {
+	for _, id := range ids {
+		if _, ok := config.FindCheck(id); !ok {
+			return nil, fmt.Errorf("unknown check %q; run \"devpathpro checks list\" to see them", id)
+		}
+	}
+
+	issues := config.VerifyConfigurations()
+	if len(ids) == 0 {
+		return issues, nil
+	}
+	var selected []config.ConfigurationIssue
+	for _, issue := range issues {
+		if containsFold(ids, issue.CheckID) {
+			selected = append(selected, issue)
+		}
+	}
+	return selected, nil
+}
+
+// runChecks lists the verify checks with their settings
+func runChecks(args []string) int {
+	if len(args) == 0 || args[0] != "list" {
+		fmt.Fprintln(os.Stderr, "usage: devpathpro checks list [-output table|json|yaml]")
+		return exitUsage
+	}
+	fs := flag.NewFlagSet("checks list", flag.ContinueOnError)
+	output := outputFlag(fs)
+	positional, err := parseFlags(fs, args[1:])
+	if err != nil {
+		return exitUsage
+	}
+	format, err := report.ParseFormat(*output)
+	if err != nil || len(positional) != 0 {
+		fmt.Fprintln(os.Stderr, "usage: devpathpro checks list [-output table|json|yaml]")
+		return exitUsage
+	}
+
+	list := report.ChecksReport{Header: report.NewHeader("checks"), Checks: []report.CheckEntry{}}
+	for _, c := range config.Checks() {
+		severity := config.SeverityOverride(c)
+		if severity == "" {
+			severity = c.DefaultSeverity()
+		}
+		list.Checks = append(list.Checks, report.CheckEntry{
+			ID:       c.ID(),
+			Title:    c.Title(),
+			Category: c.Category(),
+			Severity: severity,
+			Enabled:  config.CheckEnabled(c),
+			CanFix:   config.CanFix(c),
+		})
+	}
+
+	if format != report.FormatTable {
+		return writeReport(format, list)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tCATEGORY\tSEVERITY\tENABLED\tFIX\tTITLE")
+	for _, c := range list.Checks {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", c.ID, c.Category, c.Severity, yesNo(c.Enabled), yesNo(c.CanFix), c.Title)
+	}
+	w.Flush()
+	return exitOK
+}
+
+// yesNo formats a flag for tables
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// reportFile is a verify report file requested with -report
+type reportFile struct {
+	kind string
+	path string
+}
+
+// reportFlag collects repeated -report format=path flags
+type reportFlag []reportFile
+
+func (f *reportFlag) String() string {
+	var values []string
+	for _, r := range *f {
+		values = append(values, r.kind+"="+r.path)
+	}
+	return strings.Join(values, ", ")
+}
+
+func (f *reportFlag) Set(value string) error {
+	kind, path, ok := strings.Cut(value, "=")
+	if !ok || strings.TrimSpace(path) == "" {
+		return fmt.Errorf("expected format=path, e.g. junit=verify.xml")
+	}
+	if _, err := report.IssueWriter(kind); err != nil {
+		return err
+	}
+	*f = append(*f, reportFile{kind: strings.ToLower(kind), path: strings.TrimSpace(path)})
+	return nil
+}
+
+// printIssues prints configuration issues grouped by type
+func printIssues(issues []config.ConfigurationIssue) {
+	if len(issues) == 0 {
+		fmt.Println("✅ All checks passed successfully!")
+		return
+	}
+
+	issuesByType := make(map[string][]config.ConfigurationIssue)
+	for _, issue := range issues {
+		issuesByType[issue.Type] = append(issuesByType[issue.Type], issue)
+	}
+
+	fmt.Printf("\nFound %d issues:\n", len(issues))
+	for _, group := range issueGroups {
+		if len(issuesByType[group.issueType]) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s\n", group.heading)
+		for _, issue := range issuesByType[group.issueType] {
+			fmt.Printf("  • [%s] %s\n", issue.CheckID, issue.Description)
+			fmt.Printf("    Solution: %s\n", issue.Solution)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"devpathpro/pkg/config"
+	"devpathpro/pkg/manifest"
+	"devpathpro/pkg/plan"
+	"devpathpro/pkg/profile"
+	"devpathpro/pkg/registry"
+)
+
+const (
+	exportUsage = "usage: devpathpro export [-o FILE] [-scope user|machine|both] [-var NAME,...] [tool...]"
+	importUsage = "usage: devpathpro import [-dry-run] [-yes] <file>"
+)
+
+// runExport writes a portable description of the configured tools, the
+// given variables and the PATH order that another machine can import
+func runExport(cfg *config.Configuration, store registry.EnvStore, args []string) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	output := fs.String("o", "", "Write the description to this file instead of standard output")
+	scopeFlag := fs.String("scope", string(registry.ScopeUser), "PATH to export: user, machine or both")
+	varsFlag := fs.String("var", "", "Comma-separated variables to export besides the ones of the tools")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	scope, err := registry.ParseScope(*scopeFlag)
+	if err != nil || scope == registry.ScopeProcess {
+		fmt.Fprintln(os.Stderr, exportUsage)
+		return exitUsage
+	}
+
+	portable, missing, err := profile.Export(store, cfg.Programs, positional, splitList(*varsFlag), scope)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+	if len(positional) > 0 && len(missing) > 0 {
+		fmt.Fprintf(os.Stderr, "⚠️ Not configured, left out: %s\n", strings.Join(missing, ", "))
+	}
+	if len(portable.Tools) == 0 {
+		fmt.Fprintln(os.Stderr, "⚠️ No configured tools found; only variables and PATH are exported")
+	}
+
+	var buf bytes.Buffer
+	if err := portable.Write(&buf); err != nil {
+		fmt.Fprintf(os.Stderr, "error encoding the environment: %v\n", err)
+		return exitError
+	}
+	if *output == "" {
+		fmt.Print(buf.String())
+		return exitOK
+	}
+	if err := os.WriteFile(*output, buf.Bytes(), 0644); err != nil {
+		fmt.Fprintf(os.Stderr, "error writing %s: %v\n", *output, err)
+		return exitError
+	}
+	fmt.Fprintf(os.Stderr, "✅ Exported %d tool(s) to %s\n", len(portable.Tools), *output)
+	return exitOK
+}
+
+// runImport finds installations on this machine for the tools of an exported
+// environment, reports the ones missing and configures the rest together with
+// the variables and PATH order, in one transaction after a backup
+func runImport(cfg *config.Configuration, store registry.EnvStore, args []string) int {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "Show the changes without applying them")
+	yes := fs.Bool("yes", false, "Import without asking for confirmation")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	if len(positional) != 1 {
+		fmt.Fprintln(os.Stderr, importUsage)
+		return exitUsage
+	}
+
+	portable, err := profile.LoadPortable(positional[0], cfg.Programs)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+	resolution, err := portable.Resolve(store, cfg.Programs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+
+	fmt.Printf("Importing %s\n\n", positional[0])
+	printProjectResults(resolution.Tools)
+	for _, skipped := range resolution.Skipped {
+		fmt.Printf("⚠️ Skipped %s\n", skipped)
+	}
+	code := exitOK
+	if !manifest.AllMet(resolution.Tools) || len(resolution.Skipped) > 0 {
+		code = exitUnmet
+	}
+	if resolution.Plan.Empty() {
+		fmt.Println("\nNothing to change.")
+		return code
+	}
+
+	changes, err := resolution.Plan.Diff(store)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error computing changes: %v\n", err)
+		return exitError
+	}
+	fmt.Println()
+	plan.PrintDiff(os.Stdout, changes)
+	if *dryRun {
+		return code
+	}
+	if resolution.Plan.RequiresAdmin() && !registry.IsAdmin() {
+		fmt.Fprintln(os.Stderr, "\nThis import changes machine variables, which requires administrator privileges")
+		return exitError
+	}
+	if !approve(*yes, "\nApply these changes? (y/n): ") {
+		return exitAborted
+	}
+
+	if _, err := resolution.Plan.CreateBackup(store, "import"); err != nil {
+		fmt.Printf("Warning: Failed to create backup: %v\n", err)
+	}
+	if err := resolution.Plan.Apply(store); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+	fmt.Printf("✅ Imported %s\n", positional[0])
+	return code
+}
@@ -0,0 +1,223 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"devpathpro/pkg/config"
+	"devpathpro/pkg/registry"
+	"devpathpro/pkg/report"
+	"devpathpro/pkg/tools"
+)
+
+// runScan searches for the named tools, or all known tools, and prints every
+// installation found. It exits with exitUnmet if a named tool is not found.
+func runScan(cfg *config.Configuration, args []string) int {
+	fs := flag.NewFlagSet("scan", flag.ContinueOnError)
+	output := outputFlag(fs)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	format, err := report.ParseFormat(*output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	programs := cfg.Programs
+	if len(positional) > 0 {
+		programs = nil
+		for _, name := range positional {
+			prog, ok := config.FindProgram(cfg.Programs, name)
+			if !ok {
+				fmt.Fprintf(os.Stderr, "unknown tool %q\n", name)
+				return exitUsage
+			}
+			programs = append(programs, prog)
+		}
+	}
+
+	scan := report.ScanReport{Header: report.NewHeader("scan"), Tools: []report.Tool{}}
+	missing := 0
+	for _, prog := range programs {
+		tool := report.Tool{Name: prog.Name, Category: prog.Category, Installations: []report.Installation{}}
+		paths := tools.FindProgram(prog)
+		if len(paths) == 0 {
+			missing++
+		} else {
+			tool.Found = true
+			versions := tools.DetectVersions(prog, paths)
+			for _, path := range paths {
+				tool.Installations = append(tool.Installations, report.Installation{Path: path, Version: versions[path]})
+			}
+			if selection, err := tools.ChooseInstallation(prog, paths, versions); err == nil {
+				tool.ChosenPath = selection.Path
+			} else {
+				tool.Error = err.Error()
+			}
+		}
+		scan.Tools = append(scan.Tools, tool)
+	}
+
+	if format == report.FormatTable {
+		printScan(scan)
+	} else if code := writeReport(format, scan); code != exitOK {
+		return code
+	}
+
+	if len(positional) > 0 && missing > 0 {
+		return exitUnmet
+	}
+	return exitOK
+}
+
+// printScan prints the installations of every tool, marking the chosen one
+func printScan(scan report.ScanReport) {
+	for _, tool := range scan.Tools {
+		if !tool.Found {
+			fmt.Printf("❌ %s not found\n", tool.Name)
+			continue
+		}
+		fmt.Printf("✅ %s\n", tool.Name)
+		for _, inst := range tool.Installations {
+			marker := " "
+			if inst.Path == tool.ChosenPath {
+				marker = "*"
+			}
+			fmt.Printf("  %s %s\n", marker, tools.DescribePath(inst.Path, inst.Version))
+		}
+		if tool.Error != "" {
+			fmt.Printf("  ⚠️ %s\n", tool.Error)
+		}
+	}
+}
+
+// runEnv prints the variables of the machine and user scopes, or of one scope,
+// optionally limited to the given names. It exits with exitUnmet if a named
+// variable is not set.
+func runEnv(store registry.EnvStore, args []string) int {
+	fs := flag.NewFlagSet("env", flag.ContinueOnError)
+	scopeFlag := fs.String("scope", "", "Show only this scope: user, machine or process")
+	output := outputFlag(fs)
+	names, err := parseFlags(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	format, err := report.ParseFormat(*output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	scopes := []registry.Scope{registry.ScopeMachine, registry.ScopeUser}
+	switch scope := registry.Scope(strings.ToLower(*scopeFlag)); scope {
+	case "":
+	case registry.ScopeMachine, registry.ScopeUser, registry.ScopeProcess:
+		scopes = []registry.Scope{scope}
+	default:
+		fmt.Fprintf(os.Stderr, "invalid scope %q: expected user, machine or process\n", *scopeFlag)
+		return exitUsage
+	}
+
+	env := report.EnvReport{Header: report.NewHeader("env"), Variables: []report.Variable{}}
+	found := make(map[string]bool)
+	for _, scope := range scopes {
+		vars, err := store.List(scope)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitError
+		}
+		keys := make([]string, 0, len(vars))
+		for name := range vars {
+			if len(names) == 0 || containsFold(names, name) {
+				keys = append(keys, name)
+				found[strings.ToLower(name)] = true
+			}
+		}
+		sort.Slice(keys, func(i, j int) bool { return strings.ToLower(keys[i]) < strings.ToLower(keys[j]) })
+		for _, name := range keys {
+			env.Variables = append(env.Variables, report.Variable{Scope: scope, Name: name, Value: vars[name]})
+		}
+	}
+	for _, name := range names {
+		if !found[strings.ToLower(name)] {
+			env.Missing = append(env.Missing, name)
+		}
+	}
+
+	if format == report.FormatTable {
+		for _, v := range env.Variables {
+			fmt.Printf("[%s] %s=%s\n", v.Scope, v.Name, v.Value)
+		}
+		for _, name := range env.Missing {
+			fmt.Fprintf(os.Stderr, "%s is not set\n", name)
+		}
+	} else if code := writeReport(format, env); code != exitOK {
+		return code
+	}
+
+	if len(env.Missing) > 0 {
+		return exitUnmet
+	}
+	return exitOK
+}
+
+// runCatalog lists the known tools
+func runCatalog(cfg *config.Configuration, args []string) int {
+	if len(args) == 0 || args[0] != "list" {
+		fmt.Fprintln(os.Stderr, "usage: devpathpro catalog list [-output table|json|yaml]")
+		return exitUsage
+	}
+	fs := flag.NewFlagSet("catalog list", flag.ContinueOnError)
+	output := outputFlag(fs)
+	positional, err := parseFlags(fs, args[1:])
+	if err != nil {
+		return exitUsage
+	}
+	format, err := report.ParseFormat(*output)
+	if err != nil || len(positional) != 0 {
+		fmt.Fprintln(os.Stderr, "usage: devpathpro catalog list [-output table|json|yaml]")
+		return exitUsage
+	}
+
+	catalog := report.CatalogReport{Header: report.NewHeader("catalog"), Programs: []report.CatalogEntry{}}
+	for _, prog := range cfg.Programs {
+		entry := report.CatalogEntry{
+			Name:       prog.Name,
+			Category:   prog.Category,
+			Executable: prog.Executable(),
+			Scope:      tools.ResolveScope(prog, ""),
+		}
+		for _, opt := range tools.GetConfigOptions(prog) {
+			entry.Options = append(entry.Options, opt.Name)
+		}
+		catalog.Programs = append(catalog.Programs, entry)
+	}
+
+	if format != report.FormatTable {
+		return writeReport(format, catalog)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tCATEGORY\tEXECUTABLE\tSCOPE\tOPTIONS")
+	for _, entry := range catalog.Programs {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", entry.Name, entry.Category, entry.Executable,
+			entry.Scope, strings.Join(entry.Options, ","))
+	}
+	w.Flush()
+	return exitOK
+}
+
+// containsFold reports whether list contains s, ignoring case
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
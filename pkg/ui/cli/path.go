@@ -0,0 +1,226 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"devpathpro/pkg/config"
+	"devpathpro/pkg/pathedit"
+	"devpathpro/pkg/registry"
+	"devpathpro/pkg/report"
+)
+
+const pathUsage = `usage: devpathpro path list [-scope user|machine|both|process] [-output table|json|yaml]
+       devpathpro path up|down <entry|N> [-by N] [-scope S] [-dry-run] [-yes]
+       devpathpro path pin <entry> <before-entry|N> [-scope S] [-dry-run] [-yes]
+       devpathpro path remove <entry|N>... [-scope S] [-dry-run] [-yes]
+       devpathpro path dedupe [-scope S] [-dry-run] [-yes]
+       devpathpro path expand [entry|N...] [-scope S] [-dry-run] [-yes]
+       devpathpro path unexpand [-vars A,B] [entry|N...] [-scope S] [-dry-run] [-yes]`
+
+// runPath lists and edits the PATH entries of a scope
+func runPath(cfg *config.Configuration, store registry.EnvStore, scope registry.Scope, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, pathUsage)
+		return exitUsage
+	}
+	switch args[0] {
+	case "list":
+		return runPathList(cfg, store, scope, args[1:])
+	case "up", "down", "pin", "remove", "dedupe", "expand", "unexpand":
+		return runPathEdit(cfg, store, scope, args[0], args[1:])
+	}
+	fmt.Fprintf(os.Stderr, "unknown path command %q\n", args[0])
+	fmt.Fprintln(os.Stderr, pathUsage)
+	return exitUsage
+}
+
+// pathEditScope returns the PATH edited when no -scope is given: the one the
+// global scope names, else the user PATH, since "both" names two
+func pathEditScope(scope registry.Scope) registry.Scope {
+	if scope == registry.ScopeMachine {
+		return scope
+	}
+	return registry.ScopeUser
+}
+
+// runPathList prints the PATH entries with the directories that are missing,
+// duplicated or belong to a known tool
+func runPathList(cfg *config.Configuration, store registry.EnvStore, scope registry.Scope, args []string) int {
+	fs := flag.NewFlagSet("path list", flag.ContinueOnError)
+	scopeFlag := fs.String("scope", "", "Scope to list: user, machine, both or process (default: both)")
+	output := outputFlag(fs)
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+	format, err := report.ParseFormat(*output)
+	if err != nil || len(positional) != 0 {
+		fmt.Fprintln(os.Stderr, pathUsage)
+		return exitUsage
+	}
+
+	listScope := registry.ScopeBoth
+	switch s := registry.Scope(strings.ToLower(*scopeFlag)); s {
+	case "":
+	case registry.ScopeMachine, registry.ScopeUser, registry.ScopeBoth, registry.ScopeProcess:
+		listScope = s
+	default:
+		fmt.Fprintf(os.Stderr, "invalid scope %q: expected user, machine, both or process\n", *scopeFlag)
+		return exitUsage
+	}
+
+	entries, err := pathedit.List(store, listScope, cfg.Programs)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+	if format != report.FormatTable {
+		list := report.PathReport{Header: report.NewHeader("path"), Entries: entries}
+		if list.Entries == nil {
+			list.Entries = []pathedit.Entry{}
+		}
+		return writeReport(format, list)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SCOPE\tPOS\tENTRY\tTOOL\tNOTES")
+	for _, entry := range entries {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", entry.Scope, entry.Position, entry.Value, entry.Owner, strings.Join(entry.Notes(), ", "))
+	}
+	w.Flush()
+	return exitOK
+}
+
+// runPathEdit makes one edit to the PATH of a scope, shows it and writes it
+// once confirmed
+func runPathEdit(cfg *config.Configuration, store registry.EnvStore, scope registry.Scope, command string, args []string) int {
+	fs := flag.NewFlagSet("path "+command, flag.ContinueOnError)
+	scopeFlag := fs.String("scope", "", "PATH to edit: user, machine or process (default: the global -scope when it names one scope, else user)")
+	by := fs.Int("by", 1, "Number of positions to move the entry (up and down)")
+	vars := fs.String("vars", "", "Comma-separated variables to unexpand to (default: well-known folders and tool homes)")
+	dryRun := fs.Bool("dry-run", false, "Show the changes without writing them")
+	yes := fs.Bool("yes", false, "Write without asking for confirmation")
+	positional, err := parseFlags(fs, args)
+	if err != nil {
+		return exitUsage
+	}
+
+	editScope := pathEditScope(scope)
+	switch s := registry.Scope(strings.ToLower(*scopeFlag)); s {
+	case "":
+	case registry.ScopeMachine, registry.ScopeUser, registry.ScopeProcess:
+		editScope = s
+	default:
+		fmt.Fprintf(os.Stderr, "invalid scope %q: PATH is edited one scope at a time; expected user, machine or process\n", *scopeFlag)
+		return exitUsage
+	}
+
+	ed, err := pathedit.Open(store, editScope)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+	if code := editPath(cfg, ed, command, positional, *by, splitList(*vars)); code != exitOK {
+		return code
+	}
+
+	fmt.Print(ed.Preview())
+	if !ed.Changed() || *dryRun {
+		return exitOK
+	}
+	if editScope.RequiresAdmin() && !registry.IsAdmin() {
+		fmt.Fprintln(os.Stderr, "\nChanging the machine PATH requires administrator privileges")
+		return exitError
+	}
+	if !approve(*yes, "\nApply these changes? (y/n): ") {
+		return exitAborted
+	}
+	if err := ed.Apply(); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+	fmt.Printf("✅ %s PATH updated\n", editScope)
+	return exitOK
+}
+
+// editPath applies a path subcommand to the editor
+func editPath(cfg *config.Configuration, ed *pathedit.Editor, command string, args []string, by int, vars []string) int {
+	usage := func() int {
+		fmt.Fprintln(os.Stderr, pathUsage)
+		return exitUsage
+	}
+
+	var err error
+	switch command {
+	case "up", "down":
+		if len(args) != 1 || by < 1 {
+			return usage()
+		}
+		var i int
+		if i, err = ed.Find(args[0]); err == nil {
+			if command == "up" {
+				by = -by
+			}
+			err = ed.Move(i, i+by)
+		}
+	case "pin":
+		if len(args) != 2 {
+			return usage()
+		}
+		err = ed.Pin(args[0], args[1])
+	case "remove":
+		if len(args) == 0 {
+			return usage()
+		}
+		// Resolve positions first, so removing one entry does not shift the next
+		entries := ed.Entries()
+		targets := make([]string, len(args))
+		for i, arg := range args {
+			if n, convErr := strconv.Atoi(arg); convErr == nil && n >= 1 && n <= len(entries) {
+				targets[i] = entries[n-1]
+			} else {
+				targets[i] = arg
+			}
+		}
+		for _, target := range targets {
+			if _, err = ed.Remove(target); err != nil {
+				break
+			}
+		}
+	case "dedupe":
+		if len(args) != 0 {
+			return usage()
+		}
+		for _, entry := range ed.Dedupe() {
+			fmt.Printf("Removing duplicate %s\n", entry)
+		}
+	case "expand", "unexpand":
+		var indexes []int
+		for _, arg := range args {
+			var i int
+			if i, err = ed.Find(arg); err != nil {
+				break
+			}
+			indexes = append(indexes, i)
+		}
+		if err == nil && command == "expand" {
+			ed.Expand(indexes...)
+		} else if err == nil {
+			refs := ed.UnexpandVars(cfg.Programs)
+			if len(vars) > 0 {
+				refs = ed.Vars(vars)
+			}
+			ed.Unexpand(refs, indexes...)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+	return exitOK
+}
@@ -0,0 +1,268 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"devpathpro/pkg/config"
+	"devpathpro/pkg/plan"
+	"devpathpro/pkg/profile"
+	"devpathpro/pkg/registry"
+	"devpathpro/pkg/report"
+)
+
+const profileUsage = `usage: devpathpro profile save <name> [tool...]
+       devpathpro profile list [-output table|json|yaml]
+       devpathpro profile show <name> [-output table|json|yaml]
+       devpathpro profile switch <name> [-dry-run] [-yes]
+       devpathpro profile delete <name>`
+
+// runProfile saves, lists, shows, switches to or deletes named profiles
+func runProfile(cfg *config.Configuration, store registry.EnvStore, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, profileUsage)
+		return exitUsage
+	}
+	fs := flag.NewFlagSet("profile "+args[0], flag.ContinueOnError)
+	var output *string
+	var dryRun, yes *bool
+	switch args[0] {
+	case "list", "show":
+		output = outputFlag(fs)
+	case "switch":
+		dryRun = fs.Bool("dry-run", false, "Show the changes without applying them")
+		yes = fs.Bool("yes", false, "Switch without asking for confirmation")
+	case "save", "delete":
+	default:
+		fmt.Fprintf(os.Stderr, "unknown profile command %q\n", args[0])
+		fmt.Fprintln(os.Stderr, profileUsage)
+		return exitUsage
+	}
+	positional, err := parseFlags(fs, args[1:])
+	if err != nil {
+		return exitUsage
+	}
+	if (args[0] == "list") != (len(positional) == 0) || (args[0] != "save" && len(positional) > 1) {
+		fmt.Fprintln(os.Stderr, profileUsage)
+		return exitUsage
+	}
+
+	switch args[0] {
+	case "save":
+		return runProfileSave(cfg, store, positional[0], positional[1:])
+	case "list":
+		return runProfileList(cfg, store, *output)
+	case "show":
+		return runProfileShow(cfg, store, positional[0], *output)
+	case "switch":
+		return runProfileSwitch(cfg, store, positional[0], *dryRun, *yes)
+	}
+	return runProfileDelete(cfg, positional[0])
+}
+
+// runProfileSave records how the named tools, or all configured tools, are
+// set up now and saves them as a profile
+func runProfileSave(cfg *config.Configuration, store registry.EnvStore, name string, tools []string) int {
+	captured, missing, err := profile.Capture(store, cfg.Programs, tools)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+	if len(tools) > 0 && len(missing) > 0 {
+		fmt.Fprintf(os.Stderr, "⚠️ Not configured, left out of the profile: %s\n", strings.Join(missing, ", "))
+	}
+	if len(captured) == 0 {
+		fmt.Fprintln(os.Stderr, "❌ No configured tools to save; configure them first")
+		return exitError
+	}
+
+	_, exists := config.FindProfile(cfg.Profiles(), name)
+	if err := cfg.PutProfile(config.Profile{Name: name, Tools: captured}); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+	printProfileTools(captured)
+	if exists {
+		fmt.Printf("\n✅ Profile %s updated in %s\n", name, cfg.SettingsPath)
+	} else {
+		fmt.Printf("\n✅ Profile %s saved to %s\n", name, cfg.SettingsPath)
+	}
+	return exitOK
+}
+
+// runProfileList lists the saved profiles and marks the one the environment matches
+func runProfileList(cfg *config.Configuration, store registry.EnvStore, output string) int {
+	format, err := report.ParseFormat(output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	list := report.ProfileReport{Header: report.NewHeader("profiles"), Profiles: []report.ProfileEntry{}}
+	for _, p := range cfg.Profiles() {
+		list.Profiles = append(list.Profiles, profileEntry(cfg, store, p))
+	}
+	if format != report.FormatTable {
+		return writeReport(format, list)
+	}
+	if len(list.Profiles) == 0 {
+		fmt.Println("No profiles saved; run \"devpathpro profile save <name>\" to save the current tools.")
+		return exitOK
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tSTATE\tTOOLS")
+	for _, entry := range list.Profiles {
+		names := make([]string, len(entry.Tools))
+		for i, t := range entry.Tools {
+			names[i] = t.Tool
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", entry.Name, profileState(entry), strings.Join(names, ", "))
+	}
+	w.Flush()
+	return exitOK
+}
+
+// runProfileShow prints the tools of a profile and the changes switching to it makes
+func runProfileShow(cfg *config.Configuration, store registry.EnvStore, name, output string) int {
+	format, err := report.ParseFormat(output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	p, ok := config.FindProfile(cfg.Profiles(), name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown profile %q\n", name)
+		return exitError
+	}
+	if format != report.FormatTable {
+		show := report.ProfileReport{Header: report.NewHeader("profiles"), Profiles: []report.ProfileEntry{profileEntry(cfg, store, p)}}
+		return writeReport(format, show)
+	}
+
+	fmt.Printf("Profile %s\n\n", p.Name)
+	printProfileTools(p.Tools)
+	switchPlan, err := profile.SwitchPlan(store, cfg.Programs, p)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "\n❌ %v\n", err)
+		return exitError
+	}
+	changes, err := switchPlan.Diff(store)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error computing changes: %v\n", err)
+		return exitError
+	}
+	if len(changes) == 0 {
+		fmt.Println("\nThe environment matches this profile.")
+		return exitOK
+	}
+	fmt.Println("\nSwitching to this profile would change:")
+	plan.PrintDiff(os.Stdout, changes)
+	return exitOK
+}
+
+// runProfileSwitch applies the changes that make the environment match a
+// profile, in one transaction after a backup
+func runProfileSwitch(cfg *config.Configuration, store registry.EnvStore, name string, dryRun, yes bool) int {
+	p, ok := config.FindProfile(cfg.Profiles(), name)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown profile %q\n", name)
+		return exitError
+	}
+	switchPlan, err := profile.SwitchPlan(store, cfg.Programs, p)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+	if switchPlan.Empty() {
+		fmt.Printf("The environment already matches profile %s.\n", p.Name)
+		return exitOK
+	}
+
+	changes, err := switchPlan.Diff(store)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error computing changes: %v\n", err)
+		return exitError
+	}
+	plan.PrintDiff(os.Stdout, changes)
+	if dryRun {
+		return exitOK
+	}
+	if switchPlan.RequiresAdmin() && !registry.IsAdmin() {
+		fmt.Fprintln(os.Stderr, "\nThis profile changes machine variables, which requires administrator privileges")
+		return exitError
+	}
+	if !approve(yes, fmt.Sprintf("\nSwitch to profile %s? (y/n): ", p.Name)) {
+		return exitAborted
+	}
+
+	if _, err := switchPlan.CreateBackup(store, "profile"); err != nil {
+		fmt.Printf("Warning: Failed to create backup: %v\n", err)
+	}
+	if err := switchPlan.Apply(store); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+	fmt.Printf("✅ Switched to profile %s\n", p.Name)
+	return exitOK
+}
+
+// runProfileDelete removes a profile from the settings
+func runProfileDelete(cfg *config.Configuration, name string) int {
+	deleted, err := cfg.DeleteProfile(name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+	if !deleted {
+		fmt.Fprintf(os.Stderr, "unknown profile %q\n", name)
+		return exitError
+	}
+	fmt.Printf("✅ Profile %s deleted\n", name)
+	return exitOK
+}
+
+// profileEntry compares the environment with a profile
+func profileEntry(cfg *config.Configuration, store registry.EnvStore, p config.Profile) report.ProfileEntry {
+	entry := report.ProfileEntry{Profile: p}
+	switchPlan, err := profile.SwitchPlan(store, cfg.Programs, p)
+	if err != nil {
+		entry.Error = err.Error()
+		return entry
+	}
+	entry.Changes = len(switchPlan.Operations)
+	entry.Active = switchPlan.Empty()
+	return entry
+}
+
+// profileState describes a profile entry in one word for the list table
+func profileState(entry report.ProfileEntry) string {
+	switch {
+	case entry.Error != "":
+		return "error: " + entry.Error
+	case entry.Active:
+		return "active"
+	}
+	return fmt.Sprintf("%d change(s)", entry.Changes)
+}
+
+// printProfileTools prints one line per tool of a profile
+func printProfileTools(profileTools []config.ProfileTool) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TOOL\tSCOPE\tOPTIONS\tPATH")
+	for _, t := range profileTools {
+		scope := string(t.Scope)
+		if scope == "" {
+			scope = "default"
+		}
+		options := strings.Join(t.Options, ",")
+		if options == "" {
+			options = "all"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", t.Tool, scope, options, t.Path)
+	}
+	w.Flush()
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"devpathpro/pkg/config"
+	"devpathpro/pkg/manifest"
+	"devpathpro/pkg/plan"
+	"devpathpro/pkg/registry"
+	"devpathpro/pkg/report"
+)
+
+// runProject verifies or configures the tools listed in a project manifest
+func runProject(cfg *config.Configuration, store registry.EnvStore, scope registry.Scope, args []string) int {
+	if len(args) == 0 || (args[0] != "verify" && args[0] != "configure") {
+		fmt.Fprintln(os.Stderr, "usage: devpathpro project verify|configure [-f .devpathpro.toml] [-scope S] [-output table|json|yaml] [-yes]")
+		return exitUsage
+	}
+	action := args[0]
+
+	fs := flag.NewFlagSet("project "+action, flag.ContinueOnError)
+	file := fs.String("f", "", "Manifest file (default: "+manifest.FileName+" in the current directory or a parent)")
+	scopeFlag := fs.String("scope", "", "Where to write variables: user, machine or both (default: from the manifest)")
+	yes := fs.Bool("yes", false, "Configure without asking for confirmation")
+	output := outputFlag(fs)
+	positional, err := parseFlags(fs, args[1:])
+	if err != nil {
+		return exitUsage
+	}
+	format, err := report.ParseFormat(*output)
+	if err != nil || len(positional) != 0 {
+		fmt.Fprintln(os.Stderr, "usage: devpathpro project verify|configure [-f .devpathpro.toml] [-scope S] [-output table|json|yaml] [-yes]")
+		return exitUsage
+	}
+	if format != report.FormatTable && action != "verify" {
+		fmt.Fprintln(os.Stderr, "-output is only supported by project verify")
+		return exitUsage
+	}
+
+	path := *file
+	if path == "" {
+		if path, err = manifest.Find("."); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitError
+		}
+	}
+	m, err := manifest.Load(path, cfg.Programs)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+
+	// The -scope of this command wins over the manifest, which wins over the global scope
+	checkScope := scope
+	if m.Scope != "" {
+		checkScope = ""
+	}
+	if *scopeFlag != "" {
+		if checkScope, err = registry.ParseScope(*scopeFlag); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return exitUsage
+		}
+	}
+
+	results, err := m.Check(store, cfg.Programs, checkScope)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+
+	code := exitOK
+	if !manifest.AllMet(results) {
+		code = exitUnmet
+	}
+	combined := manifest.Plan(results)
+
+	if format != report.FormatTable {
+		if code := writeReport(format, projectReport(m, results)); code != exitOK {
+			return code
+		}
+		if !combined.Empty() {
+			return exitUnmet
+		}
+		return code
+	}
+
+	fmt.Printf("Checking %s\n\n", m.Path)
+	printProjectResults(results)
+	if combined.Empty() {
+		return code
+	}
+	if action == "verify" {
+		fmt.Println("\nRun \"devpathpro project configure\" to configure the environment.")
+		return exitUnmet
+	}
+
+	changes, err := combined.Diff(store)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error computing changes: %v\n", err)
+		return exitError
+	}
+	fmt.Println()
+	plan.PrintDiff(os.Stdout, changes)
+
+	if combined.RequiresAdmin() && !registry.IsAdmin() {
+		fmt.Fprintln(os.Stderr, "\nThis configuration changes machine variables, which requires administrator privileges")
+		return exitError
+	}
+	if !approve(*yes, "\nApply these changes? (y/n): ") {
+		return exitAborted
+	}
+
+	if _, err := combined.CreateBackup(store, "project"); err != nil {
+		fmt.Printf("Warning: Failed to create backup: %v\n", err)
+	}
+	if err := combined.Apply(store); err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+	fmt.Println("✅ Project tools configured successfully")
+	return code
+}
+
+// projectReport converts the results of checking a manifest to a report
+func projectReport(m *manifest.Manifest, results []manifest.Result) report.ProjectReport {
+	r := report.ProjectReport{
+		Header:       report.NewHeader("project"),
+		Manifest:     m.Path,
+		Met:          true,
+		Requirements: []report.Requirement{},
+	}
+	for _, result := range results {
+		r.Requirements = append(r.Requirements, report.Requirement{
+			Tool:       result.Tool,
+			Constraint: result.Requirement.Version,
+			Status:     string(result.Status),
+			Path:       result.Path,
+			Version:    result.Version,
+			Message:    result.Message,
+			Changes:    result.Changes,
+		})
+		if result.Status != manifest.StatusOK {
+			r.Met = false
+		}
+	}
+	return r
+}
+
+// printProjectResults prints one line per required tool
+func printProjectResults(results []manifest.Result) {
+	for _, result := range results {
+		icon := "✅"
+		switch result.Status {
+		case manifest.StatusUnconfigured:
+			icon = "⚠️"
+		case manifest.StatusMissing, manifest.StatusWrongVersion:
+			icon = "❌"
+		}
+
+		line := fmt.Sprintf("%s %s", icon, result.Tool)
+		if result.Requirement.Version != "" {
+			line += " " + result.Requirement.Version
+		}
+		if result.Path != "" {
+			line += ": " + result.Path
+			if result.Version != "" {
+				line += " (" + result.Version + ")"
+			}
+		}
+		fmt.Printf("%s - %s\n", line, result.Message)
+	}
+}
@@ -0,0 +1,174 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"devpathpro/pkg/config"
+	"devpathpro/pkg/registry"
+	"devpathpro/pkg/report"
+	"devpathpro/pkg/shell"
+)
+
+const shellUsage = `usage: devpathpro shell status [-output table|json|yaml]
+       devpathpro shell sync [-dry-run]
+       devpathpro shell remove [-dry-run]`
+
+// runShell shows, rewrites or removes the managed blocks in shell startup files
+func runShell(cfg *config.Configuration, store registry.EnvStore, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, shellUsage)
+		return exitUsage
+	}
+	fs := flag.NewFlagSet("shell "+args[0], flag.ContinueOnError)
+	var output *string
+	var dryRun *bool
+	switch args[0] {
+	case "status":
+		output = outputFlag(fs)
+	case "sync", "remove":
+		dryRun = fs.Bool("dry-run", false, "Show the files that would change without writing them")
+	default:
+		fmt.Fprintf(os.Stderr, "unknown shell command %q\n", args[0])
+		fmt.Fprintln(os.Stderr, shellUsage)
+		return exitUsage
+	}
+	positional, err := parseFlags(fs, args[1:])
+	if err != nil {
+		return exitUsage
+	}
+	if len(positional) != 0 {
+		fmt.Fprintln(os.Stderr, shellUsage)
+		return exitUsage
+	}
+
+	if args[0] == "remove" && len(config.KnownShellProfiles()) > 0 {
+		return runShellRemove(*dryRun)
+	}
+	if len(config.ShellProfiles()) == 0 {
+		fmt.Println("No shell profiles are configured; list shells under \"shells\" in the settings.")
+		return exitOK
+	}
+	if args[0] == "status" {
+		return runShellStatus(cfg, store, *output)
+	}
+	return runShellSync(cfg, store, *dryRun)
+}
+
+// runShellStatus compares the configured startup files with the store
+func runShellStatus(cfg *config.Configuration, store registry.EnvStore, output string) int {
+	format, err := report.ParseFormat(output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+	env, err := config.ShellEnv(store, cfg.Programs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+
+	status := report.ShellReport{Header: report.NewHeader("shell"), Profiles: []report.ShellProfile{}}
+	code := exitOK
+	for _, profile := range config.ShellProfiles() {
+		state, err := config.ShellProfileState(profile, env)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return exitError
+		}
+		if state != config.ShellInSync {
+			code = exitUnmet
+		}
+		status.Profiles = append(status.Profiles, report.ShellProfile{
+			Path:      profile.Path,
+			Shell:     string(profile.Kind),
+			PathStyle: string(profile.Style),
+			State:     state,
+		})
+	}
+
+	if format != report.FormatTable {
+		if rc := writeReport(format, status); rc != exitOK {
+			return rc
+		}
+		return code
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SHELL\tSTATE\tPROFILE")
+	for _, p := range status.Profiles {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", p.Shell, p.State, p.Path)
+	}
+	w.Flush()
+	return code
+}
+
+// runShellSync rewrites the managed block of every configured startup file
+func runShellSync(cfg *config.Configuration, store registry.EnvStore, dryRun bool) int {
+	env, err := config.ShellEnv(store, cfg.Programs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+	if dryRun {
+		for _, profile := range config.ShellProfiles() {
+			state, err := config.ShellProfileState(profile, env)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				return exitError
+			}
+			if state != config.ShellInSync {
+				fmt.Printf("~ %s (%s)\n", profile.Path, state)
+			}
+		}
+		return exitOK
+	}
+
+	changed, err := config.SyncShells(store, cfg.Programs)
+	for _, path := range changed {
+		fmt.Printf("✅ Updated %s\n", path)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+	if len(changed) == 0 {
+		fmt.Println("Shell profiles are up to date.")
+	}
+	return exitOK
+}
+
+// runShellRemove removes the managed block from every startup file that may have one
+func runShellRemove(dryRun bool) int {
+	profiles := config.KnownShellProfiles()
+	if dryRun {
+		for _, profile := range profiles {
+			state, err := config.ShellProfileState(profile, shell.Env{})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+				return exitError
+			}
+			if state != config.ShellInSync {
+				fmt.Printf("- %s\n", profile.Path)
+			}
+		}
+		return exitOK
+	}
+
+	changed, err := config.RemoveShellBlocks(profiles)
+	for _, path := range changed {
+		fmt.Printf("✅ Removed the DevPathPro block from %s\n", path)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return exitError
+	}
+	if len(changed) == 0 {
+		fmt.Println("No shell profile has a DevPathPro block.")
+	}
+	if len(config.ShellProfiles()) > 0 {
+		fmt.Println("Remove \"shells\" and \"shellProfiles\" from the settings, or the blocks are written again on the next change.")
+	}
+	return exitOK
+}
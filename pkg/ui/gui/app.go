@@ -12,6 +12,7 @@ import (
 	"fyne.io/fyne/v2/widget"
 
 	"devpathpro/pkg/config"
+	"devpathpro/pkg/registry"
 	"devpathpro/pkg/tools"
 )
 
@@ -19,19 +20,19 @@ import (
 type DevPathProGUI struct {
 	window       fyne.Window
 	config       *config.Configuration
+	store        registry.EnvStore
 	tabContainer *container.AppTabs
 }
 
 // NewDevPathProGUI creates a new instance of the GUI application
-func NewDevPathProGUI() *DevPathProGUI {
+func NewDevPathProGUI(cfg *config.Configuration, store registry.EnvStore) *DevPathProGUI {
 	a := app.New()
 	window := a.NewWindow("DevPathPro")
 
 	gui := &DevPathProGUI{
 		window: window,
-		config: &config.Configuration{
-			Programs: config.GetDefaultPrograms(),
-		},
+		store:  store,
+		config: cfg,
 	}
 
 	gui.setupUI()
@@ -56,12 +57,14 @@ func (gui *DevPathProGUI) setupTabs() {
 	verifyTab := gui.createVerifyTab()
 	settingsTab := gui.createSettingsTab()
 	environmentTab := gui.createEnvironmentTab()
+	backupsTab := gui.createBackupsTab()
 
 	gui.tabContainer = container.NewAppTabs(
 		toolsTab,
 		verifyTab,
 		settingsTab,
 		environmentTab,
+		backupsTab,
 	)
 }
 
@@ -112,8 +115,8 @@ func (gui *DevPathProGUI) createToolsTab() *container.TabItem {
 	// Создаем скролируемый контейнер
 	scroll := container.NewVScroll(mainContainer)
 
-	// Создаем основной контейнер с кнопкой внизу
-	content := container.NewBorder(nil, searchBtn, nil, nil, scroll)
+	// Создаем основной контейнер с панелью профилей вверху и кнопкой внизу
+	content := container.NewBorder(newProfilesBar(gui.window, gui.config, gui.store), searchBtn, nil, nil, scroll)
 
 	return container.NewTabItem("Tools", content)
 }
@@ -132,7 +135,7 @@ func (gui *DevPathProGUI) createVerifyTab() *container.TabItem {
 	// Create fix button
 	fixBtn := widget.NewButton("Fix Issues", func() {
 		issues := config.VerifyConfigurations()
-		if err := config.FixConfigurationIssues(issues); err != nil {
+		if err := config.FixConfigurationIssues(gui.store, issues); err != nil {
 			gui.showError("Error fixing issues", err)
 		} else {
 			gui.showSuccess("Issues fixed successfully")
@@ -147,12 +150,12 @@ func (gui *DevPathProGUI) createVerifyTab() *container.TabItem {
 
 // createSettingsTab creates the settings management tab
 func (gui *DevPathProGUI) createSettingsTab() *container.TabItem {
-	// Create settings form
-	form := widget.NewForm()
-
-	// Add settings fields here
+	return container.NewTabItem("Settings", newSettingsView(gui.window, gui.config))
+}
 
-	return container.NewTabItem("Settings", form)
+// createBackupsTab creates the backup catalog and compare tab
+func (gui *DevPathProGUI) createBackupsTab() *container.TabItem {
+	return container.NewTabItem("Backups", newBackupsView(gui.window, gui.store))
 }
 
 // createEnvironmentTab creates the environment variables tab
@@ -194,7 +197,7 @@ func (gui *DevPathProGUI) createEnvironmentTab() *container.TabItem {
 			go func(p config.Program, l *widget.Label) {
 				paths := tools.FindProgram(p)
 				if len(paths) > 0 {
-					l.SetText(filepath.Dir(filepath.Dir(paths[0])))
+					l.SetText(tools.DescribePath(filepath.Dir(filepath.Dir(paths[0])), tools.DetectVersion(p, paths[0])))
 				} else {
 					l.SetText("Not found")
 				}
@@ -216,7 +219,10 @@ func (gui *DevPathProGUI) createEnvironmentTab() *container.TabItem {
 	// Создаем основной контейнер с кнопкой вверху
 	content := container.NewBorder(refreshBtn, nil, nil, nil, scroll)
 
-	return container.NewTabItem("Environment", content)
+	return container.NewTabItem("Environment", container.NewAppTabs(
+		container.NewTabItem("Variables", content),
+		container.NewTabItem("PATH", newPathView(gui.window, gui.store, gui.config.Programs)),
+	))
 }
 
 // processSelectedTools processes the selected tools
@@ -254,102 +260,7 @@ func (gui *DevPathProGUI) showSuccess(message string) {
 }
 
 func (gui *DevPathProGUI) configureTool(prog config.Program) {
-	paths := tools.FindProgram(prog)
-	if len(paths) == 0 {
-		dialog.ShowInformation("Search",
-			fmt.Sprintf("%s not found in standard locations. Would you like to perform a deep search?", prog.Name),
-			gui.window)
-		return
-	}
-
-	// Create path selection dialog
-	var selectedPath string
-	pathOptions := widget.NewRadioGroup(paths, func(value string) {
-		selectedPath = value
-	})
-
-	configDialog := dialog.NewCustom(
-		fmt.Sprintf("Configure %s", prog.Name),
-		"Configure",
-		container.NewVBox(
-			widget.NewLabel("Select installation path:"),
-			pathOptions,
-		),
-		gui.window,
-	)
-
-	configDialog.SetOnClosed(func() {
-		if selectedPath == "" {
-			return
-		}
-
-		// Get available configuration options
-		options := tools.GetConfigOptions(prog)
-		if len(options) == 0 {
-			// If no special options, just configure
-			if err := tools.ConfigureSelectedPath(prog, selectedPath); err != nil {
-				dialog.ShowError(err, gui.window)
-			} else {
-				dialog.ShowInformation("Success",
-					fmt.Sprintf("%s configured successfully", prog.Name),
-					gui.window)
-			}
-			return
-		}
-
-		// Create options selection dialog
-		var selectedVars []string
-		optionsContainer := container.NewVBox()
-
-		for _, opt := range options {
-			check := widget.NewCheck(opt.Name, func(checked bool) {
-				if checked {
-					selectedVars = append(selectedVars, opt.Variables...)
-				} else {
-					// Remove variables from selected
-					newVars := make([]string, 0)
-					for _, v := range selectedVars {
-						found := false
-						for _, ov := range opt.Variables {
-							if v == ov {
-								found = true
-								break
-							}
-						}
-						if !found {
-							newVars = append(newVars, v)
-						}
-					}
-					selectedVars = newVars
-				}
-			})
-			optionsContainer.Add(container.NewHBox(
-				check,
-				widget.NewLabel(opt.Description),
-			))
-		}
-
-		optionsDialog := dialog.NewCustom(
-			"Select Configuration Options",
-			"Apply",
-			optionsContainer,
-			gui.window,
-		)
-
-		optionsDialog.SetOnClosed(func() {
-			if err := tools.ConfigureSelectedPath(prog, selectedPath); err != nil {
-				dialog.ShowError(err, gui.window)
-			} else {
-				dialog.ShowInformation("Success",
-					fmt.Sprintf("%s configured successfully", prog.Name),
-					gui.window)
-			}
-		})
-
-		optionsDialog.Show()
-	})
-
-	configDialog.Show()
+	showConfigureDialog(gui.window, gui.store, "", prog, gui.config.Settings.DefaultOptionsFor(prog.Name), nil)
 }
 
 func (gui *DevPathProGUI) performDeepSearch() {
@@ -365,7 +276,7 @@ func (gui *DevPathProGUI) performDeepSearch() {
 	)
 
 	go func() {
-		results := tools.ProcessToolsDeepSearch(gui.config.Programs)
+		results := tools.ProcessToolsDeepSearch(gui.store, "", gui.config.Programs)
 		gui.window.Content().Refresh()
 		progressDialog.Hide()
 
@@ -375,7 +286,7 @@ func (gui *DevPathProGUI) performDeepSearch() {
 			if result.Found {
 				text += fmt.Sprintf("✅ %s found:\n", result.Program.Name)
 				for _, path := range result.Paths {
-					text += fmt.Sprintf("  - %s\n", path)
+					text += fmt.Sprintf("  - %s\n", tools.DescribePath(path, result.Versions[path]))
 				}
 			} else {
 				text += fmt.Sprintf("❌ %s not found\n", result.Program.Name)
@@ -0,0 +1,89 @@
+package gui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"devpathpro/pkg/backup"
+	"devpathpro/pkg/registry"
+)
+
+// newBackupsView lists the backup catalog and compares two backups, or a
+// backup with the current environment. Both GUI front ends use it.
+func newBackupsView(window fyne.Window, store registry.EnvStore) fyne.CanvasObject {
+	var backups []backup.Info
+
+	list := widget.NewList(
+		func() int { return len(backups) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			item.(*widget.Label).SetText(backups[id].String())
+		},
+	)
+	fromSelect := widget.NewSelect(nil, nil)
+	toSelect := widget.NewSelect(nil, nil)
+	result := widget.NewTextGrid()
+
+	refresh := func() {
+		// A missing backup directory just means there are no backups yet
+		backups, _ = backup.ListBackups()
+		ids := make([]string, len(backups))
+		for i, info := range backups {
+			ids[i] = info.ID
+		}
+		fromSelect.Options = ids
+		fromSelect.Refresh()
+		toSelect.Options = append([]string{backup.Current}, ids...)
+		toSelect.Refresh()
+		list.Refresh()
+	}
+
+	// Selecting a backup compares it with the current environment by default
+	list.OnSelected = func(id widget.ListItemID) {
+		fromSelect.SetSelected(backups[id].ID)
+		if toSelect.Selected == "" {
+			toSelect.SetSelected(backup.Current)
+		}
+	}
+
+	compareBtn := widget.NewButton("Compare", func() {
+		if fromSelect.Selected == "" || toSelect.Selected == "" {
+			dialog.ShowInformation("Compare", "Select two backups to compare.", window)
+			return
+		}
+		changes, err := backup.Compare(store, fromSelect.Selected, toSelect.Selected)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		result.SetText(backup.FormatChanges(changes))
+	})
+
+	createBtn := widget.NewButton("Create Backup", func() {
+		info, err := backup.CreateBackup(store, backup.Metadata{Action: "manual"})
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		refresh()
+		dialog.ShowInformation("Backup", fmt.Sprintf("Backup %s created", info.ID), window)
+	})
+	refreshBtn := widget.NewButton("Refresh", refresh)
+
+	refresh()
+
+	controls := container.NewVBox(
+		container.NewHBox(createBtn, refreshBtn),
+		widget.NewForm(
+			widget.NewFormItem("From", fromSelect),
+			widget.NewFormItem("To", toSelect),
+		),
+		compareBtn,
+	)
+	return container.NewBorder(controls, nil, nil, nil,
+		container.NewVSplit(list, container.NewScroll(result)))
+}
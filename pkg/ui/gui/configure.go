@@ -0,0 +1,191 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"devpathpro/pkg/config"
+	"devpathpro/pkg/plan"
+	"devpathpro/pkg/registry"
+	"devpathpro/pkg/tools"
+	"devpathpro/pkg/utils"
+)
+
+// scopeOptions are the write scopes offered in the configure dialog
+var scopeOptions = []string{
+	string(registry.ScopeUser),
+	string(registry.ScopeMachine),
+	string(registry.ScopeBoth),
+}
+
+// showConfigureDialog lets the user pick an installation path, a scope and
+// configuration options for a program, then configures it in the store.
+// The option groups named in defaults are preselected.
+// onSuccess is called after the program has been configured.
+func showConfigureDialog(window fyne.Window, store registry.EnvStore, scope registry.Scope, prog config.Program, defaults []string, onSuccess func()) {
+	paths := tools.FindProgram(prog)
+	if len(paths) == 0 {
+		dialog.ShowInformation("Search",
+			fmt.Sprintf("%s not found in standard locations. Would you like to perform a deep search?", prog.Name),
+			window)
+		return
+	}
+
+	// Create path selection dialog, labelling each path with its version
+	versions := tools.DetectVersions(prog, paths)
+	labels := make([]string, len(paths))
+	pathByLabel := make(map[string]string)
+	for i, path := range paths {
+		labels[i] = tools.DescribePath(path, versions[path])
+		pathByLabel[labels[i]] = path
+	}
+	var selectedPath string
+	pathOptions := widget.NewRadioGroup(labels, func(value string) {
+		selectedPath = pathByLabel[value]
+	})
+
+	// Preselect the installation the selection policy picks and say why
+	explanation := widget.NewLabel("")
+	explanation.Wrapping = fyne.TextWrapWord
+	if selection, err := tools.ChooseInstallation(prog, paths, versions); err != nil {
+		explanation.SetText(err.Error())
+	} else {
+		pathOptions.SetSelected(tools.DescribePath(selection.Path, selection.Version))
+		explanation.SetText(selection.Explain())
+	}
+
+	selectedScope := tools.ResolveScope(prog, scope)
+	scopeSelect := widget.NewSelect(scopeOptions, func(value string) {
+		selectedScope = registry.Scope(value)
+	})
+	scopeSelect.SetSelected(string(selectedScope))
+
+	configDialog := dialog.NewCustom(
+		fmt.Sprintf("Configure %s", prog.Name),
+		"Configure",
+		container.NewVBox(
+			widget.NewLabel("Select installation path:"),
+			pathOptions,
+			explanation,
+			widget.NewLabel("Write variables to:"),
+			scopeSelect,
+		),
+		window,
+	)
+
+	configure := func(selectedVars []string) {
+		// Machine scope writes need elevation, user scope writes do not
+		if selectedScope.RequiresAdmin() && !registry.IsAdmin() {
+			dialog.ShowInformation("Administrator Rights Required",
+				fmt.Sprintf("Writing to the %s scope requires administrator privileges.\nRestart the program as administrator or choose the user scope.", selectedScope),
+				window)
+			return
+		}
+
+		p := tools.BuildPlan(selectedScope, prog, selectedPath, selectedVars)
+		showPreviewDialog(window, store, p, "configure", func() {
+			dialog.ShowInformation("Success",
+				fmt.Sprintf("%s configured successfully", prog.Name),
+				window)
+			if onSuccess != nil {
+				onSuccess()
+			}
+		})
+	}
+
+	configDialog.SetOnClosed(func() {
+		if selectedPath == "" {
+			return
+		}
+
+		// Get available configuration options
+		options := tools.GetConfigOptions(prog)
+		if len(options) == 0 {
+			// If no special options, just configure
+			configure(nil)
+			return
+		}
+
+		// Create options selection dialog
+		selected := make([]bool, len(options))
+		optionsContainer := container.NewVBox()
+
+		for i, opt := range options {
+			index := i
+			check := widget.NewCheck(opt.Name, func(checked bool) {
+				selected[index] = checked
+			})
+			for _, name := range defaults {
+				if strings.EqualFold(name, opt.Name) {
+					check.SetChecked(true)
+				}
+			}
+			optionsContainer.Add(container.NewHBox(
+				check,
+				widget.NewLabel(opt.Description),
+			))
+		}
+
+		optionsDialog := dialog.NewCustom(
+			"Select Configuration Options",
+			"Apply",
+			optionsContainer,
+			window,
+		)
+
+		optionsDialog.SetOnClosed(func() {
+			var selectedVars []string
+			for i, opt := range options {
+				if selected[i] {
+					selectedVars = append(selectedVars, opt.Variables...)
+				}
+			}
+			configure(selectedVars)
+		})
+
+		optionsDialog.Show()
+	})
+
+	configDialog.Show()
+}
+
+// showPreviewDialog shows the changes a plan would make against the current
+// values in the store and applies the plan once the user confirms, after a
+// backup recording action. onApplied is called after the plan has been applied.
+func showPreviewDialog(window fyne.Window, store registry.EnvStore, p *plan.ChangePlan, action string, onApplied func()) {
+	changes, err := p.Diff(store)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("error computing changes: %v", err), window)
+		return
+	}
+
+	diff := widget.NewTextGridFromString(plan.FormatDiff(changes))
+	scroll := container.NewScroll(diff)
+	scroll.SetMinSize(fyne.NewSize(600, 300))
+
+	if plan.CountChanged(changes) == 0 {
+		dialog.ShowCustom("Preview Changes", "Close", scroll, window)
+		return
+	}
+
+	dialog.ShowCustomConfirm("Preview Changes", "Apply", "Cancel", scroll, func(apply bool) {
+		if !apply {
+			return
+		}
+		if _, err := p.CreateBackup(store, action); err != nil {
+			utils.Warnf("backup before %s of %v failed: %v", action, p.Tools, err)
+		}
+		if err := p.Apply(store); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		if onApplied != nil {
+			onApplied()
+		}
+	}, window)
+}
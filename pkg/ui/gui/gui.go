@@ -23,34 +23,23 @@ type GUI struct {
 	app    fyne.App
 	window fyne.Window
 	config *config.Configuration
+	store  registry.EnvStore
+	scope  registry.Scope
 }
 
-func NewGUI() *GUI {
+// NewGUI creates the main window application. An empty scope
+// preselects each tool's default scope when configuring.
+func NewGUI(cfg *config.Configuration, store registry.EnvStore, scope registry.Scope) *GUI {
 	return &GUI{
-		app: app.New(),
-		config: &config.Configuration{
-			LogFile:  "devpathpro.log",
-			Programs: config.GetDefaultPrograms(),
-		},
+		app:    app.New(),
+		store:  store,
+		scope:  scope,
+		config: cfg,
 	}
 }
 
 func (g *GUI) Run() {
-	// Создаем временное окно для проверки прав
-	tempWindow := g.app.NewWindow("DevPathPro")
-	
-	// Проверяем права администратора
-	if !registry.IsAdmin() {
-		dialog.ShowInformation("Administrator Rights Required", 
-			"This program requires administrator privileges to modify system environment variables.\nPlease restart the program as administrator.", 
-			tempWindow)
-		tempWindow.Show()
-		g.app.Run()
-		return
-	}
-	
-	// Закрываем временное окно
-	tempWindow.Close()
+	// Права администратора проверяются только при записи в machine scope
 
 	// Создаем основное окно с заданным размером
 	g.window = g.app.NewWindow("DevPathPro")
@@ -61,6 +50,8 @@ func (g *GUI) Run() {
 		container.NewTabItem("Tools", g.createToolsTab()),
 		container.NewTabItem("Verify", g.createVerifyTab()),
 		container.NewTabItem("Environment", g.createEnvironmentTab()),
+		container.NewTabItem("Backups", newBackupsView(g.window, g.store)),
+		container.NewTabItem("Settings", newSettingsView(g.window, g.config)),
 	)
 
 	tabs.SetTabLocation(container.TabLocationTop)
@@ -131,8 +122,8 @@ func (g *GUI) createToolsTab() fyne.CanvasObject {
 	// Создаем скролируемый контейнер
 	scroll := container.NewVScroll(mainContainer)
 
-	// Создаем основной контейнер с кнопкой внизу
-	content := container.NewBorder(nil, searchBtn, nil, nil, scroll)
+	// Создаем основной контейнер с панелью профилей вверху и кнопкой внизу
+	content := container.NewBorder(newProfilesBar(g.window, g.config, g.store), searchBtn, nil, nil, scroll)
 
 	return content
 }
@@ -198,7 +189,7 @@ func (g *GUI) createVerifyTab() fyne.CanvasObject {
 				case <-done:
 					return
 				default:
-					if err := config.FixConfigurationIssues(issues); err != nil {
+					if err := config.FixConfigurationIssues(g.store, issues); err != nil {
 						dialog.ShowError(fmt.Errorf("Fix error: %v", err), g.window)
 					} else {
 						dialog.ShowInformation("Success", "Issues fixed successfully!", g.window)
@@ -338,8 +329,8 @@ func (g *GUI) createEnvironmentTab() fyne.CanvasObject {
 						// Если переменная окружения установлена, показываем её
 						l.SetText(envValue)
 					} else {
-						// Иначе показываем найденный путь
-						l.SetText(path)
+						// Иначе показываем найденный путь и его версию
+						l.SetText(tools.DescribePath(path, tools.DetectVersion(p, paths[0])))
 					}
 				} else {
 					l.SetText("Not found")
@@ -385,7 +376,10 @@ func (g *GUI) createEnvironmentTab() fyne.CanvasObject {
 	// Создаем основной контейнер
 	content := container.NewBorder(topContainer, nil, nil, nil, scroll)
 
-	return content
+	return container.NewAppTabs(
+		container.NewTabItem("Variables", content),
+		container.NewTabItem("PATH", newPathView(g.window, g.store, g.config.Programs)),
+	)
 }
 
 // showRestartDialog показывает диалог с предложением перезагрузить компьютер
@@ -406,104 +400,7 @@ func (g *GUI) showRestartDialog() {
 }
 
 func (g *GUI) configureTool(prog config.Program) {
-	paths := tools.FindProgram(prog)
-	if len(paths) == 0 {
-		dialog.ShowInformation("Search",
-			fmt.Sprintf("%s not found in standard locations. Would you like to perform a deep search?", prog.Name),
-			g.window)
-		return
-	}
-
-	// Create path selection dialog
-	var selectedPath string
-	pathOptions := widget.NewRadioGroup(paths, func(value string) {
-		selectedPath = value
-	})
-
-	configDialog := dialog.NewCustom(
-		fmt.Sprintf("Configure %s", prog.Name),
-		"Configure",
-		container.NewVBox(
-			widget.NewLabel("Select installation path:"),
-			pathOptions,
-		),
-		g.window,
-	)
-
-	configDialog.SetOnClosed(func() {
-		if selectedPath == "" {
-			return
-		}
-
-		// Get available configuration options
-		options := tools.GetConfigOptions(prog)
-		if len(options) == 0 {
-			// If no special options, just configure
-			if err := tools.ConfigureSelectedPath(prog, selectedPath); err != nil {
-				dialog.ShowError(err, g.window)
-			} else {
-				dialog.ShowInformation("Success",
-					fmt.Sprintf("%s configured successfully", prog.Name),
-					g.window)
-				g.showRestartDialog()
-			}
-			return
-		}
-
-		// Create options selection dialog
-		var selectedVars []string
-		optionsContainer := container.NewVBox()
-
-		for _, opt := range options {
-			check := widget.NewCheck(opt.Name, func(checked bool) {
-				if checked {
-					selectedVars = append(selectedVars, opt.Variables...)
-				} else {
-					// Remove variables from selected
-					newVars := make([]string, 0)
-					for _, v := range selectedVars {
-						found := false
-						for _, ov := range opt.Variables {
-							if v == ov {
-								found = true
-								break
-							}
-						}
-						if !found {
-							newVars = append(newVars, v)
-						}
-					}
-					selectedVars = newVars
-				}
-			})
-			optionsContainer.Add(container.NewHBox(
-				check,
-				widget.NewLabel(opt.Description),
-			))
-		}
-
-		optionsDialog := dialog.NewCustom(
-			"Select Configuration Options",
-			"Apply",
-			optionsContainer,
-			g.window,
-		)
-
-		optionsDialog.SetOnClosed(func() {
-			if err := tools.ConfigureSelectedPath(prog, selectedPath); err != nil {
-				dialog.ShowError(err, g.window)
-			} else {
-				dialog.ShowInformation("Success",
-					fmt.Sprintf("%s configured successfully", prog.Name),
-					g.window)
-				g.showRestartDialog()
-			}
-		})
-
-		optionsDialog.Show()
-	})
-
-	configDialog.Show()
+	showConfigureDialog(g.window, g.store, g.scope, prog, g.config.Settings.DefaultOptionsFor(prog.Name), g.showRestartDialog)
 }
 
 func (g *GUI) performDeepSearch() {
@@ -519,7 +416,7 @@ func (g *GUI) performDeepSearch() {
 	)
 
 	go func() {
-		results := tools.ProcessToolsDeepSearch(g.config.Programs)
+		results := tools.ProcessToolsDeepSearch(g.store, g.scope, g.config.Programs)
 		g.window.Content().Refresh()
 		progressDialog.Hide()
 
@@ -529,7 +426,7 @@ func (g *GUI) performDeepSearch() {
 			if result.Found {
 				text += fmt.Sprintf("✅ %s found:\n", result.Program.Name)
 				for _, path := range result.Paths {
-					text += fmt.Sprintf("  - %s\n", path)
+					text += fmt.Sprintf("  - %s\n", tools.DescribePath(path, result.Versions[path]))
 				}
 			} else {
 				text += fmt.Sprintf("❌ %s not found\n", result.Program.Name)
@@ -0,0 +1,206 @@
+package gui
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"devpathpro/pkg/config"
+	"devpathpro/pkg/pathedit"
+	"devpathpro/pkg/registry"
+)
+
+// pathRow is a PATH entry in the list that can be dragged to a new position
+type pathRow struct {
+	widget.Label
+	index   int
+	dragged float32
+	// onDrag reports how many rows the entry is being dragged by
+	onDrag func(index, rows int)
+	onDrop func(from, to int)
+}
+
+func newPathRow(onDrag func(index, rows int), onDrop func(from, to int)) *pathRow {
+	row := &pathRow{onDrag: onDrag, onDrop: onDrop}
+	row.ExtendBaseWidget(row)
+	return row
+}
+
+// Dragged accumulates the vertical movement of the pointer
+func (r *pathRow) Dragged(e *fyne.DragEvent) {
+	r.dragged += e.Dragged.DY
+	r.onDrag(r.index, r.rows())
+}
+
+// DragEnd moves the entry by the number of rows it was dragged over
+func (r *pathRow) DragEnd() {
+	rows := r.rows()
+	r.dragged = 0
+	r.onDrop(r.index, r.index+rows)
+}
+
+// rows converts the dragged distance to a number of list rows
+func (r *pathRow) rows() int {
+	height := r.Size().Height + theme.SeparatorThicknessSize()
+	if height <= 0 {
+		return 0
+	}
+	return int(math.Round(float64(r.dragged / height)))
+}
+
+// newPathView edits the PATH of the user or machine scope: entries can be
+// dragged or moved up and down, removed, deduplicated and have their %VAR%
+// references expanded or restored. Changes are previewed before they are
+// written. Both GUI front ends use it.
+func newPathView(window fyne.Window, store registry.EnvStore, programs []config.Program) fyne.CanvasObject {
+	var ed *pathedit.Editor
+	var entries []pathedit.Entry
+	selected := -1
+
+	hint := widget.NewLabel("Drag entries to reorder them")
+	var list *widget.List
+
+	refresh := func() {
+		entries = ed.Describe(programs)
+		list.Refresh()
+	}
+	move := func(from, to int) {
+		hint.SetText("Drag entries to reorder them")
+		if to < 0 {
+			to = 0
+		}
+		if to >= len(entries) {
+			to = len(entries) - 1
+		}
+		if from == to || ed.Move(from, to) != nil {
+			return
+		}
+		refresh()
+		list.Select(to)
+	}
+
+	list = widget.NewList(
+		func() int { return len(entries) },
+		func() fyne.CanvasObject {
+			return newPathRow(func(index, rows int) {
+				hint.SetText(fmt.Sprintf("Move %d to position %d", index+1, index+rows+1))
+			}, move)
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			row := item.(*pathRow)
+			row.index = id
+			entry := entries[id]
+			text := fmt.Sprintf("%d. %s", entry.Position, entry.Value)
+			if entry.Owner != "" {
+				text += fmt.Sprintf("  [%s]", entry.Owner)
+			}
+			if notes := entry.Notes(); len(notes) > 0 {
+				text += "  (" + strings.Join(notes, ", ") + ")"
+			}
+			row.SetText(text)
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) { selected = id }
+	list.OnUnselected = func(widget.ListItemID) { selected = -1 }
+
+	load := func(scope registry.Scope) {
+		e, err := pathedit.Open(store, scope)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		ed = e
+		selected = -1
+		list.UnselectAll()
+		refresh()
+	}
+
+	scopeSelect := widget.NewSelect([]string{string(registry.ScopeUser), string(registry.ScopeMachine)}, func(value string) {
+		load(registry.Scope(value))
+	})
+
+	withSelection := func(fn func(index int)) func() {
+		return func() {
+			if ed == nil {
+				return
+			}
+			if selected < 0 || selected >= len(entries) {
+				dialog.ShowInformation("PATH", "Select an entry first.", window)
+				return
+			}
+			fn(selected)
+		}
+	}
+
+	upBtn := widget.NewButtonWithIcon("", theme.MoveUpIcon(), withSelection(func(i int) { move(i, i-1) }))
+	downBtn := widget.NewButtonWithIcon("", theme.MoveDownIcon(), withSelection(func(i int) { move(i, i+1) }))
+	removeBtn := widget.NewButtonWithIcon("Remove", theme.DeleteIcon(), withSelection(func(i int) {
+		if _, err := ed.Remove(entries[i].Value); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		list.UnselectAll()
+		refresh()
+	}))
+	dedupeBtn := widget.NewButton("Remove Duplicates", func() {
+		if ed == nil {
+			return
+		}
+		ed.Dedupe()
+		list.UnselectAll()
+		refresh()
+	})
+	expandBtn := widget.NewButton("Expand %VAR%", func() {
+		if ed == nil {
+			return
+		}
+		ed.Expand()
+		refresh()
+	})
+	unexpandBtn := widget.NewButton("Use %VAR%", func() {
+		if ed == nil {
+			return
+		}
+		ed.Unexpand(ed.UnexpandVars(programs))
+		refresh()
+	})
+	reloadBtn := widget.NewButtonWithIcon("Reload", theme.ViewRefreshIcon(), func() {
+		load(registry.Scope(scopeSelect.Selected))
+	})
+
+	applyBtn := widget.NewButtonWithIcon("Apply", theme.ConfirmIcon(), func() {
+		if ed == nil {
+			return
+		}
+		preview := widget.NewTextGridFromString(ed.Preview())
+		scroll := container.NewScroll(preview)
+		scroll.SetMinSize(fyne.NewSize(600, 300))
+		if !ed.Changed() {
+			dialog.ShowCustom("Preview Changes", "Close", scroll, window)
+			return
+		}
+		dialog.ShowCustomConfirm("Preview Changes", "Apply", "Cancel", scroll, func(apply bool) {
+			if !apply {
+				return
+			}
+			if err := ed.Apply(); err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			refresh()
+			dialog.ShowInformation("PATH", fmt.Sprintf("The %s PATH was updated.", ed.Scope()), window)
+		}, window)
+	})
+
+	scopeSelect.SetSelected(string(registry.ScopeUser))
+
+	top := container.NewHBox(widget.NewLabel("Scope:"), scopeSelect, reloadBtn, hint)
+	bottom := container.NewHBox(upBtn, downBtn, removeBtn, dedupeBtn, expandBtn, unexpandBtn, applyBtn)
+	return container.NewBorder(top, bottom, nil, nil, list)
+}
@@ -0,0 +1,117 @@
+package gui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"devpathpro/pkg/config"
+	"devpathpro/pkg/profile"
+	"devpathpro/pkg/registry"
+)
+
+// newProfilesBar picks a saved profile and switches the environment to it,
+// saves the configured tools as a profile or deletes one. The profile the
+// environment matches is preselected. Both GUI front ends use it.
+func newProfilesBar(window fyne.Window, cfg *config.Configuration, store registry.EnvStore) fyne.CanvasObject {
+	profileSelect := widget.NewSelect(nil, nil)
+	profileSelect.PlaceHolder = "No profile"
+
+	refresh := func() {
+		profiles := cfg.Profiles()
+		names := make([]string, len(profiles))
+		active := ""
+		for i, p := range profiles {
+			names[i] = p.Name
+			if switchPlan, err := profile.SwitchPlan(store, cfg.Programs, p); err == nil && switchPlan.Empty() && active == "" {
+				active = p.Name
+			}
+		}
+		profileSelect.Options = names
+		profileSelect.ClearSelected()
+		if active != "" {
+			profileSelect.SetSelected(active)
+		}
+		profileSelect.Refresh()
+	}
+
+	switchBtn := widget.NewButton("Switch", func() {
+		p, ok := config.FindProfile(cfg.Profiles(), profileSelect.Selected)
+		if !ok {
+			dialog.ShowInformation("Switch Profile", "Select a profile to switch to.", window)
+			return
+		}
+		switchPlan, err := profile.SwitchPlan(store, cfg.Programs, p)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		if switchPlan.Empty() {
+			dialog.ShowInformation("Switch Profile", fmt.Sprintf("The environment already matches profile %s.", p.Name), window)
+			return
+		}
+		if switchPlan.RequiresAdmin() && !registry.IsAdmin() {
+			dialog.ShowInformation("Administrator Rights Required",
+				fmt.Sprintf("Profile %s changes machine variables.\nRestart the program as administrator to switch to it.", p.Name),
+				window)
+			return
+		}
+		showPreviewDialog(window, store, switchPlan, "profile", func() {
+			refresh()
+			dialog.ShowInformation("Switch Profile", fmt.Sprintf("Switched to profile %s", p.Name), window)
+		})
+	})
+
+	saveBtn := widget.NewButton("Save As...", func() {
+		nameEntry := widget.NewEntry()
+		nameEntry.SetText(profileSelect.Selected)
+		dialog.ShowForm("Save Profile", "Save", "Cancel",
+			[]*widget.FormItem{widget.NewFormItem("Name", nameEntry)},
+			func(save bool) {
+				name := strings.TrimSpace(nameEntry.Text)
+				if !save || name == "" {
+					return
+				}
+				captured, _, err := profile.Capture(store, cfg.Programs, nil)
+				if err != nil {
+					dialog.ShowError(err, window)
+					return
+				}
+				if len(captured) == 0 {
+					dialog.ShowInformation("Save Profile", "No configured tools to save; configure them first.", window)
+					return
+				}
+				if err := cfg.PutProfile(config.Profile{Name: name, Tools: captured}); err != nil {
+					dialog.ShowError(err, window)
+					return
+				}
+				refresh()
+				dialog.ShowInformation("Save Profile", fmt.Sprintf("Profile %s saved with %d tool(s)", name, len(captured)), window)
+			}, window)
+	})
+
+	deleteBtn := widget.NewButton("Delete", func() {
+		name := profileSelect.Selected
+		if name == "" {
+			return
+		}
+		dialog.ShowConfirm("Delete Profile", fmt.Sprintf("Delete profile %s?", name), func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			if _, err := cfg.DeleteProfile(name); err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			refresh()
+		}, window)
+	})
+
+	refresh()
+	return container.NewBorder(nil, nil, widget.NewLabel("Profile:"),
+		container.NewHBox(switchBtn, saveBtn, deleteBtn), profileSelect)
+}
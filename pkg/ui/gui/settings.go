@@ -0,0 +1,210 @@
+package gui
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+
+	"devpathpro/pkg/config"
+	"devpathpro/pkg/registry"
+	"devpathpro/pkg/ui"
+)
+
+// defaultScopeOption is shown for an empty default scope
+const defaultScopeOption = "per tool"
+
+// newSettingsView edits the settings file of cfg. Saving writes the file and
+// applies the settings right away. Both GUI front ends use it.
+func newSettingsView(window fyne.Window, cfg *config.Configuration) fyne.CanvasObject {
+	settings := cfg.Settings
+	if settings == nil {
+		settings = &config.Settings{}
+	}
+
+	scopeSelect := widget.NewSelect(append([]string{defaultScopeOption}, scopeOptions...), nil)
+	scopeSelect.SetSelected(defaultScopeOption)
+	if settings.DefaultScope != "" {
+		scopeSelect.SetSelected(string(settings.DefaultScope))
+	}
+
+	backupDirEntry := widget.NewEntry()
+	backupDirEntry.SetPlaceHolder("default")
+	backupDirEntry.SetText(settings.BackupDir)
+
+	keepEntry := widget.NewEntry()
+	keepEntry.SetPlaceHolder("0 (keep all)")
+	if settings.BackupKeep > 0 {
+		keepEntry.SetText(strconv.Itoa(settings.BackupKeep))
+	}
+
+	maxAgeEntry := widget.NewEntry()
+	maxAgeEntry.SetPlaceHolder("e.g. 720h (empty: keep forever)")
+	maxAgeEntry.SetText(settings.BackupMaxAge)
+
+	compressCheck := widget.NewCheck("Bundle each backup into a zip archive", nil)
+	compressCheck.SetChecked(settings.BackupCompress)
+
+	logLevelSelect := widget.NewSelect([]string{"debug", "info", "warn", "error", "off"}, nil)
+	logLevelSelect.SetSelected("info")
+	if settings.LogLevel != "" {
+		logLevelSelect.SetSelected(strings.ToLower(settings.LogLevel))
+	}
+
+	excludedEntry := widget.NewMultiLineEntry()
+	excludedEntry.SetPlaceHolder("One directory name or full path per line")
+	excludedEntry.SetText(strings.Join(settings.ExcludedSearchDirs, "\n"))
+
+	drivesEntry := widget.NewEntry()
+	drivesEntry.SetPlaceHolder("e.g. C, D (empty: all drives)")
+	drivesEntry.SetText(strings.Join(settings.DeepSearchDrives, ", "))
+
+	catalogDirEntry := widget.NewEntry()
+	catalogDirEntry.SetPlaceHolder(config.DefaultCatalogDir())
+	catalogDirEntry.SetText(settings.CatalogDir)
+
+	optionsEntry := widget.NewMultiLineEntry()
+	optionsEntry.SetPlaceHolder("One tool per line, e.g. Python: Basic, Pip")
+	optionsEntry.SetText(formatDefaultOptions(settings.DefaultOptions))
+
+	programsEntry := widget.NewMultiLineEntry()
+	programsEntry.SetPlaceHolder(`[{"name": "...", "executableName": "...", "commonPaths": ["..."], "category": "..."}]`)
+	if len(settings.CustomPrograms) > 0 {
+		data, _ := json.MarshalIndent(settings.CustomPrograms, "", "  ")
+		programsEntry.SetText(string(data))
+	}
+
+	saveBtn := widget.NewButton("Save", func() {
+		// Start from the current settings so fields without an editor are kept
+		updated := *settings
+		updated.DefaultScope = ""
+		if scopeSelect.Selected != defaultScopeOption {
+			updated.DefaultScope = registry.Scope(scopeSelect.Selected)
+		}
+		updated.BackupDir = strings.TrimSpace(backupDirEntry.Text)
+		updated.BackupKeep = 0
+		updated.BackupMaxAge = strings.TrimSpace(maxAgeEntry.Text)
+		updated.BackupCompress = compressCheck.Checked
+		updated.LogLevel = logLevelSelect.Selected
+		updated.ExcludedSearchDirs = splitLines(excludedEntry.Text)
+		updated.DeepSearchDrives = splitComma(drivesEntry.Text)
+		updated.CatalogDir = strings.TrimSpace(catalogDirEntry.Text)
+		if text := strings.TrimSpace(keepEntry.Text); text != "" {
+			keep, err := strconv.Atoi(text)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("backups to keep: %q is not a number", text), window)
+				return
+			}
+			updated.BackupKeep = keep
+		}
+		options, err := parseDefaultOptions(optionsEntry.Text)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		updated.DefaultOptions = options
+		updated.CustomPrograms = nil
+		if text := strings.TrimSpace(programsEntry.Text); text != "" {
+			if err := json.Unmarshal([]byte(text), &updated.CustomPrograms); err != nil {
+				dialog.ShowError(fmt.Errorf("custom programs: %v", err), window)
+				return
+			}
+		}
+
+		if err := updated.Validate(); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		// Catalog errors are reported before anything is saved
+		programs, err := config.LoadPrograms(&updated)
+		if err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		if err := config.SaveSettings(cfg.SettingsPath, &updated); err != nil {
+			dialog.ShowError(err, window)
+			return
+		}
+		ui.ApplySettings(&updated)
+		settings = &updated
+		cfg.Settings = settings
+		cfg.Programs = programs
+		dialog.ShowInformation("Settings",
+			fmt.Sprintf("Settings saved to %s.\nChanges to the program list take effect after a restart.", cfg.SettingsPath),
+			window)
+	})
+
+	form := widget.NewForm(
+		widget.NewFormItem("Default scope", scopeSelect),
+		widget.NewFormItem("Backup directory", backupDirEntry),
+		widget.NewFormItem("Backups to keep", keepEntry),
+		widget.NewFormItem("Backup max age", maxAgeEntry),
+		widget.NewFormItem("", compressCheck),
+		widget.NewFormItem("Log level", logLevelSelect),
+		widget.NewFormItem("Excluded search dirs", excludedEntry),
+		widget.NewFormItem("Deep search drives", drivesEntry),
+		widget.NewFormItem("Catalog directory", catalogDirEntry),
+		widget.NewFormItem("Default options", optionsEntry),
+		widget.NewFormItem("Custom programs (JSON)", programsEntry),
+	)
+	return container.NewBorder(nil, saveBtn, nil, nil, container.NewScroll(form))
+}
+
+// formatDefaultOptions renders default option groups as "Tool: A, B" lines
+func formatDefaultOptions(options map[string][]string) string {
+	tools := make([]string, 0, len(options))
+	for tool := range options {
+		tools = append(tools, tool)
+	}
+	sort.Strings(tools)
+
+	lines := make([]string, len(tools))
+	for i, tool := range tools {
+		lines[i] = fmt.Sprintf("%s: %s", tool, strings.Join(options[tool], ", "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseDefaultOptions parses the "Tool: A, B" lines of formatDefaultOptions
+func parseDefaultOptions(text string) (map[string][]string, error) {
+	options := make(map[string][]string)
+	for _, line := range splitLines(text) {
+		tool, groups, ok := strings.Cut(line, ":")
+		if !ok || strings.TrimSpace(tool) == "" {
+			return nil, fmt.Errorf("default options: expected \"Tool: Option, ...\", got %q", line)
+		}
+		options[strings.TrimSpace(tool)] = splitComma(groups)
+	}
+	if len(options) == 0 {
+		return nil, nil
+	}
+	return options, nil
+}
+
+// splitLines returns the non-empty trimmed lines of text
+func splitLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// splitComma returns the non-empty trimmed items of a comma-separated list
+func splitComma(text string) []string {
+	var items []string
+	for _, item := range strings.Split(text, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -10,13 +10,16 @@ import (
 	"strings"
 
 	"devpathpro/pkg/config"
+	"devpathpro/pkg/plan"
 	"devpathpro/pkg/tools"
 	"devpathpro/pkg/utils"
 	"devpathpro/pkg/backup"
+	"devpathpro/pkg/registry"
 )
 
-// MainMenu displays and handles the main menu
-func MainMenu(cfg *config.Configuration) {
+// MainMenu displays and handles the main menu. An empty scope
+// configures every tool in its default scope.
+func MainMenu(cfg *config.Configuration, store registry.EnvStore, scope registry.Scope) {
 	reader := bufio.NewReader(os.Stdin)
 
 	for {
@@ -38,13 +41,13 @@ func MainMenu(cfg *config.Configuration) {
 
 		switch input {
 		case "1":
-			SearchToolsMenu(cfg)
+			SearchToolsMenu(cfg, store, scope)
 		case "2":
-			VerifyConfigMenu()
+			VerifyConfigMenu(store)
 		case "3":
 			ViewEnvironmentMenu()
 		case "4":
-			ManageBackupsMenu()
+			ManageBackupsMenu(store)
 		case "5":
 			continue
 		case "6":
@@ -60,7 +63,7 @@ func MainMenu(cfg *config.Configuration) {
 }
 
 // SearchToolsMenu displays the tool search menu
-func SearchToolsMenu(cfg *config.Configuration) {
+func SearchToolsMenu(cfg *config.Configuration, store registry.EnvStore, scope registry.Scope) {
 	utils.ClearScreen()
 	fmt.Println("\nAvailable Development Tools:")
 	utils.PrintDivider("-", 80)
@@ -91,7 +94,7 @@ func SearchToolsMenu(cfg *config.Configuration) {
 
 		fmt.Printf("\n%s:\n", category)
 		for _, prog := range programs {
-			fmt.Printf("[%3d] %-30s (%s)\n", currentNumber, prog.Name, prog.ExecutableName)
+			fmt.Printf("[%3d] %-30s (%s)\n", currentNumber, prog.Name, prog.Executable())
 			numberedPrograms = append(numberedPrograms, prog)
 			currentNumber++
 		}
@@ -158,11 +161,11 @@ func SearchToolsMenu(cfg *config.Configuration) {
 	}
 
 	// Process selected programs
-	ProcessSelectedTools(selectedPrograms)
+	ProcessSelectedTools(store, scope, selectedPrograms)
 }
 
 // VerifyConfigMenu displays the configuration verification menu
-func VerifyConfigMenu() {
+func VerifyConfigMenu(store registry.EnvStore) {
 	fmt.Println("\nVerifying system configuration...")
 	issues := config.VerifyConfigurations()
 
@@ -204,6 +207,16 @@ func VerifyConfigMenu() {
 		fmt.Println()
 	}
 
+	if p, err := config.FixPlan(store, issues); err != nil {
+		fmt.Printf("❌ Error planning fixes: %v\n", err)
+	} else if !p.Empty() {
+		if changes, err := p.Diff(store); err == nil {
+			fmt.Println("The fixes change these variables:")
+			plan.PrintDiff(os.Stdout, changes)
+			fmt.Println()
+		}
+	}
+
 	// Ask user if they want to fix issues
 	reader := bufio.NewReader(os.Stdin)
 	fmt.Print("Would you like to attempt to fix these issues automatically? (y/n): ")
@@ -212,7 +225,7 @@ func VerifyConfigMenu() {
 
 	if answer == "y" || answer == "yes" {
 		fmt.Println("\nAttempting to fix detected issues...")
-		if err := config.FixConfigurationIssues(issues); err != nil {
+		if err := config.FixConfigurationIssues(store, issues); err != nil {
 			fmt.Printf("❌ Error fixing issues: %v\n", err)
 		} else {
 			fmt.Println("✅ Fixes applied. Please restart the program for changes to take effect.")
@@ -242,7 +255,7 @@ func ViewEnvironmentMenu() {
 }
 
 // ProcessSelectedTools processes the selected tools
-func ProcessSelectedTools(programs []config.Program) {
+func ProcessSelectedTools(store registry.EnvStore, scope registry.Scope, programs []config.Program) {
 	utils.ClearScreen()
 	fmt.Printf("\nSelected tools: ")
 	for i, prog := range programs {
@@ -251,10 +264,14 @@ func ProcessSelectedTools(programs []config.Program) {
 		}
 		fmt.Print(prog.Name)
 	}
-	fmt.Println("\n")
+	fmt.Print("\n\n")
 
 	// Create backup before making any changes
-	if err := backup.CreateBackup(); err != nil {
+	names := make([]string, len(programs))
+	for i, prog := range programs {
+		names[i] = prog.Name
+	}
+	if _, err := backup.CreateBackup(store, backup.Metadata{Action: "configure", Tools: names, Scope: scope}); err != nil {
 		fmt.Printf("Warning: Failed to create backup: %v\n", err)
 	}
 
@@ -272,20 +289,21 @@ func ProcessSelectedTools(programs []config.Program) {
 			continue
 		}
 
+		versions := tools.DetectVersions(prog, paths)
 		fmt.Printf("✅ Found in:\n")
 		for _, path := range paths {
-			fmt.Printf("  - %s\n", path)
+			fmt.Printf("  - %s\n", tools.DescribePath(path, versions[path]))
 		}
 
 		// Let user select path if multiple found
-		selectedPath, err := tools.SelectPath(paths, prog.Name)
+		selectedPath, err := tools.SelectPath(prog, paths, versions)
 		if err != nil {
 			fmt.Printf("⚠️ Error selecting path: %v\n", err)
 			continue
 		}
 
 		// Configure selected path
-		if err := tools.ConfigureSelectedPath(prog, selectedPath); err != nil {
+		if err := configureTool(store, scope, prog, selectedPath); err != nil {
 			fmt.Printf("⚠️ Configuration error: %v\n", err)
 		} else {
 			fmt.Printf("✅ Successfully configured using: %s\n", selectedPath)
@@ -317,7 +335,7 @@ func ProcessSelectedTools(programs []config.Program) {
 				
 				// Search in each drive
 				for _, drive := range drives {
-					go tools.SearchInDrive(drive, prog.ExecutableName, resultChan)
+					go tools.SearchInDrive(drive, prog.Executable(), resultChan)
 				}
 				
 				// Collect results
@@ -333,20 +351,21 @@ func ProcessSelectedTools(programs []config.Program) {
 				
 				if len(paths) > 0 {
 					fmt.Printf("\n=== %s ===\n", prog.Name)
+					versions := tools.DetectVersions(prog, paths)
 					fmt.Printf("✅ Found in:\n")
 					for _, path := range paths {
-						fmt.Printf("  - %s\n", path)
+						fmt.Printf("  - %s\n", tools.DescribePath(path, versions[path]))
 					}
 					
 					// Let user select path
-					selectedPath, err := tools.SelectPath(paths, prog.Name)
+					selectedPath, err := tools.SelectPath(prog, paths, versions)
 					if err != nil {
 						fmt.Printf("⚠️ Error selecting path: %v\n", err)
 						continue
 					}
 					
 					// Configure selected path
-					if err := tools.ConfigureSelectedPath(prog, selectedPath); err != nil {
+					if err := configureTool(store, scope, prog, selectedPath); err != nil {
 						fmt.Printf("⚠️ Configuration error: %v\n", err)
 					} else {
 						fmt.Printf("✅ Successfully configured using: %s\n", selectedPath)
@@ -377,8 +396,18 @@ func ProcessSelectedTools(programs []config.Program) {
 	bufio.NewReader(os.Stdin).ReadString('\n')
 }
 
+// configureTool configures a program in its resolved scope, refusing
+// machine scope writes up front when the process is not elevated
+func configureTool(store registry.EnvStore, scope registry.Scope, prog config.Program, path string) error {
+	scope = tools.ResolveScope(prog, scope)
+	if scope.RequiresAdmin() && !registry.IsAdmin() {
+		return fmt.Errorf("%s scope requires administrator privileges", scope)
+	}
+	return tools.ConfigureProgram(store, scope, prog, path, nil)
+}
+
 // ManageBackupsMenu displays the backup management menu
-func ManageBackupsMenu() {
+func ManageBackupsMenu(store registry.EnvStore) {
 	for {
 		utils.ClearScreen()
 		fmt.Println("\nBackup Management")
@@ -396,10 +425,10 @@ func ManageBackupsMenu() {
 
 		switch input {
 		case "1":
-			if err := backup.CreateBackup(); err != nil {
+			if info, err := backup.CreateBackup(store, backup.Metadata{Action: "manual"}); err != nil {
 				fmt.Printf("\n❌ Failed to create backup: %v\n", err)
 			} else {
-				fmt.Println("\n✅ Backup created successfully!")
+				fmt.Printf("\n✅ Backup %s created successfully!\n", info.ID)
 			}
 			
 		case "2":
@@ -408,8 +437,8 @@ func ManageBackupsMenu() {
 				fmt.Printf("\n❌ Failed to list backups: %v\n", err)
 			} else {
 				fmt.Println("\nAvailable backups:")
-				for i, timestamp := range backups {
-					fmt.Printf("[%d] %s\n", i+1, timestamp)
+				for i, info := range backups {
+					fmt.Printf("[%d] %s\n", i+1, info)
 				}
 			}
 			
@@ -426,8 +455,8 @@ func ManageBackupsMenu() {
 			}
 			
 			fmt.Println("\nAvailable backups:")
-			for i, timestamp := range backups {
-				fmt.Printf("[%d] %s\n", i+1, timestamp)
+			for i, info := range backups {
+				fmt.Printf("[%d] %s\n", i+1, info)
 			}
 			
 			fmt.Print("\nSelect backup to restore (enter number): ")
@@ -435,11 +464,7 @@ func ManageBackupsMenu() {
 			input = strings.TrimSpace(input)
 			
 			if num, err := strconv.Atoi(input); err == nil && num > 0 && num <= len(backups) {
-				if err := backup.RestoreBackup(backups[num-1]); err != nil {
-					fmt.Printf("\n❌ Failed to restore backup: %v\n", err)
-				} else {
-					fmt.Println("\n✅ Backup restored successfully!")
-				}
+				restoreBackupMenu(store, reader, backups[num-1].ID)
 			} else {
 				fmt.Println("\n❌ Invalid selection.")
 			}
@@ -454,4 +479,62 @@ func ManageBackupsMenu() {
 		fmt.Print("\nPress Enter to continue...")
 		reader.ReadString('\n')
 	}
-} 
\ No newline at end of file
+} 
+
+// restoreBackupMenu asks what part of a backup to restore, previews
+// the changes and restores them after confirmation
+func restoreBackupMenu(store registry.EnvStore, reader *bufio.Reader, timestamp string) {
+	fmt.Println("\nWhat would you like to restore?")
+	fmt.Println("1. Everything")
+	fmt.Println("2. Machine variables only")
+	fmt.Println("3. User variables only")
+	fmt.Println("4. PATH only")
+	fmt.Println("5. A single variable")
+	fmt.Print("\nSelect an option (1-5): ")
+	input, _ := reader.ReadString('\n')
+
+	var opts backup.RestoreOptions
+	switch strings.TrimSpace(input) {
+	case "1":
+	case "2":
+		opts.Scope = registry.ScopeMachine
+	case "3":
+		opts.Scope = registry.ScopeUser
+	case "4":
+		opts.PathOnly = true
+	case "5":
+		fmt.Print("Variable name: ")
+		name, _ := reader.ReadString('\n')
+		opts.Variable = strings.TrimSpace(name)
+		if opts.Variable == "" {
+			fmt.Println("\n❌ No variable given.")
+			return
+		}
+	default:
+		fmt.Println("\n❌ Invalid selection.")
+		return
+	}
+
+	changes, err := backup.PreviewRestore(store, timestamp, opts)
+	if err != nil {
+		fmt.Printf("\n❌ Failed to read backup: %v\n", err)
+		return
+	}
+	fmt.Println("\nRestoring would make these changes:")
+	fmt.Print(backup.FormatChanges(changes))
+	if len(changes) == 0 {
+		return
+	}
+
+	fmt.Print("\nRestore? (y/n): ")
+	input, _ = reader.ReadString('\n')
+	if strings.TrimSpace(strings.ToLower(input)) != "y" {
+		return
+	}
+
+	if err := backup.RestoreBackup(store, timestamp, opts); err != nil {
+		fmt.Printf("\n❌ Failed to restore backup: %v\n", err)
+	} else {
+		fmt.Println("\n✅ Backup restored successfully!")
+	}
+}
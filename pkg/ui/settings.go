@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"devpathpro/pkg/backup"
+	"devpathpro/pkg/config"
+	"devpathpro/pkg/tools"
+	"devpathpro/pkg/utils"
+)
+
+// ApplySettings configures the backup, search, verify, shell and logging packages from settings.
+// The settings are expected to be valid.
+func ApplySettings(settings *config.Settings) {
+	maxAge, _ := settings.BackupAge()
+	backup.Configure(backup.Options{
+		Dir:      settings.BackupDir,
+		MaxCount: settings.BackupKeep,
+		MaxAge:   maxAge,
+		Compress: settings.BackupCompress,
+	})
+
+	tools.Configure(tools.SearchOptions{
+		ExcludedDirs: settings.ExcludedSearchDirs,
+		Drives:       settings.DeepSearchDrives,
+	})
+
+	config.ConfigureChecks(settings.Checks)
+	config.ConfigureShells(settings.Shells, settings.ShellProfiles)
+
+	level, _ := utils.ParseLogLevel(settings.LogLevel)
+	utils.SetLogLevel(level)
+}
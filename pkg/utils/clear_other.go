@@ -0,0 +1,11 @@
+//go:build !windows
+
+package utils
+
+import "fmt"
+
+// ClearScreen clears the console screen
+func ClearScreen() error {
+	_, err := fmt.Print("\033[H\033[2J")
+	return err
+}
@@ -0,0 +1,15 @@
+//go:build windows
+
+package utils
+
+import (
+	"os"
+	"os/exec"
+)
+
+// ClearScreen clears the console screen
+func ClearScreen() error {
+	cmd := exec.Command("cmd", "/c", "cls")
+	cmd.Stdout = os.Stdout
+	return cmd.Run()
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// LogLevel is the minimum severity written to the log
+type LogLevel int
+
+const (
+	LevelDebug LogLevel = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+	LevelOff
+)
+
+var logLevelNames = map[string]LogLevel{
+	"debug": LevelDebug,
+	"info":  LevelInfo,
+	"warn":  LevelWarn,
+	"error": LevelError,
+	"off":   LevelOff,
+}
+
+// logLevel is the level messages are filtered by
+var logLevel = LevelInfo
+
+// ParseLogLevel converts debug, info, warn, error or off to a LogLevel.
+// An empty string is the default level, info.
+func ParseLogLevel(value string) (LogLevel, error) {
+	if value == "" {
+		return LevelInfo, nil
+	}
+	if level, ok := logLevelNames[strings.ToLower(value)]; ok {
+		return level, nil
+	}
+	return LevelInfo, fmt.Errorf("invalid log level %q: expected debug, info, warn, error or off", value)
+}
+
+// SetLogLevel sets the minimum severity written to the log
+func SetLogLevel(level LogLevel) {
+	logLevel = level
+}
+
+// Debugf logs a debug message
+func Debugf(format string, args ...interface{}) {
+	logf(LevelDebug, "DEBUG", format, args...)
+}
+
+// Infof logs an informational message
+func Infof(format string, args ...interface{}) {
+	logf(LevelInfo, "INFO", format, args...)
+}
+
+// Warnf logs a warning
+func Warnf(format string, args ...interface{}) {
+	logf(LevelWarn, "WARN", format, args...)
+}
+
+// Errorf logs an error
+func Errorf(format string, args ...interface{}) {
+	logf(LevelError, "ERROR", format, args...)
+}
+
+func logf(level LogLevel, prefix, format string, args ...interface{}) {
+	if level < logLevel {
+		return
+	}
+	log.Printf(prefix+": "+format, args...)
+}
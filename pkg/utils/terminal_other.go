@@ -0,0 +1,16 @@
+//go:build !windows
+
+package utils
+
+import "os"
+
+// IsTerminal reports whether f is an interactive console rather than a pipe or a file
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	// The null device is a character device too, but nobody answers from it
+	null, err := os.Stat(os.DevNull)
+	return err != nil || !os.SameFile(info, null)
+}
@@ -0,0 +1,135 @@
+package version
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Constraint is a set of version requirements such as ">=17 <22", "^3.11",
+// "~1.21" or "18 || 20". Space or comma separated requirements must all hold;
+// alternatives are separated by "||". A bare version matches as a prefix,
+// so "17" matches 17.0.2 but not 18.
+type Constraint struct {
+	text         string
+	alternatives [][]comparator
+}
+
+// comparator is a single requirement of a constraint
+type comparator struct {
+	op      string
+	version Version
+}
+
+// ParseConstraint parses a version constraint
+func ParseConstraint(text string) (*Constraint, error) {
+	c := &Constraint{text: strings.TrimSpace(text)}
+	if c.text == "" {
+		return nil, fmt.Errorf("empty version constraint")
+	}
+	for _, alternative := range strings.Split(c.text, "||") {
+		fields := strings.FieldsFunc(alternative, func(r rune) bool { return r == ' ' || r == ',' })
+		if len(fields) == 0 {
+			return nil, fmt.Errorf("invalid version constraint %q: empty alternative", text)
+		}
+		var group []comparator
+		for i := 0; i < len(fields); i++ {
+			field := fields[i]
+			// Allow a space between operator and version, as in ">= 17"
+			if strings.TrimLeft(field, "<>=!^~") == "" && i+1 < len(fields) {
+				i++
+				field += fields[i]
+			}
+			cmp, err := parseComparator(field)
+			if err != nil {
+				return nil, fmt.Errorf("invalid version constraint %q: %v", text, err)
+			}
+			group = append(group, cmp)
+		}
+		c.alternatives = append(c.alternatives, group)
+	}
+	return c, nil
+}
+
+// parseComparator parses one requirement such as ">=17" or "1.21.x"
+func parseComparator(text string) (comparator, error) {
+	op := ""
+	for _, candidate := range []string{">=", "<=", "!=", ">", "<", "=", "^", "~"} {
+		if strings.HasPrefix(text, candidate) {
+			op = candidate
+			break
+		}
+	}
+	rest := strings.TrimPrefix(text, op)
+	for _, wildcard := range []string{".x", ".X", ".*"} {
+		for strings.HasSuffix(rest, wildcard) {
+			rest = strings.TrimSuffix(rest, wildcard)
+		}
+	}
+	v, err := Parse(rest)
+	if err != nil {
+		return comparator{}, err
+	}
+	if op == "" {
+		op = "prefix"
+	}
+	return comparator{op: op, version: v}, nil
+}
+
+// String returns the constraint as it was written
+func (c *Constraint) String() string {
+	return c.text
+}
+
+// Check reports whether v satisfies the constraint
+func (c *Constraint) Check(v Version) bool {
+	for _, group := range c.alternatives {
+		ok := true
+		for _, cmp := range group {
+			if !cmp.check(v) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (c comparator) check(v Version) bool {
+	cmp := v.Compare(c.version)
+	switch c.op {
+	case ">=":
+		return cmp >= 0
+	case "<=":
+		return cmp <= 0 || v.hasPrefix(c.version)
+	case ">":
+		return cmp > 0 && !v.hasPrefix(c.version)
+	case "<":
+		return cmp < 0
+	case "!=":
+		return !v.hasPrefix(c.version)
+	case "=", "prefix":
+		return v.hasPrefix(c.version)
+	case "^":
+		// Compatible releases: same major, or same minor below 1.0
+		if cmp < 0 {
+			return false
+		}
+		if c.version.Major() == 0 {
+			return v.Major() == 0 && v.component(1) == c.version.component(1)
+		}
+		return v.Major() == c.version.Major()
+	case "~":
+		// Patch releases: same major and minor, or same major if no minor was given
+		if cmp < 0 {
+			return false
+		}
+		if len(c.version.Numbers) < 2 {
+			return v.Major() == c.version.Major()
+		}
+		return v.Major() == c.version.Major() && v.component(1) == c.version.component(1)
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package version
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Version is a dotted version number as reported by development tools, such
+// as 3.12.1, 17.0.2+8, 1.8.0_392 or 1.22rc1
+type Version struct {
+	// Numbers are the dotted numeric components; missing ones count as 0
+	Numbers []int
+	// Pre is a pre-release suffix such as "rc1"; a pre-release sorts before the release
+	Pre string
+	// Original is the text the version was parsed from
+	Original string
+}
+
+var versionPattern = regexp.MustCompile(`^v?(\d+(?:\.\d+)*)(?:[-.]?([A-Za-z][0-9A-Za-z.]*))?(?:[+_].*)?$`)
+
+// Parse parses a version; a leading "v" is ignored
+func Parse(text string) (Version, error) {
+	m := versionPattern.FindStringSubmatch(strings.TrimSpace(text))
+	if m == nil {
+		return Version{}, fmt.Errorf("invalid version %q", text)
+	}
+	v := Version{Pre: m[2], Original: text}
+	for _, part := range strings.Split(m[1], ".") {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %q", text)
+		}
+		v.Numbers = append(v.Numbers, n)
+	}
+	return v, nil
+}
+
+// Major returns the first component of the version
+func (v Version) Major() int {
+	return v.component(0)
+}
+
+// String returns the version as it was parsed
+func (v Version) String() string {
+	return v.Original
+}
+
+// Compare returns -1, 0 or 1 if v is lower than, equal to or higher than o
+func (v Version) Compare(o Version) int {
+	n := len(v.Numbers)
+	if len(o.Numbers) > n {
+		n = len(o.Numbers)
+	}
+	for i := 0; i < n; i++ {
+		if a, b := v.component(i), o.component(i); a != b {
+			if a < b {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case v.Pre == o.Pre:
+		return 0
+	case v.Pre == "":
+		return 1
+	case o.Pre == "":
+		return -1
+	case v.Pre < o.Pre:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// component returns the i-th number of the version, 0 if it has fewer numbers
+func (v Version) component(i int) int {
+	if i < len(v.Numbers) {
+		return v.Numbers[i]
+	}
+	return 0
+}
+
+// hasPrefix reports whether v starts with the numbers of prefix, so that
+// 17.0.2 has the prefix 17 and 17.0
+func (v Version) hasPrefix(prefix Version) bool {
+	for i, n := range prefix.Numbers {
+		if v.component(i) != n {
+			return false
+		}
+	}
+	return prefix.Pre == "" || prefix.Pre == v.Pre
+}
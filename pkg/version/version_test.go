@@ -0,0 +1,137 @@
+package version
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		text    string
+		numbers []int
+		pre     string
+		wantErr bool
+	}{
+		{text: "3.12.1", numbers: []int{3, 12, 1}},
+		{text: "v1.22.0", numbers: []int{1, 22, 0}},
+		{text: "17.0.2+8", numbers: []int{17, 0, 2}},
+		{text: "1.8.0_392", numbers: []int{1, 8, 0}},
+		{text: "1.22rc1", numbers: []int{1, 22}, pre: "rc1"},
+		{text: "2.0.0-beta.2", numbers: []int{2, 0, 0}, pre: "beta.2"},
+		{text: " 21 ", numbers: []int{21}},
+		{text: "", wantErr: true},
+		{text: "java", wantErr: true},
+		{text: "1..2", wantErr: true},
+	}
+	for _, tt := range tests {
+		v, err := Parse(tt.text)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Parse(%q) = %v, want an error", tt.text, v.Numbers)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.text, err)
+			continue
+		}
+		if !equalInts(v.Numbers, tt.numbers) || v.Pre != tt.pre {
+			t.Errorf("Parse(%q) = %v %q, want %v %q", tt.text, v.Numbers, v.Pre, tt.numbers, tt.pre)
+		}
+		if v.String() != tt.text {
+			t.Errorf("Parse(%q).String() = %q", tt.text, v.String())
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"1.2", "1.2.0", 0},
+		{"1.10", "1.9", 1},
+		{"17.0.2", "21", -1},
+		{"1.22rc1", "1.22", -1},
+		{"1.22", "1.22rc1", 1},
+		{"1.22rc1", "1.22rc2", -1},
+		{"1.8.0_392", "1.8.0_400", 0},
+	}
+	for _, tt := range tests {
+		if got := mustParse(t, tt.a).Compare(mustParse(t, tt.b)); got != tt.want {
+			t.Errorf("Compare(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestConstraintCheck(t *testing.T) {
+	tests := []struct {
+		constraint string
+		version    string
+		want       bool
+	}{
+		{"17", "17.0.2", true},
+		{"17", "18", false},
+		{"17", "1.7", false},
+		{">=17 <22", "21.0.1", true},
+		{">=17 <22", "22", false},
+		{">=17, <22", "16.0.2", false},
+		{">= 17", "17", true},
+		{"<=17", "17.0.9", true},
+		{"<=17", "18", false},
+		{">17", "17.0.9", false},
+		{">17", "18", true},
+		{"!=17", "17.0.2", false},
+		{"!=17", "21", true},
+		{"=3.11", "3.11.4", true},
+		{"^3.11", "3.12.0", true},
+		{"^3.11", "4.0", false},
+		{"^3.11", "3.10", false},
+		{"^0.4", "0.4.9", true},
+		{"^0.4", "0.5.0", false},
+		{"~1.21", "1.21.5", true},
+		{"~1.21", "1.22.0", false},
+		{"~1", "1.9", true},
+		{"1.21.x", "1.21.3", true},
+		{"1.21.*", "1.22", false},
+		{"18 || 20", "20.11.0", true},
+		{"18 || 20", "19.0", false},
+	}
+	for _, tt := range tests {
+		c, err := ParseConstraint(tt.constraint)
+		if err != nil {
+			t.Errorf("ParseConstraint(%q): %v", tt.constraint, err)
+			continue
+		}
+		if got := c.Check(mustParse(t, tt.version)); got != tt.want {
+			t.Errorf("%q.Check(%s) = %v, want %v", tt.constraint, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestParseConstraintErrors(t *testing.T) {
+	for _, text := range []string{"", "  ", "17 ||", ">=java", "<"} {
+		if _, err := ParseConstraint(text); err == nil {
+			t.Errorf("ParseConstraint(%q) succeeded, want an error", text)
+		}
+	}
+}
+
+func mustParse(t *testing.T, text string) Version {
+	t.Helper()
+	v, err := Parse(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}